@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+func (a *App) anonymizeEnabled(settings *Settings) bool {
+	return a.Config.Anonymize || settings.Anonymize
+}
+
+// locationAliases names the scanned locations "dump", or "dump 1", "dump 2"
+// and so on when several were scanned, so local paths aren't shared.
+func locationAliases(report *Report) map[string]string {
+	locations := strings.Split(report.DumpLocation, ", ")
+	aliases := make(map[string]string, len(locations))
+	for i, location := range locations {
+		if location == "" || location == stdinLocation {
+			continue
+		}
+		if len(locations) == 1 {
+			aliases[location] = "dump"
+		} else {
+			aliases[location] = fmt.Sprintf("dump %d", i+1)
+		}
+	}
+	return aliases
+}
+
+// anonymizeText replaces the scanned locations with their aliases and the
+// home folder with "~".
+func anonymizeText(text string, aliases map[string]string) string {
+	locations := make([]string, 0, len(aliases))
+	for location := range aliases {
+		locations = append(locations, location)
+	}
+	// Longest first, so a location inside another one is replaced whole
+	sort.Slice(locations, func(i, j int) bool {
+		return len(locations[i]) > len(locations[j])
+	})
+	for _, location := range locations {
+		text = strings.ReplaceAll(text, location, aliases[location])
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		text = strings.ReplaceAll(text, home, "~")
+	}
+	return text
+}
+
+// anonymizeReport returns a copy of a report without local paths, the
+// console's serial, MAC address and HDD key, and save names, which can hold
+// gamertags.
+func (a *App) anonymizeReport(report *Report) *Report {
+	aliases := locationAliases(report)
+	anonymized := *report
+	anonymized.DumpLocation = anonymizeText(report.DumpLocation, aliases)
+
+	anonymized.Findings = make([]Finding, len(report.Findings))
+	for i, f := range report.Findings {
+		f.Path = anonymizeText(f.Path, aliases)
+		f.Location = anonymizeText(f.Location, aliases)
+		also := make([]string, len(f.Also))
+		for j, p := range f.Also {
+			also[j] = anonymizeText(p, aliases)
+		}
+		f.Also = also
+		if f.Kind == kindSave {
+			f.Name = a.wantedSaveName(f)
+		}
+		anonymized.Findings[i] = f
+	}
+
+	anonymized.Errors = make([]string, len(report.Errors))
+	for i, message := range report.Errors {
+		anonymized.Errors[i] = anonymizeText(message, aliases)
+	}
+
+	anonymized.Skipped = make([]string, len(report.Skipped))
+	for i, message := range report.Skipped {
+		anonymized.Skipped[i] = anonymizeText(message, aliases)
+	}
+
+	anonymized.UnknownTitles = make([]UnknownTitle, len(report.UnknownTitles))
+	for i, u := range report.UnknownTitles {
+		u.Path = anonymizeText(u.Path, aliases)
+		u.Location = anonymizeText(u.Location, aliases)
+		anonymized.UnknownTitles[i] = u
+	}
+
+	anonymized.SystemTitles = make([]SystemTitleFolder, len(report.SystemTitles))
+	for i, s := range report.SystemTitles {
+		s.Path = anonymizeText(s.Path, aliases)
+		s.Location = anonymizeText(s.Location, aliases)
+		anonymized.SystemTitles[i] = s
+	}
+
+	if report.Console != nil {
+		anonymized.Console = &ConsoleInfo{
+			Source:        anonymizeText(report.Console.Source, aliases),
+			VideoStandard: report.Console.VideoStandard,
+			Region:        report.Console.Region,
+		}
+	}
+	return &anonymized
+}
+
+// wantedSaveName names a save finding after the database entry it matched
+// instead of the save's own name.
+func (a *App) wantedSaveName(f Finding) string {
+	for _, wanted := range a.Titles.WantedSaves[f.TitleID] {
+		if wanted.SHA1 == f.SHA1 || (wanted.SHA1 == "" && strings.HasPrefix(strings.ToLower(f.Name), strings.ToLower(wanted.Name))) {
+			if wanted.Notes != "" {
+				return wanted.Name + " (" + wanted.Notes + ")"
+			}
+			return wanted.Name
+		}
+	}
+	return "Wanted save"
+}
+
+// anonymizeOutput removes the same data from saved scan output.
+func anonymizeOutput(text string, report *Report) string {
+	text = anonymizeText(text, locationAliases(report))
+	if c := report.Console; c != nil {
+		for _, value := range []string{c.Serial, c.MAC, c.HDDKey} {
+			if value != "" {
+				text = strings.ReplaceAll(text, value, "[removed]")
+			}
+		}
+	}
+	return text
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"crypto/rsa"
+	"runtime"
+)
+
+// App is the state of a Pinecone run: its configuration, the database scans
+// are matched against and the report of the last scan. main builds one and
+// passes it to the CLI, GUI and scanner rather than keeping it in package
+// variables, so tests and concurrent scans each get their own.
+type App struct {
+	Config Config
+	Titles TitleList
+	// Report is the last scan's, see resetReport and runScan.
+	Report Report
+
+	// The optional datasets and settings loaded from the data folder when a
+	// scan starts, see scanDumpLocations. Offerings maps content IDs to
+	// marketplace listings, CommunityTitles title IDs to names of community
+	// databases and XBEPublicKey verifies XBE signatures, each empty when
+	// its file is missing. IgnoredTitles are the title IDs, wildcards
+	// allowed, never scanned: usually homebrew stored under made up IDs.
+	// IgnoredItems are the keys of findings not reported anymore, see
+	// Finding.key, marked as ignored during triage.
+	Offerings       map[string]Offering
+	CommunityTitles map[string]string
+	XBEPublicKey    *rsa.PublicKey
+	HashSizeRules   []HashSizeRule
+	IgnoredTitles   []string
+	IgnoredItems    []string
+	HashCache       HashCache
+}
+
+// Config is what a run was asked to do, set from the command line flags.
+// How files are read (-block-size, -mmap, -symlinks, -tdata-depth) and
+// printed (-quiet, -no-color and the resolved title language) stays package
+// level, it is the same for every scan of the process.
+type Config struct {
+	// DataPath is the folder of the database, settings and reports, set
+	// with -data or picked by resolveDataPath. Portable keeps it next to
+	// the executable, see portableMode.
+	DataPath string
+	Portable bool
+	// Offerings, CommunityTitles and XBEKey are datasets to use instead of
+	// the data folder's known_offerings.json, community_titles.json and
+	// xbe_public_key.bin. The XBE key is Microsoft's retail public key as
+	// dumped from the kernel's XePublicKeyData.
+	Offerings       string
+	CommunityTitles string
+	XBEKey          string
+	// Language is the -lang code title names are shown in, see
+	// resolveTitleLanguage.
+	Language string
+	// Update checks for a newer database, ForceUpdate even if it was checked
+	// recently. SignedUpdate downloads it from the project's GitHub
+	// releases, verified against a minisign signature published next to it,
+	// also set with "signedDatabase" in the settings.
+	Update       bool
+	ForceUpdate  bool
+	SignedUpdate bool
+	// Summarize prints statistics for the whole database instead of
+	// scanning.
+	Summarize bool
+	// TitleID prints statistics for a title, ScanTitleID is it normalized
+	// once the scan starts, only its folders are scanned then.
+	TitleID     string
+	ScanTitleID string
+	// FatXplorer scans FatXplorer's X: drive.
+	FatXplorer bool
+	// DumpLocation is the dump the GUI scans, DumpLocations every -location
+	// given, see scanLocations.
+	DumpLocation  string
+	DumpLocations stringList
+	// Jobs is how many title folders are checked at once. A single job
+	// suits spinning drives where parallel reads only seek.
+	Jobs int
+	// OnlyTitles and ExcludeTitles are title ID or name patterns, also set
+	// with the GUI filter chips.
+	OnlyTitles    stringList
+	ExcludeTitles stringList
+	// EEPROMPath is an eeprom.bin to use instead of one found in the dump.
+	EEPROMPath string
+	// Detectors is the comma separated list of detectors to run, "all" runs
+	// every detector. See enabledDetectors.
+	Detectors string
+	// ListSaves lists every save found in UDATA, not only wanted ones.
+	ListSaves bool
+	// Anonymize strips identifying data from exported reports, see
+	// anonymizeReport.
+	Anonymize bool
+	// Thumbnails fetches the thumbnails of DLC found, see fetchThumbnail.
+	Thumbnails bool
+	// LookupTitles looks up title IDs missing from the database online, see
+	// lookupTitleOnline.
+	LookupTitles bool
+	// GUI, Tray and TUI pick the interface, the CLI if none is set.
+	GUI  bool
+	Tray bool
+	TUI  bool
+	// HashCache remembers the SHA1 of the files hashed by a scan, so
+	// rescanning an unchanged dump reads no file in full. Also set with
+	// "hashCache" in the settings.
+	HashCache bool
+	// QuickHash checks large files against the database's "Quick Hashes"
+	// before hashing them in full, see quickCheck.
+	QuickHash bool
+	// CollectDir is where the unknown and unarchived files are copied after
+	// a CLI scan.
+	CollectDir string
+	// Triage steps through the unknown items after a CLI scan.
+	Triage bool
+	// HTMLReport and MarkdownReport are the files a CLI scan exports to.
+	HTMLReport     string
+	MarkdownReport string
+	// WebhookURL receives the results of a CLI scan, see resolveWebhookURL.
+	// WebhookOnly lists the statuses posted to it, overriding
+	// "webhookStatuses" in the settings.
+	WebhookURL  string
+	WebhookOnly string
+	// ReportTemplate is a user text/template replacing the built-in Markdown
+	// report, also set with "reportTemplate" in the settings.
+	ReportTemplate string
+	// CompileIndex compiles the database into a binary index next to it,
+	// also set with "compileIndex" in the settings. Loading the index skips
+	// parsing and validating the JSON.
+	CompileIndex bool
+}
+
+// defaultConfig is the configuration before the flags are parsed.
+func defaultConfig() Config {
+	return Config{DumpLocation: "dump", GUI: true, Jobs: min(defaultScanJobs, runtime.NumCPU())}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+func isArchive(location string) bool {
+	switch strings.ToLower(filepath.Ext(location)) {
+	case ".zip":
+		return true
+	}
+	return false
+}
+
+// checkArchiveSupported fails for archive formats recognised but not read:
+// the standard library has no 7z reader, without this a .7z would be taken
+// for a folder without TDATA.
+func checkArchiveSupported(location string) error {
+	if strings.ToLower(filepath.Ext(location)) == ".7z" {
+		return fmt.Errorf("7z is not supported, re-pack %s as .zip", filepath.Base(location))
+	}
+	return nil
+}
+
+// openArchive opens a zipped dump as a file system so it is scanned in place,
+// entries are streamed and hashed in memory without extracting them.
+func openArchive(archivePath string) (fs.FS, func() error, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error opening archive: %v", err)
+	}
+	return reader, reader.Close, nil
+}
+
+// findArchiveTDATA returns the path of the first TDATA folder in an archive,
+// dumps are often zipped together with their parent folder.
+func findArchiveTDATA(fsys fs.FS) (string, error) {
+	tdata := ""
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.EqualFold(d.Name(), "TDATA") {
+			tdata = p
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if tdata == "" {
+		return "", fmt.Errorf("TDATA folder not found in archive")
+	}
+	return tdata, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// auditDatabase looks for data entry mistakes the schema can't catch: known
+// update hashes listed under more than one title or more than once within a
+// title, and archived items that aren't referenced in the title's content IDs.
+func auditDatabase(list TitleList) []string {
+	var problems []string
+
+	hashTitles := make(map[string][]string)
+	titleIDs := make([]string, 0, len(list.Titles))
+	for titleID := range list.Titles {
+		titleIDs = append(titleIDs, titleID)
+	}
+	sort.Strings(titleIDs)
+
+	for _, titleID := range titleIDs {
+		titleData := list.Titles[titleID]
+
+		seen := make(map[string]string)
+		for _, knownUpdate := range titleData.TitleUpdatesKnown {
+			for hash, name := range knownUpdate {
+				if previous, ok := seen[hash]; ok {
+					problems = append(problems, fmt.Sprintf("Title %s (%s) lists update %s twice (%q and %q)", titleID, titleData.TitleName, hash, previous, name))
+					continue
+				}
+				seen[hash] = name
+				hashTitles[hash] = append(hashTitles[hash], titleID)
+			}
+		}
+
+		for _, archived := range titleData.Archived {
+			for contentID, name := range archived {
+				if !contains(titleData.ContentIDs, contentID) {
+					problems = append(problems, fmt.Sprintf("Title %s (%s) has archived item %s (%q) missing from its content IDs", titleID, titleData.TitleName, contentID, name))
+				}
+			}
+		}
+	}
+
+	for hash, titleIDs := range hashTitles {
+		if len(titleIDs) > 1 {
+			problems = append(problems, fmt.Sprintf("Update %s is listed under several titles: %s", hash, strings.Join(titleIDs, ", ")))
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+func (a *App) printAudit() {
+	problems := auditDatabase(a.Titles)
+	if len(problems) == 0 {
+		fmt.Println("Database audit found no problems.")
+		return
+	}
+	fmt.Printf("Database audit found %d problem(s):\n", len(problems))
+	for _, problem := range problems {
+		fmt.Println("  " + problem)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// DatabaseRow is a title as listed in the Database tab.
+type DatabaseRow struct {
+	TitleID      string
+	TitleName    string
+	Content      int
+	KnownUpdates int
+	Archived     int
+}
+
+// Completion is the share of the title's content that is archived, titles
+// without content count as complete.
+func (r DatabaseRow) Completion() float64 {
+	if r.Content == 0 {
+		return 1
+	}
+	return float64(r.Archived) / float64(r.Content)
+}
+
+// databaseColumns are the Database tab's columns, each sorting the rows by
+// what it shows.
+var databaseColumns = []struct {
+	Name  string
+	Width float32
+	Less  func(a, b DatabaseRow) bool
+}{
+	{"Name", 360, func(a, b DatabaseRow) bool { return strings.ToLower(a.TitleName) < strings.ToLower(b.TitleName) }},
+	{"Title ID", 150, func(a, b DatabaseRow) bool { return a.TitleID < b.TitleID }},
+	{"Content", 90, func(a, b DatabaseRow) bool { return a.Content < b.Content }},
+	{"Known Updates", 130, func(a, b DatabaseRow) bool { return a.KnownUpdates < b.KnownUpdates }},
+	{"Archived", 100, func(a, b DatabaseRow) bool { return a.Completion() < b.Completion() }},
+}
+
+func databaseRows(list TitleList) []DatabaseRow {
+	rows := make([]DatabaseRow, 0, len(list.Titles))
+	for titleID, titleData := range list.Titles {
+		row := DatabaseRow{
+			TitleID:      titleID,
+			TitleName:    titleData.DisplayName(),
+			Content:      len(titleData.ContentIDs),
+			KnownUpdates: len(titleData.TitleUpdatesKnown),
+		}
+		for _, archivedItem := range titleData.Archived {
+			row.Archived += len(archivedItem)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// filterDatabaseRows keeps the rows whose name fuzzy matches the query or
+// whose title ID starts with it.
+func filterDatabaseRows(rows []DatabaseRow, query string) []DatabaseRow {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return rows
+	}
+	var filtered []DatabaseRow
+	for _, row := range rows {
+		if _, ok := fuzzyScore(query, row.TitleName); ok || strings.HasPrefix(row.TitleID, query) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+func (r DatabaseRow) cell(column int) string {
+	switch column {
+	case 0:
+		return r.TitleName
+	case 1:
+		return displayTitleID(r.TitleID)
+	case 2:
+		return fmt.Sprint(r.Content)
+	case 3:
+		return fmt.Sprint(r.KnownUpdates)
+	default:
+		if r.Content == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.0f%%", r.Completion()*100)
+	}
+}
+
+// databaseBrowser lists every title of the loaded database, searchable and
+// sortable by clicking a column header, independent of any scan. Selecting
+// a title shows its details.
+func (a *App) databaseBrowser() fyne.CanvasObject {
+	all := databaseRows(a.Titles)
+	rows := all
+	sortColumn, descending := 0, false
+
+	sortRows := func() {
+		less := databaseColumns[sortColumn].Less
+		sort.SliceStable(rows, func(i, j int) bool {
+			if descending {
+				return less(rows[j], rows[i])
+			}
+			return less(rows[i], rows[j])
+		})
+	}
+	sortRows()
+
+	table := widget.NewTableWithHeaders(
+		func() (int, int) {
+			return len(rows), len(databaseColumns)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(rows[id.Row].cell(id.Col))
+		},
+	)
+	table.ShowHeaderColumn = false
+	table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewButton("", nil)
+	}
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		button := obj.(*widget.Button)
+		label := databaseColumns[id.Col].Name
+		if id.Col == sortColumn && descending {
+			label += " ▼"
+		} else if id.Col == sortColumn {
+			label += " ▲"
+		}
+		button.SetText(label)
+		button.OnTapped = func() {
+			if sortColumn == id.Col {
+				descending = !descending
+			} else {
+				sortColumn, descending = id.Col, false
+			}
+			sortRows()
+			table.Refresh()
+		}
+	}
+	for i, column := range databaseColumns {
+		table.SetColumnWidth(i, column.Width)
+	}
+	table.OnSelected = func(id widget.TableCellID) {
+		a.showTitleDetails(rows[id.Row].TitleID)
+		table.UnselectAll()
+	}
+
+	count := widget.NewLabel("")
+	updateCount := func() {
+		count.SetText(fmt.Sprintf("%d of %d titles", len(rows), len(all)))
+	}
+	updateCount()
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Filter by title name or ID...")
+	searchEntry.OnChanged = func(query string) {
+		rows = filterDatabaseRows(all, query)
+		sortRows()
+		updateCount()
+		table.ScrollToTop()
+		table.Refresh()
+	}
+
+	return container.NewBorder(container.NewBorder(nil, nil, nil, count, searchEntry), nil, nil, nil, table)
+}
@@ -2,15 +2,21 @@ package main
 
 import (
 	"fmt"
-	"log"
+	"os"
 	"strings"
 
+	"github.com/Xbox-Preservation-Project/Pinecone/titleid"
 	"github.com/fatih/color"
 )
 
 const (
 	headerWidth = 100
 	separator   = ""
+
+	// Exit codes of a CLI scan, so scripts can tell whether it's worth reporting.
+	exitNothingFound = 0
+	exitFoundContent = 2
+	exitError        = 3
 )
 
 type CLIOptions struct {
@@ -19,38 +25,87 @@ type CLIOptions struct {
 	JSONUrl      string
 }
 
+// setupColor turns colored output off with -no-color. The color package
+// already leaves it off when NO_COLOR is set, TERM is dumb or the output
+// isn't a terminal (redirected to a file, CI logs), and turns on escape code
+// support in Windows consoles, translating them on consoles without it.
+func setupColor() {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		color.NoColor = true
+	}
+}
+
 func printHeader(title string) {
+	if quietMode {
+		return
+	}
 	title = strings.TrimSpace(title)
-	if len(title) > headerWidth-6 { // -6 to account for spaces and equals signs
-		title = title[:headerWidth-9] + "..."
+	if textWidth(title) > headerWidth-6 { // -6 to account for spaces and equals signs
+		runes := []rune(title)
+		for textWidth(string(runes)) > headerWidth-9 {
+			runes = runes[:len(runes)-1]
+		}
+		title = string(runes) + "..."
 	}
 	formattedTitle := "== " + title + " =="
-	padLen := (headerWidth - len(formattedTitle)) / 2
-	color.New(color.FgCyan).Println(strings.Repeat("=", padLen) + formattedTitle + strings.Repeat("=", headerWidth-padLen-len(formattedTitle)))
+	width := textWidth(formattedTitle)
+	padLen := (headerWidth - width) / 2
+	color.New(color.FgCyan).Println(strings.Repeat("=", padLen) + formattedTitle + strings.Repeat("=", headerWidth-padLen-width))
+}
+
+// textWidth is the number of terminal columns text takes, CJK characters
+// (e.g. localized Japanese title names) take two.
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		if r >= 0x1100 && (r <= 0x115f || (r >= 0x2e80 && r <= 0xa4cf) || (r >= 0xac00 && r <= 0xd7a3) ||
+			(r >= 0xf900 && r <= 0xfaff) || (r >= 0xfe30 && r <= 0xfe4f) || (r >= 0xff00 && r <= 0xff60) || (r >= 0xffe0 && r <= 0xffe6)) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
 }
 
 func printInfo(colorCode color.Attribute, format string, args ...interface{}) {
+	// Quiet mode only keeps what is worth reporting: unknown/unarchived
+	// content (red/yellow) and errors.
+	if quietMode && colorCode != color.FgRed && colorCode != color.FgYellow {
+		return
+	}
 	color.New(colorCode).Printf("    "+format, args...)
 }
 
 // Prints statistics for a specific title or for all titles if batch is true.
-func printStats(titleID string, batch bool) {
+func (a *App) printStats(titleID string, batch bool) {
 	if batch {
-		printTotalStats()
+		a.printTotalStats()
 	} else {
-		data, ok := titles.Titles[titleID]
+		titleID, err := titleid.Normalize(titleID)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		data, ok := a.Titles.Titles[titleID]
 		if !ok {
-			fmt.Printf("No data found for title ID %s\n", titleID)
+			fmt.Printf("No data found for title ID %s\n", displayTitleID(titleID))
 			return
 		}
-		fmt.Printf("Statistics for title ID %s:\n", titleID)
+		fmt.Printf("Statistics for title ID %s:\n", displayTitleID(titleID))
 		printTitleStats(&data)
 	}
 }
 
 // Prints statistics for TitleData.
 func printTitleStats(data *TitleData) {
-	fmt.Println("Title:", data.TitleName)
+	fmt.Println("Title:", data.DisplayName())
+	if metadata := data.Metadata(); metadata != "" {
+		fmt.Println("Metadata:", metadata)
+	}
+	if data.Notes != "" {
+		fmt.Println("Notes:", data.Notes)
+	}
 	fmt.Println("Total number of Content IDs:", len(data.ContentIDs))
 	fmt.Println("Total number of Title Updates:", len(data.TitleUpdates))
 	fmt.Println("Total number of Known Title Updates:", len(data.TitleUpdatesKnown))
@@ -58,8 +113,8 @@ func printTitleStats(data *TitleData) {
 	fmt.Println()
 }
 
-func printTotalStats() {
-	totalTitles := len(titles.Titles)
+func (a *App) printTotalStats() {
+	totalTitles := len(a.Titles.Titles)
 	totalContentIDs := 0
 	totalTitleUpdates := 0
 	totalKnownTitleUpdates := 0
@@ -69,7 +124,7 @@ func printTotalStats() {
 	knownTitleUpdateHashes := make(map[string]struct{})
 	archivedItemHashes := make(map[string]struct{})
 
-	for _, data := range titles.Titles {
+	for _, data := range a.Titles.Titles {
 		totalContentIDs += len(data.ContentIDs)
 		totalTitleUpdates += len(data.TitleUpdates)
 
@@ -96,6 +151,21 @@ func printTotalStats() {
 	fmt.Println("Total Title Updates:", totalTitleUpdates)
 	fmt.Println("Total Known Title Updates:", totalKnownTitleUpdates)
 	fmt.Println("Total Archived Items:", totalArchivedItems)
+
+	a.printPublisherStats()
+}
+
+// printLine prints progress output that -quiet suppresses.
+func printLine(a ...interface{}) {
+	if !quietMode {
+		fmt.Println(a...)
+	}
+}
+
+// exitWithError prints err and exits with the error exit code.
+func exitWithError(err interface{}) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(exitError)
 }
 
 func cliPromptForDownload(url string) bool {
@@ -107,27 +177,139 @@ func cliPromptForDownload(url string) bool {
 	return strings.ToLower(response) == "yes"
 }
 
-func startCLI(options CLIOptions) {
+func (a *App) startCLI(options CLIOptions) {
 	err := checkDataFolder(options.DataFolder)
 	if err != nil {
-		log.Fatalln(err)
+		exitWithError(err)
 	}
 
-	err = checkDatabaseFile(options.JSONFilePath, options.JSONUrl, updateFlag)
+	err = a.checkDatabaseFile(options.JSONFilePath, options.JSONUrl, a.Config.Update)
 	if err != nil {
-		log.Fatalln(err)
+		exitWithError(err)
+	}
+
+	for _, location := range a.scanLocations() {
+		err = a.checkDumpFolder(location)
+		if err != nil {
+			exitWithError(err)
+		}
 	}
 
-	err = checkDumpFolder(dumpLocation)
+	printLine(fmt.Sprintf("Pinecone v%s", version))
+	printLine("Please share output of this program with the Pinecone team if you find anything interesting!")
+
+	err = a.checkParsingSettings()
 	if err != nil {
-		log.Fatalln(err)
+		exitWithError(err)
 	}
 
-	fmt.Printf("Pinecone v%s\n", version)
-	fmt.Println("Please share output of this program with the Pinecone team if you find anything interesting!")
+	settings, err := a.loadSettings()
+	if err != nil {
+		fmt.Println(err)
+		settings = &Settings{}
+	}
 
-	err = checkParsingSettings()
+	if a.Config.HTMLReport != "" {
+		err = a.exportHTMLReport(a.Config.HTMLReport, settings)
+		if err != nil {
+			exitWithError(err)
+		}
+		printLine("HTML report saved to:", a.Config.HTMLReport)
+	}
+
+	if a.Config.MarkdownReport != "" {
+		err = a.exportMarkdownReport(a.Config.MarkdownReport, settings)
+		if err != nil {
+			exitWithError(err)
+		}
+		printLine("Markdown report saved to:", a.Config.MarkdownReport)
+	}
+
+	a.postScanWebhook(settings)
+
+	if a.Config.CollectDir != "" {
+		copied, err := a.collectFinds(a.Config.CollectDir, settings)
+		if err != nil {
+			exitWithError(err)
+		}
+		printLine(fmt.Sprintf("%d file(s) collected to: %s", copied, a.Config.CollectDir))
+	}
+
+	if a.Config.Triage {
+		if err := a.runTriage(os.Stdin, settings); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if len(a.Report.Errors) > 0 {
+		os.Exit(exitError)
+	}
+	if len(a.Report.Interesting().Findings) > 0 {
+		os.Exit(exitFoundContent)
+	}
+	os.Exit(exitNothingFound)
+}
+
+// runCommand runs a command given after the flags, e.g. "pinecone search halo".
+func (a *App) runCommand(args []string, options CLIOptions) {
+	a.Config.GUI = false
+
+	err := checkDataFolder(options.DataFolder)
 	if err != nil {
-		log.Fatalln(err)
+		exitWithError(err)
+	}
+
+	err = a.checkDatabaseFile(options.JSONFilePath, options.JSONUrl, a.Config.Update)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	switch args[0] {
+	case "search":
+		if len(args) < 2 {
+			exitWithError("Usage: pinecone search <title name>")
+		}
+		a.printSearchResults(strings.Join(args[1:], " "))
+	case "audit":
+		a.printAudit()
+	case "changes":
+		a.printDatabaseChanges()
+	case "export":
+		if len(args) < 2 || args[1] != "manifest" || len(args) > 3 {
+			exitWithError("Usage: pinecone -l=<dump> export manifest [output file]")
+		}
+		if len(a.scanLocations()) > 1 {
+			exitWithError("A manifest covers a single dump, pass only one -l")
+		}
+		outputPath := a.defaultReportPath("manifest", ".sha1")
+		if len(args) == 3 {
+			outputPath = args[2]
+		}
+		if err := a.exportManifest(a.Config.DumpLocation, outputPath); err != nil {
+			exitWithError(err)
+		}
+	case "verify":
+		if len(args) != 2 {
+			exitWithError("Usage: pinecone -l=<dump> verify <manifest file>")
+		}
+		if len(a.scanLocations()) > 1 {
+			exitWithError("A manifest covers a single dump, pass only one -l")
+		}
+		differences, err := a.verifyManifest(a.Config.DumpLocation, args[1])
+		if err != nil {
+			exitWithError(err)
+		}
+		if !differences.Empty() {
+			os.Exit(exitFoundContent)
+		}
+	case "devtool":
+		a.runDevtool(args[1:])
+	case "compare":
+		if len(args) != 3 {
+			exitWithError("Usage: pinecone compare <dump A> <dump B>")
+		}
+		a.printDumpComparison(args[1], args[2])
+	default:
+		exitWithError(fmt.Sprintf("Unknown command %q, see -help for usage", args[0]))
 	}
 }
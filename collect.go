@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// collectFinds copies the files of the unknown and unarchived findings of the
+// last scan into dest, under their path relative to the folder holding TDATA
+// (dest/TDATA/4d530064/$c/...), with a Markdown report of them, to review and
+// upload without touching the dump. Returns how many files were copied.
+func (a *App) collectFinds(dest string, settings *Settings) (int, error) {
+	findings := a.Report.Interesting().Findings
+	if len(findings) == 0 {
+		return 0, nil
+	}
+	if err := a.checkCollectDir(dest); err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return 0, fmt.Errorf("Error creating the collect folder: %v", err)
+	}
+
+	copied := 0
+	for _, f := range findings {
+		err := walkFindingFiles(f, func(name string, info fs.FileInfo, src io.Reader) error {
+			if err := copyCollected(filepath.Join(dest, filepath.FromSlash(name)), info, src); err != nil {
+				return err
+			}
+			copied++
+			return nil
+		})
+		if err != nil {
+			return copied, fmt.Errorf("Error collecting %s: %v", f.Path, err)
+		}
+	}
+
+	report, err := os.Create(filepath.Join(dest, "report.md"))
+	if err != nil {
+		return copied, fmt.Errorf("Error writing the collected report: %v", err)
+	}
+	defer report.Close()
+	if err := a.writeFindingsReport(report, findings, settings); err != nil {
+		return copied, fmt.Errorf("Error writing the collected report: %v", err)
+	}
+	return copied, nil
+}
+
+// checkCollectDir refuses a folder inside a scanned dump, the copies would be
+// found by the next scan.
+func (a *App) checkCollectDir(dest string) error {
+	abs, err := filepath.Abs(dest)
+	if err != nil {
+		return fmt.Errorf("Error with the collect folder: %v", err)
+	}
+	for _, location := range a.scanLocations() {
+		if isArchive(location) {
+			continue
+		}
+		dump, err := filepath.Abs(location)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(dump, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("Error with the collect folder: %s is inside the dump %s", dest, location)
+		}
+	}
+	return nil
+}
+
+// copyCollected copies a file, keeping its modification time.
+func copyCollected(target string, info fs.FileInfo, src io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	dst, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(target, info.ModTime(), info.ModTime())
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// DumpComparison is the result of comparing the TDATA files of two dumps by
+// hash, e.g. before and after pulling new content onto a console.
+type DumpComparison struct {
+	OnlyInA   map[string][]string // SHA1 -> paths relative to TDATA
+	OnlyInB   map[string][]string
+	Identical int
+}
+
+// hashDumpFiles hashes every file in the TDATA folder of a dump, returning
+// the paths relative to TDATA for each hash.
+func (a *App) hashDumpFiles(location string) (map[string][]string, error) {
+	fsys, tdata, closeDump, err := openDump(location)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDump()
+
+	hashes := make(map[string][]string)
+	err = walkDump(fsys, tdata, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fileHash, err := a.getSHA1HashFS(location, fsys, name)
+		if err != nil {
+			return fmt.Errorf("Error calculating hash for file: %s, error: %v", displayPath(location, name), err)
+		}
+		hashes[fileHash] = append(hashes[fileHash], relativePath(tdata, name))
+		return nil
+	})
+	return hashes, err
+}
+
+// compareDumps reports the files present in one dump but not the other. Files
+// are matched by hash, so moved or renamed files count as present.
+func (a *App) compareDumps(locationA string, locationB string) (DumpComparison, error) {
+	hashesA, err := a.hashDumpFiles(locationA)
+	if err != nil {
+		return DumpComparison{}, err
+	}
+	hashesB, err := a.hashDumpFiles(locationB)
+	if err != nil {
+		return DumpComparison{}, err
+	}
+
+	comparison := DumpComparison{OnlyInA: make(map[string][]string), OnlyInB: make(map[string][]string)}
+	for hash, paths := range hashesA {
+		if _, ok := hashesB[hash]; ok {
+			comparison.Identical++
+		} else {
+			comparison.OnlyInA[hash] = paths
+		}
+	}
+	for hash, paths := range hashesB {
+		if _, ok := hashesA[hash]; !ok {
+			comparison.OnlyInB[hash] = paths
+		}
+	}
+	return comparison, nil
+}
+
+// describeDumpPath names the title a path relative to TDATA belongs to, if
+// it is in the database.
+func (a *App) describeDumpPath(relPath string) string {
+	titleID, _, _ := strings.Cut(relPath, "/")
+	if titleData, ok := a.Titles.Titles[strings.ToLower(titleID)]; ok {
+		return relPath + " (" + titleData.DisplayName() + ")"
+	}
+	return relPath
+}
+
+func (a *App) printDumpDifference(label string, files map[string][]string) {
+	var lines []string
+	for hash, paths := range files {
+		for _, p := range paths {
+			lines = append(lines, fmt.Sprintf("  %s  %s", a.describeDumpPath(p), hash))
+		}
+	}
+	sort.Strings(lines)
+
+	fmt.Printf("Only in %s (%d):\n", label, len(lines))
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	fmt.Println()
+}
+
+func (a *App) printDumpComparison(locationA string, locationB string) {
+	comparison, err := a.compareDumps(locationA, locationB)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	a.printDumpDifference(locationA, comparison.OnlyInA)
+	a.printDumpDifference(locationB, comparison.OnlyInB)
+	fmt.Printf("%d file(s) identical in both dumps.\n", comparison.Identical)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Xbox-Preservation-Project/Pinecone/titleid"
+)
+
+const (
+	contentMetaMagic       = "XCNT"
+	contentMetaMagicOffset = 0x14
+
+	// Retail DLC ranges from small unlock keys to a few hundred MB.
+	dlcMinimumSize = 1 << 10
+	dlcMaximumSize = 2 << 30
+	// Retail title updates are small patches or whole executables.
+	updateMinimumSize = 16 << 10
+	updateMaximumSize = 64 << 20
+)
+
+// Confidence is how likely unknown content is genuine retail content rather
+// than homebrew or junk, from 0 to 100, with the signals that added to or
+// took from it.
+type Confidence struct {
+	Score   int
+	Signals []string
+}
+
+// add scores a heuristic, recording the signal for whether it passed.
+func (c *Confidence) add(points int, ok bool, good string, bad string) {
+	if ok {
+		c.Score += points
+		c.Signals = append(c.Signals, good)
+	} else {
+		c.Signals = append(c.Signals, bad)
+	}
+}
+
+// scoreDLC scores an unknown DLC folder: a valid ContentMeta.xbx, a content
+// ID belonging to the title, content files besides the metadata and a
+// plausible total size.
+func (a *App) scoreDLC(fsys fs.FS, dir string, titleID string, contentID string) Confidence {
+	var c Confidence
+
+	validMeta := false
+	if metaPath, found := findFile(fsys, dir, "ContentMeta.xbx"); found {
+		validMeta = hasContentMetaMagic(fsys, metaPath)
+	}
+	c.add(35, validMeta, "valid ContentMeta.xbx", "ContentMeta.xbx without an XCNT header")
+
+	id, err := decodeContentID(contentID)
+	switch {
+	case err != nil:
+		c.Signals = append(c.Signals, "malformed content ID")
+	case id.TitleID == titleID:
+		c.add(25, true, "content ID belongs to the title", "")
+	default:
+		// Offered by another title, e.g. a sequel, which should at least
+		// exist or have a publisher code.
+		_, known := a.Titles.Titles[id.TitleID]
+		c.add(15, known || titleid.Decoded(id.TitleID) != "", "content ID offered by a plausible title", "content ID of no plausible title")
+	}
+
+	files, size := 0, int64(0)
+	walkDump(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		if !strings.EqualFold(path.Base(name), "ContentMeta.xbx") {
+			files++
+		}
+		return nil
+	})
+	c.add(20, files > 0, "has content files", "only metadata, no content files")
+	c.add(20, size >= dlcMinimumSize && size <= dlcMaximumSize, "plausible size", "implausible size")
+	return c
+}
+
+func hasContentMetaMagic(fsys fs.FS, name string) bool {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, contentMetaMagicOffset+len(contentMetaMagic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	return string(header[contentMetaMagicOffset:]) == contentMetaMagic
+}
+
+// scoreUpdate scores an unknown title update: a valid XBE whose certificate
+// is for the title, a retail or debug signature (see checkXBESignature) and
+// a plausible size.
+func scoreUpdate(fsys fs.FS, filePath string, titleID string, signature string) Confidence {
+	var c Confidence
+
+	if xbe, err := readXBEInfoFS(fsys, filePath); err != nil {
+		c.Signals = append(c.Signals, "not a valid XBE")
+	} else {
+		c.add(30, true, "valid XBE", "")
+		c.add(30, xbe.TitleID == titleID, "certificate is for the title", "certificate is for another title")
+		// Without the public key intact section digests are the best hint
+		c.add(20, signature == signatureRetail || signature == signatureDebug || signature == signatureUnchecked, signature, signature)
+	}
+
+	size := int64(-1)
+	if info, err := fs.Stat(fsys, filePath); err == nil {
+		size = info.Size()
+	}
+	c.add(20, size >= updateMinimumSize && size <= updateMaximumSize, "plausible size", "implausible size")
+	return c
+}
+
+// UnknownByConfidence returns the unknown findings, most promising first, so
+// maintainers can triage them.
+func (r *Report) UnknownByConfidence() []Finding {
+	var unknown []Finding
+	for _, f := range r.Findings {
+		if f.Status == statusUnknown && f.Kind != kindDashboard && f.Kind != kindHomebrew && f.Kind != kindDevkit && f.Kind != kindSoundtrack {
+			unknown = append(unknown, f)
+		}
+	}
+	sort.SliceStable(unknown, func(i, j int) bool {
+		return unknown[i].Confidence.Score > unknown[j].Confidence.Score
+	})
+	return unknown
+}
+
+// String is the score with its signals, e.g. "80% (valid XBE, ...)".
+func (c Confidence) String() string {
+	return fmt.Sprintf("%d%% (%s)", c.Score, strings.Join(c.Signals, ", "))
+}
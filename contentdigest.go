@@ -0,0 +1,48 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// contentDigest hashes a whole content folder: the SHA1 of a line per file,
+// "<path>\x00<sha1>\n", with the lowercase path relative to the folder, in
+// path order. Unlike the content ID it tells a complete package from a
+// partial or modified one, wherever and however it was copied. The reason
+// is returned instead if a file isn't hashed under the hash size rules.
+func (a *App) contentDigest(location string, fsys fs.FS, dir string) (string, string, error) {
+	files := make(map[string]string)
+	err := walkDump(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			files[strings.ToLower(strings.TrimPrefix(name, dir+"/"))] = name
+		}
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	hash := sha1.New()
+	for _, rel := range rels {
+		if reason := a.hashSkipReason(fsys, files[rel]); reason != "" {
+			return "", reason, nil
+		}
+		fileHash, err := a.getSHA1HashFS(location, fsys, files[rel])
+		if err != nil {
+			return "", "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%s\n", rel, fileHash)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), "", nil
+}
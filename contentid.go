@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Xbox-Preservation-Project/Pinecone/titleid"
+)
+
+// ContentID is a decoded DLC content ID, the Xbox Live offering ID of the
+// content. The upper 32 bits are the ID of the title offering it, usually the
+// title itself but sometimes a related one (e.g. a sequel or online version).
+// The lower 32 bits identify the offering within that title: an offering
+// group, which publishers used to group content by type or release, and an
+// index within the group.
+type ContentID struct {
+	ID      string
+	TitleID string
+	Group   uint16
+	Index   uint16
+}
+
+func decodeContentID(id string) (ContentID, error) {
+	value, err := strconv.ParseUint(id, 16, 64)
+	if err != nil || len(id) != 16 {
+		return ContentID{}, fmt.Errorf("invalid content ID %q, expected 16 hex digits", id)
+	}
+	return ContentID{
+		ID:      id,
+		TitleID: fmt.Sprintf("%08x", value>>32),
+		Group:   uint16(value >> 16),
+		Index:   uint16(value),
+	}, nil
+}
+
+// GroupKey identifies the offering group, findings are grouped by it.
+func (c ContentID) GroupKey() string {
+	return fmt.Sprintf("%s/%04x", c.TitleID, c.Group)
+}
+
+// describeOffering explains a content ID for the title folder it was found
+// in, e.g. "group 2004, #3" or "offered by 46530003 FS-003, group 1001,
+// #65504" for content offered by another title.
+func (a *App) describeOffering(contentID string, titleID string) string {
+	c, err := decodeContentID(contentID)
+	if err != nil {
+		return ""
+	}
+	description := fmt.Sprintf("group %04x, #%d", c.Group, c.Index)
+	if c.TitleID == titleID {
+		return description
+	}
+
+	offeredBy := displayTitleID(c.TitleID)
+	if titleData, ok := a.Titles.Titles[c.TitleID]; ok {
+		offeredBy = titleData.DisplayName() + " (" + offeredBy + ")"
+	} else if decoded, err := titleid.Decode(c.TitleID); err == nil && decoded.Publisher != "" {
+		offeredBy += " by " + decoded.Publisher
+	}
+	return "offered by " + offeredBy + ", " + description
+}
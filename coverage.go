@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const chartHeight = 120
+
+// DatabaseStats is a snapshot of how complete the database is.
+type DatabaseStats struct {
+	Date           time.Time `json:"date"`
+	Titles         int       `json:"titles"`
+	TitlesComplete int       `json:"titlesComplete"`
+	ContentIDs     int       `json:"contentIDs"`
+	ArchivedItems  int       `json:"archivedItems"`
+	ItemsWanted    int       `json:"itemsWanted"`
+	KnownUpdates   int       `json:"knownUpdates"`
+}
+
+// computeDatabaseStats counts the titles whose content is fully archived and
+// the content still wanted.
+func computeDatabaseStats(list TitleList) DatabaseStats {
+	stats := DatabaseStats{Date: time.Now(), Titles: len(list.Titles)}
+	for _, titleData := range list.Titles {
+		archived := make(map[string]bool)
+		for _, archivedItem := range titleData.Archived {
+			for contentID := range archivedItem {
+				archived[contentID] = true
+			}
+		}
+
+		wanted := 0
+		for _, contentID := range titleData.ContentIDs {
+			if !archived[contentID] {
+				wanted++
+			}
+		}
+
+		stats.ContentIDs += len(titleData.ContentIDs)
+		stats.ArchivedItems += len(archived)
+		stats.ItemsWanted += wanted
+		stats.KnownUpdates += len(titleData.TitleUpdatesKnown)
+		if wanted == 0 {
+			stats.TitlesComplete++
+		}
+	}
+	return stats
+}
+
+func (a *App) statsHistoryPath() string {
+	return filepath.Join(a.Config.DataPath, "stats_history.json")
+}
+
+// recordStatsHistory appends the snapshot to the local history whenever the
+// database changed since the last one, and returns the full history.
+func (a *App) recordStatsHistory(stats DatabaseStats) ([]DatabaseStats, error) {
+	var history []DatabaseStats
+	if data, err := os.ReadFile(a.statsHistoryPath()); err == nil {
+		if err := json.Unmarshal(data, &history); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if len(history) > 0 {
+		last := history[len(history)-1]
+		last.Date = stats.Date
+		if last == stats {
+			return history, nil
+		}
+	}
+
+	history = append(history, stats)
+	data, err := json.MarshalIndent(history, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return history, os.WriteFile(a.statsHistoryPath(), data, 0o644)
+}
+
+// fetchRemoteStats loads a stats history published by the project, in the
+// same format as the local history file.
+func (a *App) fetchRemoteStats(url string) ([]DatabaseStats, error) {
+	resp, err := a.httpGet(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from %s: %s", url, resp.Status)
+	}
+
+	var history []DatabaseStats
+	err = json.NewDecoder(resp.Body).Decode(&history)
+	return history, err
+}
+
+// barChart draws one bar per value, scaled to the largest one.
+func barChart(values []int, labels []string, barColor color.Color) fyne.CanvasObject {
+	maxValue := 1
+	for _, value := range values {
+		if value > maxValue {
+			maxValue = value
+		}
+	}
+
+	bars := container.NewGridWithColumns(len(values))
+	for i, value := range values {
+		bar := canvas.NewRectangle(barColor)
+		bar.SetMinSize(fyne.NewSize(8, float32(chartHeight*value/maxValue)))
+		caption := canvas.NewText(labels[i], theme.ForegroundColor())
+		caption.TextSize = theme.CaptionTextSize()
+		caption.Alignment = fyne.TextAlignCenter
+		bars.Add(container.NewVBox(layout.NewSpacer(), widget.NewLabelWithStyle(fmt.Sprint(value), fyne.TextAlignCenter, fyne.TextStyle{}), bar, caption))
+	}
+	return bars
+}
+
+// coverageDashboard builds the content of the Dashboard tab.
+func (a *App) coverageDashboard(settings *Settings) fyne.CanvasObject {
+	if len(a.Titles.Titles) == 0 {
+		return widget.NewLabel("Load the database (scan or search) to see its coverage.")
+	}
+
+	stats := computeDatabaseStats(a.Titles)
+	history, err := a.recordStatsHistory(stats)
+	if err != nil {
+		fmt.Println("Error recording stats history:", err)
+		history = []DatabaseStats{stats}
+	}
+	source := "local history"
+	if settings.StatsURL != "" {
+		if remote, err := a.fetchRemoteStats(settings.StatsURL); err == nil && len(remote) > 0 {
+			history = remote
+			source = settings.StatsURL
+		} else if err != nil {
+			fmt.Println("Error fetching remote stats:", err)
+		}
+	}
+
+	complete := widget.NewProgressBar()
+	complete.SetValue(float64(stats.TitlesComplete) / float64(stats.Titles))
+	archived := widget.NewProgressBar()
+	if stats.ContentIDs > 0 {
+		archived.SetValue(float64(stats.ArchivedItems) / float64(stats.ContentIDs))
+	}
+
+	// Only chart the most recent snapshots so the bars stay readable.
+	if len(history) > 12 {
+		history = history[len(history)-12:]
+	}
+	wanted := make([]int, len(history))
+	completeTitles := make([]int, len(history))
+	dates := make([]string, len(history))
+	for i, snapshot := range history {
+		wanted[i] = snapshot.ItemsWanted
+		completeTitles[i] = snapshot.TitlesComplete
+		dates[i] = snapshot.Date.Format("01/02")
+	}
+
+	return container.NewVScroll(container.NewVBox(
+		widget.NewLabelWithStyle("Database Coverage", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(fmt.Sprintf("Titles complete: %d of %d", stats.TitlesComplete, stats.Titles)),
+		complete,
+		widget.NewLabel(fmt.Sprintf("Content archived: %d of %d (%d still wanted), %d known title updates",
+			stats.ArchivedItems, stats.ContentIDs, stats.ItemsWanted, stats.KnownUpdates)),
+		archived,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Items still wanted over time ("+source+")", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		barChart(wanted, dates, guiWarnColor()),
+		widget.NewLabelWithStyle("Titles complete over time", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		barChart(completeTitles, dates, guiGoodColor()),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Publishers with content still wanted", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		a.publisherCoverage(),
+	))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+const kindDashboard = "Dashboard"
+
+// dashboardFiles are the system software executables looked for, relative to
+// the dump location or its C partition folder.
+var dashboardFiles = []string{
+	"xboxdash.xbe",
+	"xodash/xonlinedash.xbe",
+}
+
+// checkForDashboard reports the dashboard/system software found in a dump,
+// identifying the version by hash from the database's Dashboards section.
+func (a *App) checkForDashboard(fsys fs.FS, root string, location string, events chan<- ScanEvent) error {
+	dirs := []string{root}
+	if c, found := findSubDir(fsys, root, "C"); found {
+		dirs = append(dirs, c)
+	}
+	for _, dir := range dirs {
+		for _, name := range dashboardFiles {
+			filePath, found := findFile(fsys, dir, name)
+			if !found {
+				continue
+			}
+
+			if reason := a.hashSkipReason(fsys, filePath); reason != "" {
+				emitSkipped(events, displayPath(location, filePath), reason)
+				continue
+			}
+			fileHash, err := a.hashScannedFile(location, fsys, filePath)
+			if err != nil {
+				return err
+			}
+			a.reportDashboard(fsys, filePath, displayPath(location, filePath), fileHash, location, events)
+		}
+	}
+	return nil
+}
+
+func (a *App) reportDashboard(fsys fs.FS, filePath string, displayedPath string, fileHash string, location string, events chan<- ScanEvent) {
+	finding := Finding{TitleName: "Dashboard", Kind: kindDashboard, Path: displayedPath, SHA1: fileHash, Status: statusUnknown, Name: "unknown version"}
+	if xbe, err := readXBEInfoFS(fsys, filePath); err == nil {
+		finding.TitleID = xbe.TitleID
+		finding.Name = fmt.Sprintf("certificate version %d", xbe.Version)
+	}
+	if name, ok := a.Titles.Dashboards[fileHash]; ok {
+		finding.Status = statusArchived
+		finding.Name = name
+	}
+
+	a.emitFinding(events, location, finding)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const databaseRepoPath = "data/id_database.json"
+
+// portableBuild makes a build portable by default, set at build time with
+// -ldflags "-X main.portableBuild=true".
+var portableBuild = "false"
+
+func (a *App) portableMode() bool {
+	return a.Config.Portable || portableBuild == "true"
+}
+
+// portableDataPath is the data folder next to the executable.
+func portableDataPath() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return "data"
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return filepath.Join(filepath.Dir(exe), "data")
+}
+
+// installedDataPath is the per-user data folder: $XDG_DATA_HOME/Pinecone
+// (~/.local/share/Pinecone) on Linux and BSD, ~/Library/Application
+// Support/Pinecone/data on macOS and %AppData%\Pinecone\data on Windows.
+func installedDataPath() string {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "plan9":
+		if configDir, err := os.UserConfigDir(); err == nil {
+			return filepath.Join(configDir, "Pinecone", "data")
+		}
+	default:
+		if dataHome := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dataHome) {
+			return filepath.Join(dataHome, "Pinecone")
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".local", "share", "Pinecone")
+		}
+	}
+	return "data"
+}
+
+// legacyDataPaths are data folders of older versions: ~/.config/Pinecone/data
+// on Linux and BSD.
+func legacyDataPaths() []string {
+	var paths []string
+	if configDir, err := os.UserConfigDir(); err == nil {
+		if path := filepath.Join(configDir, "Pinecone", "data"); !sameDir(path, installedDataPath()) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// resolveDataPath picks the data folder: -data, then PINECONE_DATA, then the
+// "dataPath" setting of the default folder, then the default folder of the
+// current mode. When the default folder doesn't exist yet, the data of the
+// other mode and older versions is moved over, and the old "data" folder in
+// the working directory is copied, so switching modes or upgrading keeps the
+// database and settings.
+func (a *App) resolveDataPath() string {
+	if a.Config.DataPath != "" {
+		return a.Config.DataPath
+	}
+	if path := os.Getenv("PINECONE_DATA"); path != "" {
+		return path
+	}
+
+	path, other := installedDataPath(), portableDataPath()
+	if a.portableMode() {
+		path, other = other, path
+	}
+
+	for _, from := range append(legacyDataPaths(), other) {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			break
+		}
+		if err := moveDataFolder(from, path); err != nil {
+			fmt.Println("Error moving data folder:", err)
+		}
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := copyDataFolder("data", path); err != nil {
+			fmt.Println("Error copying data folder:", err)
+		}
+	}
+
+	// The settings in the default folder can point somewhere else
+	a.Config.DataPath = path
+	if settings, err := a.loadSettings(); err == nil && settings.DataPath != "" {
+		return settings.DataPath
+	}
+	return path
+}
+
+// moveDataFolder moves from to to if from exists, copying when they're on
+// different drives.
+func moveDataFolder(from string, to string) error {
+	if sameDir(from, to) {
+		return nil
+	}
+	if _, err := os.Stat(from); err != nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err == nil {
+		fmt.Printf("Moved data from %s to %s\n", from, to)
+		return nil
+	}
+	if err := copyDataFolder(from, to); err != nil {
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+// copyDataFolder copies the contents of from to to if from exists.
+func copyDataFolder(from string, to string) error {
+	if sameDir(from, to) {
+		return nil
+	}
+	if info, err := os.Stat(from); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	err := filepath.WalkDir(from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		return copyFile(path, target)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Copied data from %s to %s\n", from, to)
+	return nil
+}
+
+func copyFile(from string, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+func sameDir(a string, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// DatabaseChanges is what a database update changed, kept in
+// data/database_changes.json until the next update so it can be shown again.
+type DatabaseChanges struct {
+	Updated time.Time
+	Titles  []TitleChanges
+}
+
+// TitleChanges lists the changes of a single title, e.g. "Added update
+// 0000000100000101:NTSC 0101 (<hash>)".
+type TitleChanges struct {
+	TitleID   string
+	TitleName string
+	Added     bool // the title is new to the database
+	Changes   []string
+}
+
+func (a *App) databaseChangesPath() string {
+	return filepath.Join(a.Config.DataPath, "database_changes.json")
+}
+
+// diffDatabases lists the titles, content and update hashes added, renamed
+// or removed between two databases, in title ID order.
+func diffDatabases(oldList TitleList, newList TitleList) []TitleChanges {
+	titleIDs := make([]string, 0, len(newList.Titles))
+	for titleID := range newList.Titles {
+		titleIDs = append(titleIDs, titleID)
+	}
+	for titleID := range oldList.Titles {
+		if _, ok := newList.Titles[titleID]; !ok {
+			titleIDs = append(titleIDs, titleID)
+		}
+	}
+	sort.Strings(titleIDs)
+
+	var changes []TitleChanges
+	for _, titleID := range titleIDs {
+		oldData, existed := oldList.Titles[titleID]
+		newData, exists := newList.Titles[titleID]
+		title := TitleChanges{TitleID: titleID, TitleName: newData.TitleName, Added: !existed}
+		if !exists {
+			title.TitleName = oldData.TitleName
+			title.Changes = []string{"Removed from the database"}
+			changes = append(changes, title)
+			continue
+		}
+		if existed && oldData.TitleName != newData.TitleName {
+			title.Changes = append(title.Changes, fmt.Sprintf("Renamed from %q", oldData.TitleName))
+		}
+
+		oldContent, newContent := contentNames(oldData), contentNames(newData)
+		for _, contentID := range sortedKeys(newContent) {
+			name, listed := oldContent[contentID]
+			switch {
+			case !listed && newContent[contentID] != "":
+				title.Changes = append(title.Changes, fmt.Sprintf("Added archived content %s: %s", contentID, newContent[contentID]))
+			case !listed:
+				title.Changes = append(title.Changes, fmt.Sprintf("Added content %s", contentID))
+			case name == "" && newContent[contentID] != "":
+				title.Changes = append(title.Changes, fmt.Sprintf("Archived content %s: %s", contentID, newContent[contentID]))
+			case name != newContent[contentID]:
+				title.Changes = append(title.Changes, fmt.Sprintf("Renamed content %s from %q to %q", contentID, name, newContent[contentID]))
+			}
+		}
+		for _, contentID := range sortedKeys(oldContent) {
+			if _, listed := newContent[contentID]; !listed {
+				title.Changes = append(title.Changes, fmt.Sprintf("Removed content %s", contentID))
+			}
+		}
+
+		oldUpdates, newUpdates := updateNames(oldData), updateNames(newData)
+		for _, hash := range sortedKeys(newUpdates) {
+			name, known := oldUpdates[hash]
+			if !known {
+				title.Changes = append(title.Changes, fmt.Sprintf("Added update %s (%s)", newUpdates[hash], hash))
+			} else if name != newUpdates[hash] {
+				title.Changes = append(title.Changes, fmt.Sprintf("Renamed update %s from %q to %q", hash, name, newUpdates[hash]))
+			}
+		}
+		for _, hash := range sortedKeys(oldUpdates) {
+			if _, known := newUpdates[hash]; !known {
+				title.Changes = append(title.Changes, fmt.Sprintf("Removed update %s (%s)", oldUpdates[hash], hash))
+			}
+		}
+
+		if title.Added || len(title.Changes) > 0 {
+			changes = append(changes, title)
+		}
+	}
+	return changes
+}
+
+// contentNames maps the content IDs of a title to their archived name, ""
+// if not archived.
+func contentNames(titleData TitleData) map[string]string {
+	names := make(map[string]string)
+	for _, contentID := range titleData.ContentIDs {
+		names[contentID] = ""
+	}
+	for _, item := range titleData.Archived {
+		for contentID, name := range item {
+			names[contentID] = name
+		}
+	}
+	return names
+}
+
+// updateNames maps the known update hashes of a title to their names.
+func updateNames(titleData TitleData) map[string]string {
+	names := make(map[string]string)
+	for _, knownUpdate := range titleData.TitleUpdatesKnown {
+		for hash, name := range knownUpdate {
+			names[hash] = name
+		}
+	}
+	return names
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// recordDatabaseChanges diffs the database before and after an update and
+// saves the changes. An old database that can't be read is treated as empty.
+func (a *App) recordDatabaseChanges(oldData []byte, newData []byte) (*DatabaseChanges, error) {
+	var oldList, newList TitleList
+	_ = json.Unmarshal([]byte(removeCommentsFromJSON(string(oldData))), &oldList)
+	if err := json.Unmarshal([]byte(removeCommentsFromJSON(string(newData))), &newList); err != nil {
+		return nil, err
+	}
+
+	changes := &DatabaseChanges{Updated: time.Now(), Titles: diffDatabases(oldList, newList)}
+	data, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(a.databaseChangesPath(), data, 0o644); err != nil {
+		return nil, fmt.Errorf("Error saving database changes: %v", err)
+	}
+	return changes, nil
+}
+
+// loadDatabaseChanges loads the changes of the last database update, nil if
+// the database was never updated.
+func (a *App) loadDatabaseChanges() (*DatabaseChanges, error) {
+	data, err := os.ReadFile(a.databaseChangesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var changes DatabaseChanges
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil, fmt.Errorf("Error reading database changes: %v", err)
+	}
+	return &changes, nil
+}
+
+// changesLines formats database changes the way the database changelog
+// issues do: a line per title followed by its changes.
+func changesLines(changes *DatabaseChanges) []string {
+	if changes == nil {
+		return []string{"The database hasn't been updated yet."}
+	}
+	lines := []string{fmt.Sprintf("Database updated %s: %d title(s) changed", changes.Updated.Format("2006-01-02 15:04"), len(changes.Titles))}
+	for _, title := range changes.Titles {
+		header := fmt.Sprintf("%s (%s)", title.TitleName, displayTitleID(title.TitleID))
+		if title.Added {
+			header += ", new title"
+		}
+		lines = append(lines, header)
+		for _, change := range title.Changes {
+			lines = append(lines, "  "+change)
+		}
+	}
+	return lines
+}
+
+// printDatabaseChanges prints the changes of the last database update.
+func (a *App) printDatabaseChanges() {
+	changes, err := a.loadDatabaseChanges()
+	if err != nil {
+		exitWithError(err)
+	}
+	for _, line := range changesLines(changes) {
+		fmt.Println(line)
+	}
+}
+
+// showDatabaseChanges shows the changes of the last database update, with a
+// filter to find a title.
+func (a *App) showDatabaseChanges(parent fyne.Window) {
+	changes, err := a.loadDatabaseChanges()
+	if err != nil {
+		dialog.ShowError(err, parent)
+		return
+	}
+	lines := changesLines(changes)
+	shown := lines
+
+	list := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(shown[id])
+		},
+	)
+	filter := widget.NewEntry()
+	filter.SetPlaceHolder("Filter by title name or ID")
+	filter.OnChanged = func(text string) {
+		shown = filterChanges(changes, lines, text)
+		list.Refresh()
+	}
+
+	content := container.NewBorder(filter, nil, nil, nil, list)
+	changesDialog := dialog.NewCustom("Database Changes", "Close", content, parent)
+	changesDialog.Resize(fyne.NewSize(700, 500))
+	changesDialog.Show()
+}
+
+// filterChanges keeps the lines of the titles whose name fuzzy matches the
+// query or whose title ID starts with it, see filterDatabaseRows.
+func filterChanges(changes *DatabaseChanges, lines []string, query string) []string {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if changes == nil || query == "" {
+		return lines
+	}
+	filtered := []string{lines[0]}
+	for _, title := range changes.Titles {
+		if _, ok := fuzzyScore(query, title.TitleName); !ok && !strings.HasPrefix(title.TitleID, query) {
+			continue
+		}
+		filtered = append(filtered, changesLines(&DatabaseChanges{Updated: changes.Updated, Titles: []TitleChanges{title}})[1:]...)
+	}
+	return filtered
+}
+
+// reportDatabaseChanges records what a database update changed and shows it,
+// failures only cost the changelog. The first download has nothing to diff.
+func (a *App) reportDatabaseChanges(oldData []byte, newData []byte) {
+	if oldData == nil {
+		return
+	}
+	changes, err := a.recordDatabaseChanges(oldData, newData)
+	if err != nil {
+		printLine(err)
+		return
+	}
+	for _, line := range changesLines(changes) {
+		if a.Config.GUI {
+			a.addText(theme.ForegroundColor(), "%s", line)
+		} else {
+			printLine(line)
+		}
+	}
+}
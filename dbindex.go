@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// databaseIndexVersion is bumped whenever DatabaseIndex changes, older
+// indexes are then rebuilt from the JSON.
+const databaseIndexVersion = 4
+
+// DatabaseIndex is the compiled form of id_database.json with its reverse
+// indexes. SourceSHA1 is the hash of the JSON it was compiled from, the index
+// is only used while they match.
+type DatabaseIndex struct {
+	Version    int
+	SourceSHA1 string
+	Titles     TitleList
+	Updates    map[string][]IndexEntry
+	Content    map[string][]IndexEntry
+}
+
+func (a *App) compileIndexEnabled() bool {
+	if a.Config.CompileIndex {
+		return true
+	}
+	settings, err := a.loadSettings()
+	return err == nil && settings.CompileIndex
+}
+
+func databaseIndexPath(jsonFilePath string) string {
+	return strings.TrimSuffix(jsonFilePath, ".json") + ".idx"
+}
+
+// loadDatabaseIndex loads the compiled index of jsonData, false if there is
+// none or it is out of date.
+func loadDatabaseIndex(jsonFilePath string, jsonData []byte, list *TitleList) bool {
+	data, err := os.ReadFile(databaseIndexPath(jsonFilePath))
+	if err != nil {
+		return false
+	}
+	var index DatabaseIndex
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&index); err != nil {
+		return false
+	}
+	if index.Version != databaseIndexVersion || index.SourceSHA1 != fmt.Sprintf("%x", sha1.Sum(jsonData)) {
+		return false
+	}
+	*list = index.Titles
+	list.updateIndex, list.contentIndex = index.Updates, index.Content
+	return true
+}
+
+// writeDatabaseIndex compiles the loaded database into its index.
+func writeDatabaseIndex(jsonFilePath string, jsonData []byte, list *TitleList) error {
+	index := DatabaseIndex{
+		Version:    databaseIndexVersion,
+		SourceSHA1: fmt.Sprintf("%x", sha1.Sum(jsonData)),
+		Titles:     *list,
+		Updates:    list.updateIndex,
+		Content:    list.contentIndex,
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(index); err != nil {
+		return fmt.Errorf("Error compiling database index: %v", err)
+	}
+	if err := os.WriteFile(databaseIndexPath(jsonFilePath), buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("Error writing database index: %v", err)
+	}
+	return nil
+}
+
+// databaseLoaded indexes a freshly parsed database, compiling it to disk
+// when enabled.
+func (a *App) databaseLoaded(jsonFilePath string, jsonData []byte, v interface{}) error {
+	list, ok := v.(*TitleList)
+	if !ok {
+		return nil
+	}
+	buildIndexes(list)
+	if a.compileIndexEnabled() {
+		return writeDatabaseIndex(jsonFilePath, jsonData, list)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// coverageRow shows a checkmark when the item was found locally.
+func coverageRow(found bool, text string) fyne.CanvasObject {
+	icon := theme.CheckButtonIcon()
+	if found {
+		icon = theme.CheckButtonCheckedIcon()
+	}
+	return container.NewHBox(widget.NewIcon(icon), widget.NewLabel(text))
+}
+
+// showTitleDetails opens a window with the details of a title.
+func (a *App) showTitleDetails(titleID string) {
+	content, ok := a.titleDetailsContent(titleID)
+	if !ok {
+		return
+	}
+	detailWindow := fyne.CurrentApp().NewWindow(a.Titles.Titles[titleID].DisplayName())
+	detailWindow.SetContent(container.NewVScroll(content))
+	detailWindow.Resize(fyne.NewSize(600, 500))
+	detailWindow.Show()
+}
+
+// titleDetailsContent combines the database entry of a title with what the
+// last scan found locally.
+func (a *App) titleDetailsContent(titleID string) (fyne.CanvasObject, bool) {
+	titleData, ok := a.Titles.Titles[titleID]
+	if !ok {
+		return nil, false
+	}
+
+	foundContent := make(map[string]bool)
+	foundHashes := make(map[string]bool)
+	var unknownFindings []Finding
+	for _, f := range a.Report.Findings {
+		if f.TitleID != titleID {
+			continue
+		}
+		if f.ContentID != "" {
+			foundContent[f.ContentID] = true
+		}
+		if f.SHA1 != "" {
+			foundHashes[f.SHA1] = true
+		}
+		if f.Status == statusUnknown {
+			unknownFindings = append(unknownFindings, f)
+		}
+	}
+
+	archivedNames := make(map[string]string)
+	for _, archived := range titleData.Archived {
+		for contentID, name := range archived {
+			archivedNames[contentID] = name
+		}
+	}
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("%s (%s)", titleData.DisplayName(), displayTitleID(titleID)), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(fmt.Sprintf("Found locally: %d of %d content IDs, %d of %d known updates",
+			len(foundContent), len(titleData.ContentIDs), countFoundUpdates(titleData, foundHashes), len(titleData.TitleUpdatesKnown))),
+	)
+	if metadata := titleData.Metadata(); metadata != "" {
+		content.Add(widget.NewLabel(metadata))
+	}
+	if titleData.Notes != "" {
+		notes := widget.NewLabel(titleData.Notes)
+		notes.Wrapping = fyne.TextWrapWord
+		content.Add(notes)
+	}
+	content.Add(widget.NewSeparator())
+	content.Add(widget.NewLabelWithStyle("Content", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+
+	contentIDs := append([]string(nil), titleData.ContentIDs...)
+	sort.Strings(contentIDs)
+	for _, contentID := range contentIDs {
+		status := "not archived"
+		if name, ok := archivedNames[contentID]; ok {
+			status = "archived: " + name
+		}
+		content.Add(coverageRow(foundContent[contentID], contentID+" ("+status+")"))
+	}
+
+	content.Add(widget.NewSeparator())
+	content.Add(widget.NewLabelWithStyle("Known Title Updates", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	for _, knownUpdate := range titleData.TitleUpdatesKnown {
+		for hash, name := range knownUpdate {
+			content.Add(coverageRow(foundHashes[hash], name+" ("+hash+")"))
+		}
+	}
+
+	if len(unknownFindings) > 0 {
+		content.Add(widget.NewSeparator())
+		content.Add(widget.NewLabelWithStyle("Not in the database", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, f := range unknownFindings {
+			content.Add(coverageRow(true, f.Kind+": "+f.Path))
+		}
+	}
+	return content, true
+}
+
+func countFoundUpdates(titleData TitleData, foundHashes map[string]bool) int {
+	count := 0
+	for _, knownUpdate := range titleData.TitleUpdatesKnown {
+		for hash := range knownUpdate {
+			if foundHashes[hash] {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Dump is a dump location opened for scanning.
+type Dump struct {
+	FS       fs.FS
+	TDATA    string
+	Root     string // folder holding TDATA, UDATA and the partition folders
+	Location string
+	App      *App // the run scanning the dump, with the database to match against
+}
+
+// TitleFolder is the folder of a title known to the database in TDATA.
+type TitleFolder struct {
+	Dir  string
+	ID   string
+	Data TitleData
+}
+
+// Detector finds one category of content in a dump. Detectors run in the
+// order they are registered, see registerDetector, and can be turned off
+// with -detectors or "detectors" in the settings.
+type Detector interface {
+	// Name is the category, e.g. "dlc".
+	Name() string
+	// Detect checks a dump for the category.
+	Detect(dump Dump, events chan<- ScanEvent) error
+}
+
+// TitleDetector is a Detector checking the folders of known titles in TDATA.
+// TDATA is walked once for every enabled title detector, right after the
+// first one's Detect.
+type TitleDetector interface {
+	Detector
+	DetectTitle(dump Dump, title TitleFolder, events chan<- ScanEvent) error
+}
+
+var (
+	detectors = []Detector{
+		EEPROMDetector{},
+		DLCDetector{},
+		UpdateDetector{},
+		DashboardDetector{},
+		SaveDetector{},
+		HomebrewDetector{},
+		DevkitDetector{},
+		SoundtrackDetector{},
+	}
+	// optionalDetectors only run when asked for.
+	optionalDetectors = map[string]bool{"homebrew": true, "soundtracks": true}
+)
+
+// registerDetector adds a detector to the scanner, run after the others.
+func registerDetector(d Detector) {
+	detectors = append(detectors, d)
+}
+
+// detectorNames returns the names of the given detectors.
+func detectorNames(ds []Detector) []string {
+	var names []string
+	for _, d := range ds {
+		names = append(names, d.Name())
+	}
+	return names
+}
+
+// enabledDetectors returns the detectors to run, from -detectors, the
+// settings or every detector but the optional ones.
+func (a *App) enabledDetectors() ([]Detector, error) {
+	var names []string
+	if a.Config.Detectors != "" {
+		names = strings.Split(a.Config.Detectors, ",")
+	} else if settings, err := a.loadSettings(); err == nil && settings.Detectors != nil {
+		names = settings.Detectors
+	}
+
+	if names == nil {
+		var enabled []Detector
+		for _, d := range detectors {
+			if optionalDetectors[d.Name()] {
+				continue
+			}
+			// A scan of a single title only checks its folders
+			if _, ok := d.(TitleDetector); a.Config.ScanTitleID != "" && !ok && d.Name() != "saves" {
+				continue
+			}
+			enabled = append(enabled, d)
+		}
+		return enabled, nil
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			return detectors, nil
+		}
+		if !contains(detectorNames(detectors), name) {
+			known := detectorNames(detectors)
+			sort.Strings(known)
+			return nil, fmt.Errorf("Error: unknown detector %q, known detectors are %s", name, strings.Join(known, ", "))
+		}
+		selected[name] = true
+	}
+	var enabled []Detector
+	for _, d := range detectors {
+		if selected[d.Name()] {
+			enabled = append(enabled, d)
+		}
+	}
+	return enabled, nil
+}
+
+// runDetectors runs the enabled detectors on a dump.
+func runDetectors(dump Dump, enabled []Detector, events chan<- ScanEvent) error {
+	var titleDetectors []TitleDetector
+	for _, d := range enabled {
+		if td, ok := d.(TitleDetector); ok {
+			titleDetectors = append(titleDetectors, td)
+		}
+	}
+
+	walked := false
+	for _, d := range enabled {
+		if err := d.Detect(dump, events); err != nil {
+			return err
+		}
+		if _, ok := d.(TitleDetector); ok && !walked {
+			walked = true
+			if err := checkForContent(dump, titleDetectors, events); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EEPROMDetector reports the console info of the first EEPROM found.
+type EEPROMDetector struct{}
+
+func (EEPROMDetector) Name() string { return "eeprom" }
+
+func (EEPROMDetector) Detect(dump Dump, events chan<- ScanEvent) error {
+	dump.App.checkForEEPROM(dump.FS, dump.TDATA, dump.Location, events)
+	return nil
+}
+
+// DLCDetector checks the content folders in $c of known titles.
+type DLCDetector struct{}
+
+func (DLCDetector) Name() string { return "dlc" }
+
+func (DLCDetector) Detect(dump Dump, events chan<- ScanEvent) error { return nil }
+
+func (DLCDetector) DetectTitle(dump Dump, title TitleFolder, events chan<- ScanEvent) error {
+	subDirDLC, found := findSubDir(dump.FS, title.Dir, "$c")
+	if !found {
+		return nil
+	}
+	return dump.App.processDLCContent(dump.FS, subDirDLC, title.Data, title.ID, dump.TDATA, dump.Location, events)
+}
+
+// UpdateDetector checks the title updates in $u of known titles.
+type UpdateDetector struct{}
+
+func (UpdateDetector) Name() string { return "updates" }
+
+func (UpdateDetector) Detect(dump Dump, events chan<- ScanEvent) error { return nil }
+
+func (UpdateDetector) DetectTitle(dump Dump, title TitleFolder, events chan<- ScanEvent) error {
+	subDirUpdates, found := findSubDir(dump.FS, title.Dir, "$u")
+	if !found {
+		return nil
+	}
+	return dump.App.processUpdates(dump.FS, subDirUpdates, title.Data, title.ID, dump.TDATA, dump.Location, events)
+}
+
+// DashboardDetector reports the dashboard found, see checkForDashboard.
+type DashboardDetector struct{}
+
+func (DashboardDetector) Name() string { return "dashboard" }
+
+func (DashboardDetector) Detect(dump Dump, events chan<- ScanEvent) error {
+	return dump.App.checkForDashboard(dump.FS, dump.Root, dump.Location, events)
+}
+
+// SaveDetector checks UDATA for wanted saves, see checkForSaves.
+type SaveDetector struct{}
+
+func (SaveDetector) Name() string { return "saves" }
+
+func (SaveDetector) Detect(dump Dump, events chan<- ScanEvent) error {
+	return dump.App.checkForSaves(dump.FS, dump.Root, dump.Location, events)
+}
+
+const kindHomebrew = "Homebrew"
+
+// homebrewFolders are the folders homebrew apps are usually installed in,
+// looked for in the dump and its E, F and G partition folders.
+var homebrewFolders = []string{"Apps", "Applications", "Emulators", "Homebrew"}
+
+// HomebrewDetector lists the homebrew apps installed in the dump, the
+// default.xbe of each app folder. There's no homebrew database yet, so they
+// are all reported as unknown.
+type HomebrewDetector struct{}
+
+func (HomebrewDetector) Name() string { return "homebrew" }
+
+func (HomebrewDetector) Detect(dump Dump, events chan<- ScanEvent) error {
+	roots := []string{dump.Root}
+	for _, partition := range []string{"E", "F", "G"} {
+		if dir, found := findSubDir(dump.FS, dump.Root, partition); found {
+			roots = append(roots, dir)
+		}
+	}
+
+	for _, root := range roots {
+		for _, name := range homebrewFolders {
+			dir, found := findSubDir(dump.FS, root, name)
+			if !found {
+				continue
+			}
+			entries, err := fs.ReadDir(dump.FS, dir)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				appDir := path.Join(dir, entry.Name())
+				if entry := resolveSymlink(dump.FS, appDir, entry); entry == nil || !entry.IsDir() {
+					continue
+				}
+				if xbePath, found := findFile(dump.FS, appDir, "default.xbe"); found {
+					reportHomebrew(dump, xbePath, entry.Name(), events)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func reportHomebrew(dump Dump, xbePath string, folderName string, events chan<- ScanEvent) {
+	xbe, err := readXBEInfoFS(dump.FS, xbePath)
+	if err != nil || dump.App.titleIgnored(xbe.TitleID) {
+		return
+	}
+	if reason := dump.App.hashSkipReason(dump.FS, xbePath); reason != "" {
+		emitSkipped(events, displayPath(dump.Location, xbePath), reason)
+		return
+	}
+	fileHash, err := dump.App.getSHA1HashFS(dump.Location, dump.FS, xbePath)
+	if err != nil {
+		reportHashError(xbePath, err, events)
+		return
+	}
+
+	name := xbe.TitleName
+	if name == "" {
+		name = folderName
+	}
+	dump.App.emitFinding(events, dump.Location, Finding{TitleID: xbe.TitleID, TitleName: "Homebrew", Kind: kindHomebrew, Status: statusUnknown, Name: name,
+		Path: displayPath(dump.Location, xbePath), SHA1: fileHash})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+var (
+	testUpdate     = []byte("XBEH known update")
+	testUpdateSHA1 = fmt.Sprintf("%x", sha1.Sum(testUpdate))
+)
+
+// testDump is a dump with archived, unarchived and unknown DLC and a known
+// and an unknown title update of one title.
+func testDump(a *App) Dump {
+	fsys := fstest.MapFS{
+		"TDATA/4d530064/$c/4d53006400000001/ContentMeta.xbx": {Data: []byte("XCNT")},
+		"TDATA/4d530064/$c/4d53006400000002/ContentMeta.xbx": {Data: []byte("XCNT")},
+		"TDATA/4d530064/$c/4d530064000000ff/ContentMeta.xbx": {Data: []byte("XCNT")},
+		"TDATA/4d530064/$c/4d53006400000003/readme.txt":      {},
+		"TDATA/4d530064/$u/default.xbe":                      {Data: testUpdate},
+		"TDATA/4d530064/$u/patch.xbe":                        {Data: []byte("XBEH unknown update")},
+		"TDATA/4d530064/$u/notes.txt":                        {},
+	}
+	return Dump{FS: fsys, TDATA: "TDATA", Root: ".", Location: "dump", App: a}
+}
+
+func newTestDumpApp(t *testing.T) *App {
+	a := newTestApp(t, TitleList{Titles: map[string]TitleData{
+		"4d530064": {
+			TitleName:         "Halo 2",
+			ContentIDs:        []string{"4d53006400000001", "4d53006400000002"},
+			Archived:          []map[string]string{{"4d53006400000001": "Killtacular Pack"}},
+			TitleUpdatesKnown: []map[string]string{{testUpdateSHA1: "Title Update 5"}},
+		},
+	}})
+	return a
+}
+
+type wantFinding struct {
+	kind, status, path, name string
+}
+
+func TestRunDetectors(t *testing.T) {
+	a := newTestDumpApp(t)
+	events, err := collectEvents(func(events chan<- ScanEvent) error {
+		return runDetectors(testDump(a), []Detector{DLCDetector{}, UpdateDetector{}}, events)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) == 0 || events[0].Kind != EventTitleFound || events[0].TitleID != "4d530064" || events[0].TitleName != "Halo 2" {
+		t.Fatalf("first event = %+v, want the title found", events)
+	}
+	want := []wantFinding{
+		{kindDLC, statusArchived, "4d530064/$c/4d53006400000001", "Killtacular Pack"},
+		{kindDLC, statusUnarchived, "4d530064/$c/4d53006400000002", ""},
+		{kindDLC, statusUnknown, displayPath("dump", "TDATA/4d530064/$c/4d530064000000ff"), ""},
+		{kindUpdate, statusArchived, "4d530064/$u/default.xbe", "Title Update 5"},
+		{kindUpdate, statusUnknown, "4d530064/$u/patch.xbe", ""},
+	}
+	findings := events[1:]
+	if len(findings) != len(want) {
+		t.Fatalf("got %d events after the title, want %d: %+v", len(findings), len(want), findings)
+	}
+	for i, event := range findings {
+		f := event.Finding
+		if event.Kind != EventFinding || event.TitleID != "4d530064" || event.Location != "dump" {
+			t.Errorf("event %d = %+v, want a finding of 4d530064 in dump", i, event)
+		}
+		got := wantFinding{f.Kind, f.Status, f.Path, f.Name}
+		if got != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+	if sha := findings[3].Finding.SHA1; sha != testUpdateSHA1 {
+		t.Errorf("update SHA1 = %s, want %s", sha, testUpdateSHA1)
+	}
+}
+
+func TestRunDetectorsOnlyEnabled(t *testing.T) {
+	a := newTestDumpApp(t)
+	events, err := collectEvents(func(events chan<- ScanEvent) error {
+		return runDetectors(testDump(a), []Detector{UpdateDetector{}}, events)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range events {
+		if event.Kind == EventFinding && event.Finding.Kind != kindUpdate {
+			t.Errorf("DLC reported with only the update detector: %+v", event.Finding)
+		}
+	}
+	if len(events) != 3 {
+		t.Errorf("got %d events, want the title and 2 updates: %+v", len(events), events)
+	}
+}
+
+func TestCheckForContentIgnoredTitle(t *testing.T) {
+	a := newTestDumpApp(t)
+	a.IgnoredTitles = []string{"4d530064"}
+
+	events, err := collectEvents(func(events chan<- ScanEvent) error {
+		return checkForContent(testDump(a), []TitleDetector{DLCDetector{}, UpdateDetector{}}, events)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("ignored title reported: %+v", events)
+	}
+}
+
+func TestScanDumpLocationsTagsLocation(t *testing.T) {
+	a := newTestDumpApp(t)
+	var locations []string
+	for i := 0; i < 2; i++ {
+		location := t.TempDir()
+		dirs := []string{fmt.Sprintf("TDATA/4d530064/$c/4d5300640000000%d", i+1), "TDATA/4d5300ff/$c/4d5300ff00000001"}
+		for _, dir := range dirs {
+			if err := os.MkdirAll(filepath.Join(location, dir), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(location, dir, "ContentMeta.xbx"), []byte("XCNT"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		locations = append(locations, location)
+	}
+
+	a.resetReport()
+	if err := runScan(&a.Report, func(events chan<- ScanEvent) error {
+		return a.scanDumpLocations(locations, events)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Report.Findings) != 2 || len(a.Report.UnknownTitles) != 2 {
+		t.Fatalf("got %d findings and %d unknown titles, want one of each per location", len(a.Report.Findings), len(a.Report.UnknownTitles))
+	}
+	for i, location := range locations {
+		if f := a.Report.Findings[i]; f.Location != location {
+			t.Errorf("finding %s tagged with %q, want %q", f.Path, f.Location, location)
+		}
+		if u := a.Report.UnknownTitles[i]; u.Location != location {
+			t.Errorf("unknown title %s tagged with %q, want %q", u.Path, u.Location, location)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const kindDevkit = "Devkit Build"
+
+// devkitFiles and devkitFolders mark a debug kit dump: the debug monitor the
+// XDK installs on C and the E:\DEVKIT folder games are copied to from a PC.
+var (
+	devkitFiles   = []string{"xbdm.dll", "C/xbdm.dll"}
+	devkitFolders = []string{"DEVKIT", "E/DEVKIT"}
+)
+
+// DevkitDetector recognizes dumps of debug kits and lists the debug builds
+// copied to their DEVKIT folder. Those never went through Microsoft's
+// certification, any of them can be an unreleased prototype, so they are all
+// reported as unknown.
+type DevkitDetector struct{}
+
+func (DevkitDetector) Name() string { return "devkit" }
+
+func (DevkitDetector) Detect(dump Dump, events chan<- ScanEvent) error {
+	var markers, devkitDirs []string
+	for _, name := range devkitFiles {
+		if filePath, found := findFile(dump.FS, dump.Root, name); found {
+			markers = append(markers, displayPath(dump.Location, filePath))
+		}
+	}
+	for _, name := range devkitFolders {
+		dir, found := dump.Root, true
+		for _, elem := range strings.Split(name, "/") {
+			if dir, found = findSubDir(dump.FS, dir, elem); !found {
+				break
+			}
+		}
+		if found {
+			markers = append(markers, displayPath(dump.Location, dir))
+			devkitDirs = append(devkitDirs, dir)
+		}
+	}
+	if len(markers) == 0 {
+		return nil
+	}
+	emitWarning(events, fmt.Sprintf("Debug kit dump, found %s. Debug builds on it may be unreleased prototypes.", strings.Join(markers, ", ")))
+
+	for _, dir := range devkitDirs {
+		err := walkDump(dump.FS, dir, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !strings.EqualFold(path.Ext(filePath), ".xbe") {
+				return nil
+			}
+			reportDevkitBuild(dump, filePath, events)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportDevkitBuild reports an XBE of a DEVKIT folder, named after the title
+// in the database or its certificate.
+func reportDevkitBuild(dump Dump, xbePath string, events chan<- ScanEvent) {
+	xbe, err := readXBEInfoFS(dump.FS, xbePath)
+	if err != nil {
+		return
+	}
+	if reason := dump.App.hashSkipReason(dump.FS, xbePath); reason != "" {
+		emitSkipped(events, displayPath(dump.Location, xbePath), reason)
+		return
+	}
+	fileHash, err := dump.App.getSHA1HashFS(dump.Location, dump.FS, xbePath)
+	if err != nil {
+		reportHashError(xbePath, err, events)
+		return
+	}
+
+	name := xbe.TitleName
+	titleData, known := dump.App.Titles.Titles[xbe.TitleID]
+	if known {
+		name = titleData.TitleName
+	}
+	if name == "" {
+		name = path.Base(path.Dir(xbePath))
+	}
+	name = fmt.Sprintf("%s, version %d", name, xbe.Version)
+	dump.App.emitFinding(events, dump.Location, Finding{TitleID: xbe.TitleID, TitleName: "Devkit Builds", Kind: kindDevkit, Status: statusUnknown, Name: name,
+		Path: displayPath(dump.Location, xbePath), SHA1: fileHash, Signature: dump.App.checkXBESignature(dump.FS, xbePath),
+		Prototype: prototypeHints(dump.FS, xbePath, titleData.ReleaseYear)})
+}
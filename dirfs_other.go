@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+import (
+	"io/fs"
+	"os"
+)
+
+// dumpDirFS opens a dump folder as a file system.
+func dumpDirFS(dir string) fs.FS {
+	return os.DirFS(dir)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dumpDirFS opens a dump folder as a file system. Files are opened by their
+// extended-length path (\\?\C:\...), so the deeply nested files of NTFS copies
+// of FATX trees can be read past the 260 character MAX_PATH limit.
+func dumpDirFS(dir string) fs.FS {
+	return longPathFS(extendedPath(dir))
+}
+
+// extendedPath returns the absolute extended-length form of a path,
+// \\?\C:\dir or \\?\UNC\server\share\dir.
+func extendedPath(dir string) string {
+	if strings.HasPrefix(dir, `\\?\`) {
+		return dir
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	abs = strings.TrimRight(abs, `\`)
+	if strings.HasPrefix(abs, `\\`) {
+		return `\\?\UNC\` + abs[2:]
+	}
+	return `\\?\` + abs
+}
+
+// longPathFS is a folder opened by its extended-length path. Such paths
+// aren't normalized by Windows, so names are joined with backslashes here.
+type longPathFS string
+
+func (dir longPathFS) join(op string, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		if strings.HasSuffix(string(dir), ":") { // drive root
+			return string(dir) + `\`, nil
+		}
+		return string(dir), nil
+	}
+	return string(dir) + `\` + filepath.FromSlash(name), nil
+}
+
+func (dir longPathFS) Open(name string) (fs.File, error) {
+	fullName, err := dir.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(fullName)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (dir longPathFS) Stat(name string) (fs.FileInfo, error) {
+	fullName, err := dir.join("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(fullName)
+}
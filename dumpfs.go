@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The scanner reads dumps through fs.FS, so folders, archives and any other
+// backend (FATX images, FTP, in-memory trees) go through the same code.
+// Paths inside a dump are always slash separated.
+
+// openDump opens a dump location as a file system and returns the path of its
+// TDATA folder, close must be called once the scan is done.
+func openDump(location string) (fsys fs.FS, tdata string, close func() error, err error) {
+	if err := checkArchiveSupported(location); err != nil {
+		return nil, "", nil, err
+	}
+	if isArchive(location) {
+		fsys, close, err = openArchive(location)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		tdata, err = findArchiveTDATA(fsys)
+		if err != nil {
+			close()
+			return nil, "", nil, err
+		}
+		return fsys, tdata, close, nil
+	}
+
+	fsys = dumpDirFS(location)
+	tdata, found := findTDATA(fsys)
+	if !found {
+		return nil, "", nil, fmt.Errorf("TDATA folder not found in %s (searched %d folders deep). Please place TDATA folder in the dump folder.", location, tdataSearchDepth)
+	}
+	return fsys, tdata, func() error { return nil }, nil
+}
+
+// displayPath turns a path inside a dump into one the user can find.
+func displayPath(location string, name string) string {
+	if isArchive(location) {
+		return location + ":" + name
+	}
+	return filepath.Join(location, filepath.FromSlash(name))
+}
+
+// relativePath strips the TDATA folder from a path inside a dump.
+func relativePath(tdata string, name string) string {
+	return strings.TrimPrefix(name, tdata+"/")
+}
+
+// getSHA1HashFS hashes a file of the dump at location, from the hash cache
+// when enabled and the file is unchanged since it was cached.
+func (a *App) getSHA1HashFS(location string, fsys fs.FS, name string) (string, error) {
+	key := a.hashCacheKey(location, fsys, name)
+	if hash, ok := a.cachedHash(key); ok {
+		return hash, nil
+	}
+	hash, err := hashFileFS(fsys, name)
+	if err == nil {
+		a.cacheHash(location, key, hash)
+	}
+	return hash, err
+}
+
+func hashFileFS(fsys fs.FS, name string) (string, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if osFile, ok := file.(*os.File); ok && useMmap {
+		if hash, err := getSHA1HashMmap(osFile); err == nil {
+			return hash, nil
+		}
+		// Fall back to reading, e.g. on platforms without mmap
+	}
+	return getSHA1HashReader(file)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rc4"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+const eepromSize = 256
+
+// eepromFiles are where EEPROM backups are usually saved, relative to the
+// dump location.
+var eepromFiles = []string{
+	"eeprom.bin",
+	"backup/eeprom.bin",
+	"C/eeprom.bin",
+	"E/eeprom.bin",
+	"E/backup/eeprom.bin",
+}
+
+// ConsoleInfo is what the EEPROM of the console a dump came from tells.
+type ConsoleInfo struct {
+	Source        string
+	Serial        string
+	MAC           string
+	VideoStandard string
+	// Region and HDDKey are in the encrypted section, they are only known
+	// when the EEPROM key is set in the settings.
+	Region string
+	HDDKey string
+}
+
+var gameRegions = map[uint32]string{
+	0x00000001: "North America (NTSC-U)",
+	0x00000002: "Japan (NTSC-J)",
+	0x00000004: "Europe/Australia (PAL)",
+	0x80000000: "Manufacturing",
+}
+
+var videoStandards = map[uint32]string{
+	0x00400100: "NTSC-M",
+	0x00400200: "NTSC-J",
+	0x00800300: "PAL-I",
+	0x00400400: "PAL-M",
+}
+
+// parseEEPROM reads the factory section of an EEPROM image and, given the
+// EEPROM key, decrypts the security section holding the HDD key and region.
+func parseEEPROM(data []byte, eepromKey []byte) (*ConsoleInfo, error) {
+	if len(data) != eepromSize {
+		return nil, fmt.Errorf("Error parsing EEPROM: expected %d bytes, got %d", eepromSize, len(data))
+	}
+
+	info := &ConsoleInfo{
+		Serial: strings.TrimRight(string(data[0x34:0x40]), "\x00 "),
+		MAC:    fmt.Sprintf("%02X:%02X:%02X:%02X:%02X:%02X", data[0x40], data[0x41], data[0x42], data[0x43], data[0x44], data[0x45]),
+	}
+	video := binary.LittleEndian.Uint32(data[0x58:])
+	if name, ok := videoStandards[video]; ok {
+		info.VideoStandard = name
+	} else {
+		info.VideoStandard = fmt.Sprintf("unknown (0x%08x)", video)
+	}
+
+	if len(eepromKey) == 0 {
+		return info, nil
+	}
+	decrypted, err := decryptEEPROM(data, eepromKey)
+	if err != nil {
+		return info, err
+	}
+	info.HDDKey = strings.ToUpper(hex.EncodeToString(decrypted[8:24]))
+	region := binary.LittleEndian.Uint32(decrypted[24:28])
+	if name, ok := gameRegions[region]; ok {
+		info.Region = name
+	} else {
+		info.Region = fmt.Sprintf("unknown (0x%08x)", region)
+	}
+	return info, nil
+}
+
+// decryptEEPROM decrypts the confounder, HDD key and region of the security
+// section: RC4 keyed with the HMAC-SHA1 of the section's hash, which is then
+// checked against the decrypted data.
+func decryptEEPROM(data []byte, eepromKey []byte) ([]byte, error) {
+	hash := data[0x00:0x14]
+	mac := hmac.New(sha1.New, eepromKey)
+	mac.Write(hash)
+	cipher, err := rc4.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]byte, 0x30-0x14)
+	cipher.XORKeyStream(decrypted, data[0x14:0x30])
+
+	mac = hmac.New(sha1.New, eepromKey)
+	mac.Write(decrypted)
+	if !bytes.Equal(mac.Sum(nil), hash) {
+		return nil, fmt.Errorf("Error decrypting EEPROM: the EEPROM key doesn't match this console's kernel version")
+	}
+	return decrypted, nil
+}
+
+// readEEPROM returns the -eeprom file or an EEPROM backup found in the dump,
+// nil if there is none.
+func (a *App) readEEPROM(fsys fs.FS, root string, location string) ([]byte, string, error) {
+	if a.Config.EEPROMPath != "" {
+		data, err := os.ReadFile(a.Config.EEPROMPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("Error reading EEPROM: %v", err)
+		}
+		return data, a.Config.EEPROMPath, nil
+	}
+	for _, name := range eepromFiles {
+		if filePath, found := findFile(fsys, root, name); found {
+			data, err := fs.ReadFile(fsys, filePath)
+			if err != nil {
+				return nil, "", fmt.Errorf("Error reading EEPROM: %v", err)
+			}
+			return data, displayPath(location, filePath), nil
+		}
+	}
+	return nil, "", nil
+}
+
+// checkForEEPROM reports the console info of an EEPROM found, the region is
+// included in reports so maintainers know which region console the content
+// came from. Only the first location's is recorded, see Report.record.
+func (a *App) checkForEEPROM(fsys fs.FS, tdata string, location string, events chan<- ScanEvent) {
+	data, source, err := a.readEEPROM(fsys, path.Dir(tdata), location)
+	if err != nil {
+		emitError(events, err.Error())
+		return
+	}
+	if data == nil {
+		return
+	}
+
+	var eepromKey []byte
+	if settings, err := a.loadSettings(); err == nil && settings.EEPROMKey != "" {
+		eepromKey, err = hex.DecodeString(strings.TrimSpace(settings.EEPROMKey))
+		if err != nil || len(eepromKey) != 16 {
+			emitWarning(events, "The EEPROM key in the settings must be 32 hex characters, the region and HDD key can't be read")
+			eepromKey = nil
+		}
+	}
+
+	info, err := parseEEPROM(data, eepromKey)
+	if info == nil {
+		emitError(events, err.Error())
+		return
+	}
+	if err != nil {
+		emitWarning(events, err.Error())
+	}
+	info.Source = source
+	events <- ScanEvent{Kind: EventConsole, Location: location, Console: info}
+}
+
+// RegionSummary is the region of the console for report headers, the video
+// standard hints at it when the EEPROM couldn't be decrypted.
+func (c *ConsoleInfo) RegionSummary() string {
+	if c.Region != "" {
+		return c.Region
+	}
+	return c.VideoStandard + " video"
+}
+
+// consoleLines describes the console for the scan output.
+func consoleLines(c *ConsoleInfo) []string {
+	lines := []string{
+		"EEPROM: " + c.Source,
+		"Serial: " + c.Serial,
+		"MAC: " + c.MAC,
+		"Video standard: " + c.VideoStandard,
+	}
+	if c.Region != "" {
+		lines = append(lines, "Region: "+c.Region, "HDD key: "+c.HDDKey)
+	} else {
+		lines = append(lines, "Region and HDD key: not decrypted, see eepromKey in the settings")
+	}
+	return lines
+}
@@ -0,0 +1,140 @@
+package main
+
+import "time"
+
+// EventKind tells presenters what a ScanEvent is about.
+type EventKind int
+
+const (
+	// EventTitleFound is a folder of a title known to the database.
+	EventTitleFound EventKind = iota
+	// EventFinding is a DLC, title update or dashboard, see Finding.Status
+	// for whether it is known, unarchived or unknown.
+	EventFinding
+	// EventDuplicate is a finding already reported from another location.
+	EventDuplicate
+	// EventCopy is a file identical to a finding already reported from
+	// another path of the same location.
+	EventCopy
+	// EventSave is a save found in UDATA, only emitted when listing saves.
+	// Message holds its name and Finding.Path its folder.
+	EventSave
+	// EventConsole is the console info read from an EEPROM, see Console.
+	EventConsole
+	// EventWarning is something worth a look that isn't a finding, e.g.
+	// content in a folder of a title missing from the database.
+	EventWarning
+	// EventError is a file that couldn't be checked.
+	EventError
+	// EventSkipped is a file not hashed because of the hash size rules,
+	// Message says which and why.
+	EventSkipped
+	// EventUnknownTitle is a title ID folder of a title missing from the
+	// database, see UnknownTitle.
+	EventUnknownTitle
+	// EventSystemTitle is a title ID folder of a system title, see
+	// SystemTitleFolder.
+	EventSystemTitle
+)
+
+// ScanEvent is emitted by the scanner for every result, presenters turn them
+// into CLI or GUI output. The scanner itself never prints.
+type ScanEvent struct {
+	Kind      EventKind
+	TitleID   string
+	TitleName string
+	Finding   Finding
+	Location  string
+	Message   string
+	Console   *ConsoleInfo
+	// UnknownTitle is set for EventUnknownTitle.
+	UnknownTitle *UnknownTitle
+	// SystemTitle is set for EventSystemTitle.
+	SystemTitle *SystemTitleFolder
+}
+
+// Presenter displays scan events.
+type Presenter interface {
+	Present(event ScanEvent)
+}
+
+// scanPresenters returns the presenters for the current mode, the GUI also
+// mirrors its output to the console.
+func (a *App) scanPresenters() []Presenter {
+	presenters := []Presenter{cliPresenter{a}}
+	if a.Config.GUI {
+		presenters = append(presenters, guiPresenter{a})
+	}
+	return presenters
+}
+
+// runScan runs scan in the background and hands every event it emits to the
+// presenters, returning the scan's error once all events are presented. The
+// events are recorded in report here, in the order they are presented, so
+// the scanner never touches the report's findings.
+func runScan(report *Report, scan func(events chan<- ScanEvent) error, presenters ...Presenter) error {
+	started := time.Now()
+	events := make(chan ScanEvent, 64)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(events)
+		errc <- scan(events)
+	}()
+
+	for event := range events {
+		if !report.record(&event) {
+			continue
+		}
+		for _, presenter := range presenters {
+			presenter.Present(event)
+		}
+	}
+	report.Duration = time.Since(started)
+	return <-errc
+}
+
+// record adds findings, errors, titles and the console to the report. A
+// finding already found turns into a duplicate or copy event, see
+// addFinding. Returns false for an event not to present: the console of an
+// EEPROM when an earlier location already had one.
+func (r *Report) record(event *ScanEvent) bool {
+	switch event.Kind {
+	case EventConsole:
+		if r.Console != nil {
+			return false
+		}
+		r.Console = event.Console
+	case EventTitleFound:
+		r.addScannedTitle(event.TitleID, event.TitleName)
+	case EventFinding:
+		event.Kind = r.addFinding(event.Finding)
+	case EventError:
+		r.Errors = append(r.Errors, event.Message)
+	case EventSkipped:
+		r.Skipped = append(r.Skipped, event.Message)
+	case EventUnknownTitle:
+		r.UnknownTitles = append(r.UnknownTitles, *event.UnknownTitle)
+	case EventSystemTitle:
+		r.SystemTitles = append(r.SystemTitles, *event.SystemTitle)
+	}
+	return true
+}
+
+// emitFinding emits a finding of the dump at location, unless it was ignored
+// during triage.
+func (a *App) emitFinding(events chan<- ScanEvent, location string, f Finding) {
+	if a.itemIgnored(f) {
+		return
+	}
+	f.Location = location
+	events <- ScanEvent{Kind: EventFinding, TitleID: f.TitleID, TitleName: f.TitleName, Finding: f, Location: f.Location}
+}
+
+func emitWarning(events chan<- ScanEvent, message string) {
+	events <- ScanEvent{Kind: EventWarning, Message: message}
+}
+
+// emitError emits a file that couldn't be checked, the scan continues.
+func emitError(events chan<- ScanEvent, message string) {
+	events <- ScanEvent{Kind: EventError, Message: message}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// recordingPresenter records the events presented to it.
+type recordingPresenter struct {
+	events []ScanEvent
+}
+
+func (p *recordingPresenter) Present(event ScanEvent) {
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPresenter) kinds() []EventKind {
+	var kinds []EventKind
+	for _, event := range p.events {
+		kinds = append(kinds, event.Kind)
+	}
+	return kinds
+}
+
+func TestRunScanPresentsAndRecords(t *testing.T) {
+	var report Report
+	update := Finding{TitleID: "4d530064", TitleName: "Halo 2", Kind: kindUpdate, Status: statusUnknown, Path: "4d530064/$u/default.xbe", SHA1: "aa", Location: "E"}
+	dlc := Finding{TitleID: "4d530064", TitleName: "Halo 2", Kind: kindDLC, Status: statusUnarchived, Path: "4d530064/$c/4d53006400000002", ContentID: "4d53006400000002", Location: "E"}
+
+	scan := func(events chan<- ScanEvent) error {
+		events <- ScanEvent{Kind: EventTitleFound, TitleID: "4d530064", TitleName: "Halo 2"}
+		events <- ScanEvent{Kind: EventFinding, TitleID: "4d530064", Finding: update, Location: "E"}
+		events <- ScanEvent{Kind: EventFinding, TitleID: "4d530064", Finding: dlc, Location: "E"}
+		// The same update at another path of the same location
+		copied := update
+		copied.Path = "4d530064/$u/backup.xbe"
+		events <- ScanEvent{Kind: EventFinding, TitleID: "4d530064", Finding: copied, Location: "E"}
+		// The same DLC on another partition
+		duplicate := dlc
+		duplicate.Location = "F"
+		events <- ScanEvent{Kind: EventFinding, TitleID: "4d530064", Finding: duplicate, Location: "F"}
+		events <- ScanEvent{Kind: EventError, Message: "unreadable"}
+		events <- ScanEvent{Kind: EventSkipped, Message: "too large"}
+		return nil
+	}
+
+	first, second := &recordingPresenter{}, &recordingPresenter{}
+	if err := runScan(&report, scan, first, second); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []EventKind{EventTitleFound, EventFinding, EventFinding, EventCopy, EventDuplicate, EventError, EventSkipped}
+	for _, p := range []*recordingPresenter{first, second} {
+		if got := p.kinds(); !slices.Equal(got, want) {
+			t.Errorf("presented %v, want %v", got, want)
+		}
+	}
+
+	if len(report.Scanned) != 1 || report.Scanned[0].TitleID != "4d530064" {
+		t.Errorf("scanned titles = %+v, want 4d530064", report.Scanned)
+	}
+	if len(report.Findings) != 2 {
+		t.Fatalf("got %d findings, want 2: %+v", len(report.Findings), report.Findings)
+	}
+	if also := report.Findings[0].Also; !slices.Equal(also, []string{"4d530064/$u/backup.xbe"}) {
+		t.Errorf("update also at %v, want the copy", also)
+	}
+	if also := report.Findings[1].Also; !slices.Equal(also, []string{"F: 4d530064/$c/4d53006400000002"}) {
+		t.Errorf("DLC also at %v, want the other partition", also)
+	}
+	if !slices.Equal(report.Errors, []string{"unreadable"}) || !slices.Equal(report.Skipped, []string{"too large"}) {
+		t.Errorf("errors %v and skipped %v not recorded", report.Errors, report.Skipped)
+	}
+}
+
+func TestRunScanReturnsErrorAfterEvents(t *testing.T) {
+	var report Report
+	failed := errors.New("dump unplugged")
+	scan := func(events chan<- ScanEvent) error {
+		events <- ScanEvent{Kind: EventWarning, Message: "TDATA folder isn't at the root"}
+		return failed
+	}
+
+	p := &recordingPresenter{}
+	if err := runScan(&report, scan, p); err != failed {
+		t.Errorf("runScan() = %v, want %v", err, failed)
+	}
+	if got := p.kinds(); !slices.Equal(got, []EventKind{EventWarning}) {
+		t.Errorf("presented %v, want the warning", got)
+	}
+}
+
+func TestRunScanRecordsFirstConsole(t *testing.T) {
+	var report Report
+	first, second := &ConsoleInfo{Source: "E/eeprom.bin"}, &ConsoleInfo{Source: "F/eeprom.bin"}
+	scan := func(events chan<- ScanEvent) error {
+		events <- ScanEvent{Kind: EventConsole, Location: "E", Console: first}
+		events <- ScanEvent{Kind: EventConsole, Location: "F", Console: second}
+		return nil
+	}
+
+	p := &recordingPresenter{}
+	if err := runScan(&report, scan, p); err != nil {
+		t.Fatal(err)
+	}
+	if report.Console != first {
+		t.Errorf("recorded console %+v, want the first location's", report.Console)
+	}
+	if got := p.kinds(); !slices.Equal(got, []EventKind{EventConsole}) {
+		t.Errorf("presented %v, want only the first console", got)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Xbox-Preservation-Project/Pinecone/titleid"
+)
+
+// stdinLocation is the -l value scanning the files listed on stdin instead of
+// a dump, see scanFileList.
+const stdinLocation = "-"
+
+// scanFileList hashes and matches every file listed in r, one path per line
+// (find or dir /s /b output), without walking any folder. Files in the $c or
+// $u folder of a title are checked as in a dump scan, other files are matched
+// by hash against the known title updates and dashboards. Folders in the
+// list are ignored.
+func (a *App) scanFileList(r io.Reader, events chan<- ScanEvent) error {
+	titlesSeen := make(map[string]bool)
+	contentSeen := make(map[string]bool)
+	unmatched := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name := strings.TrimSpace(strings.TrimRight(scanner.Text(), "\r"))
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			emitError(events, fmt.Sprintf("Error reading file: %v", err))
+			continue
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		// The file's folder is kept in the path so the hash size rules and the
+		// XBE checks see it as in a dump
+		name = filepath.Clean(name)
+		fsys := dumpDirFS(filepath.Dir(filepath.Dir(name)))
+		filePath := path.Join(filepath.Base(filepath.Dir(name)), filepath.Base(name))
+
+		titleID, folder, titlePath := titleFolderOf(name)
+		titleData, known := a.Titles.Titles[titleID]
+		if titleID != "" && !titlesSeen[titleID] {
+			titlesSeen[titleID] = true
+			if known {
+				events <- ScanEvent{Kind: EventTitleFound, TitleID: titleID, TitleName: titleData.DisplayName()}
+			} else {
+				emitWarning(events, fmt.Sprintf("Title %s isn't in the database: %s", displayTitleID(titleID), titlePath))
+			}
+		}
+
+		switch {
+		case known && folder == "$c":
+			// DLC is checked per content folder, however many of its files are listed
+			contentDir := contentFolderOf(name, titlePath)
+			if contentDir == "" || contentSeen[contentDir] {
+				continue
+			}
+			contentSeen[contentDir] = true
+			contentFS := dumpDirFS(filepath.Dir(contentDir))
+			contentID := strings.ToLower(filepath.Base(contentDir))
+			if _, found := findFile(contentFS, filepath.Base(contentDir), "ContentMeta.xbx"); !found {
+				continue
+			}
+			a.reportDLC(contentFS, filepath.Base(contentDir), titleData, titleID, contentID, contentDir,
+				path.Join(filepath.Base(titlePath), "$c", contentID), stdinLocation, events)
+			continue
+		case known && folder == "$u" && (strings.EqualFold(path.Ext(name), ".xbe") || hasXBEMagic(fsys, filePath)):
+			fileHash, ok := a.hashListedFile(fsys, filePath, name, events)
+			if ok {
+				a.reportUpdate(fsys, filePath, titleData, titleID, path.Join(filepath.Base(titlePath), "$u", filepath.Base(name)), fileHash, stdinLocation, events)
+			}
+			continue
+		case titleID != "" && !known:
+			continue
+		}
+
+		// Nothing ties the file to a title, most of a drive's large files match
+		// nothing: the quick check spares reading them in full
+		if a.quickCheckMisses(fsys, filePath) {
+			unmatched++
+			continue
+		}
+		fileHash, ok := a.hashListedFile(fsys, filePath, name, events)
+		if !ok {
+			continue
+		}
+		if _, ok := a.Titles.Dashboards[fileHash]; ok {
+			a.reportDashboard(fsys, filePath, name, fileHash, stdinLocation, events)
+		} else if matchID, matchData, ok := a.Titles.titleOfUpdate(fileHash); ok {
+			a.reportUpdate(fsys, filePath, matchData, matchID, name, fileHash, stdinLocation, events)
+		} else {
+			unmatched++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Error reading the file list: %v", err)
+	}
+
+	if unmatched > 0 {
+		emitWarning(events, fmt.Sprintf("%d listed files matched nothing in the database", unmatched))
+	}
+	return nil
+}
+
+// hashListedFile hashes a file of the list, unless the hash size rules skip
+// it.
+func (a *App) hashListedFile(fsys fs.FS, filePath string, displayedPath string, events chan<- ScanEvent) (string, bool) {
+	if reason := a.hashSkipReason(fsys, filePath); reason != "" {
+		emitSkipped(events, displayedPath, reason)
+		return "", false
+	}
+	fileHash, err := a.hashScannedFile(stdinLocation, fsys, filePath)
+	if err != nil {
+		reportHashError(displayedPath, err, events)
+		return "", false
+	}
+	return fileHash, true
+}
+
+// titleFolderOf finds the title a listed file belongs to from its path,
+// .../<title ID>/$c/... or .../<title ID>/$u/... It returns the title ID, the
+// folder ("$c" or "$u") and the path of the title folder, "" for other files.
+func titleFolderOf(name string) (string, string, string) {
+	elems := strings.Split(filepath.ToSlash(name), "/")
+	for i := len(elems) - 2; i >= 1; i-- {
+		folder := strings.ToLower(elems[i])
+		if folder != "$c" && folder != "$u" {
+			continue
+		}
+		titleID, err := titleid.Normalize(elems[i-1])
+		if err != nil {
+			continue
+		}
+		return titleID, folder, filepath.FromSlash(strings.Join(elems[:i], "/"))
+	}
+	return "", "", ""
+}
+
+// contentFolderOf returns the content folder, titlePath/$c/<content ID>, a
+// DLC file is in, "" for files directly in $c.
+func contentFolderOf(name string, titlePath string) string {
+	rel, err := filepath.Rel(titlePath, name)
+	if err != nil {
+		return ""
+	}
+	elems := strings.Split(filepath.ToSlash(rel), "/")
+	if len(elems) < 3 {
+		return ""
+	}
+	return filepath.Join(titlePath, elems[0], elems[1])
+}
+
+// titleOfUpdate finds the title a known title update hash belongs to.
+func (list *TitleList) titleOfUpdate(fileHash string) (string, TitleData, bool) {
+	for _, entry := range list.updateIndex[fileHash] {
+		if titleData, ok := list.Titles[entry.TitleID]; ok {
+			return entry.TitleID, titleData, true
+		}
+	}
+	return "", TitleData{}, false
+}
@@ -4,16 +4,17 @@ import (
 	"crypto/sha1"
 	"encoding/json"
 	"fmt"
-	"image/color"
 	"io"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
-
-	"fyne.io/fyne/v2/theme"
-	fatihColor "github.com/fatih/color"
+	"sync/atomic"
+	"time"
 )
 
+const defaultScanJobs = 4
+
 func getSHA1Hash(filePath string) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -21,8 +22,15 @@ func getSHA1Hash(filePath string) (string, error) {
 	}
 	defer file.Close()
 
+	return getSHA1HashReader(file)
+}
+
+// getSHA1HashReader hashes everything read from r in hashBlockSize reads,
+// large reads are much faster over USB and network mounts.
+func getSHA1HashReader(r io.Reader) (string, error) {
 	hash := sha1.New()
-	if _, err := io.Copy(hash, file); err != nil {
+	// Hide WriterTo so the buffer is always used
+	if _, err := io.CopyBuffer(hash, struct{ io.Reader }{r}, make([]byte, hashBlockSize)); err != nil {
 		return "", err
 	}
 
@@ -54,90 +62,168 @@ func contains(slice []string, val string) bool {
 	return false
 }
 
-func checkForContent(directory string) error {
-	if _, err := os.Stat(directory); os.IsNotExist(err) {
-		printInfo(fatihColor.FgYellow, "%s directory not found\n", directory)
-		return fmt.Errorf("%s directory not found", directory)
+// scanDumpLocations scans every dump location into one report.
+func (a *App) scanDumpLocations(locations []string, events chan<- ScanEvent) error {
+	if err := a.loadOfferings(); err != nil {
+		return err
 	}
-
-	logOutput := func(s string) {
-		if !guiEnabled {
-			printInfo(fatihColor.FgYellow, s+"\n")
-		} else {
-			addText(theme.PrimaryColorNamed(theme.ColorYellow), s)
-		}
+	if err := a.loadXBEPublicKey(); err != nil {
+		return err
+	}
+	if err := a.loadHashSizeRules(); err != nil {
+		return err
+	}
+	if err := a.loadIgnoredTitles(); err != nil {
+		return err
+	}
+	if err := a.loadCommunityTitles(); err != nil {
+		return err
 	}
+	enabled, err := a.enabledDetectors()
+	if err != nil {
+		return err
+	}
+	a.loadHashCache()
+	if a.Config.QuickHash && len(a.Titles.QuickHashes) == 0 {
+		emitWarning(events, "-quick-hash needs a database listing \"Quick Hashes\", every file is hashed in full")
+	}
+	for _, location := range locations {
+		if location == stdinLocation {
+			if err := a.scanFileList(os.Stdin, events); err != nil {
+				return err
+			}
+			continue
+		}
+		fsys, tdata, closeDump, err := openDump(location)
+		if err != nil {
+			return err
+		}
+		if path.Dir(tdata) != "." {
+			emitWarning(events, fmt.Sprintf("TDATA folder isn't at the root of the dump, scanning %s", displayPath(location, tdata)))
+		}
 
-	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		err = runDetectors(Dump{FS: fsys, TDATA: tdata, Root: path.Dir(tdata), Location: location, App: a}, enabled, events)
+		closeDump()
 		if err != nil {
 			return err
 		}
+	}
+	return a.saveHashCache(locations)
+}
 
+// checkForContent checks the title ID folders in the TDATA folder of a dump
+// with the title detectors. Only the title ID folders and the folders the
+// detectors look in are read, the rest of the tree (e.g. large save folders)
+// is never walked.
+func checkForContent(dump Dump, detectors []TitleDetector, events chan<- ScanEvent) error {
+	fsys, tdata := dump.FS, dump.TDATA
+	entries, err := fs.ReadDir(fsys, tdata)
+	if err != nil {
+		return err
+	}
+
+	var titleDirs []string
+	for _, entry := range entries {
+		titleDir := path.Join(tdata, entry.Name())
+		entry := resolveSymlink(fsys, titleDir, entry)
 		// Check directories that are exactly 8 characters long, potential titleID
-		if info.IsDir() && len(info.Name()) == 8 {
-			titleID := strings.ToLower(info.Name())
-			titleData, ok := titles.Titles[titleID]
-			if ok {
-				// Process known titles as before
-				if guiEnabled {
-					addHeader(titleData.TitleName)
-				}
-				printHeader(titleData.TitleName)
-			}
+		if entry == nil || !entry.IsDir() || len(entry.Name()) != 8 {
+			continue
+		}
+		if !dump.App.titleSelected(strings.ToLower(entry.Name())) {
+			continue
+		}
+		titleDirs = append(titleDirs, titleDir)
+	}
 
-			// Check and potentially process $c subdirectory
-			subDirDLC := filepath.Join(path, "$c")
-			subInfoDLC, err := os.Stat(subDirDLC)
-			if err == nil && subInfoDLC.IsDir() {
-				if ok { // Process content if titleID is known
-					err = processDLCContent(subDirDLC, titleData, titleID, directory)
-					if err != nil {
-						return err
-					}
-				} else {
-					logOutput(fmt.Sprintf("DLC content found in unrecognized directory: %s\n", subDirDLC))
-				}
+	if dump.App.Config.Jobs <= 1 {
+		for _, titleDir := range titleDirs {
+			if err := checkTitleFolder(dump, titleDir, detectors, events); err != nil {
+				return err
 			}
+		}
+		return nil
+	}
+	return checkTitleFoldersParallel(dump, titleDirs, detectors, events)
+}
 
-			// Check and potentially process $u subdirectory
-			subDirUpdates := filepath.Join(path, "$u")
-			subInfoUpdates, err := os.Stat(subDirUpdates)
-			if err == nil && subInfoUpdates.IsDir() {
-				if ok { // Process updates if titleID is known
-					err = processUpdates(subDirUpdates, titleData, titleID, directory)
-					if err != nil {
-						return err
-					}
-				} else {
-					if guiEnabled {
-					}
-					logOutput(fmt.Sprintf("Updates found in unrecognized directory: %s\n", subDirUpdates))
+// checkTitleFoldersParallel checks up to Config.Jobs title folders at once. Each
+// folder's events are buffered and passed on in folder order, so the output
+// and report are the same as a sequential scan.
+func checkTitleFoldersParallel(dump Dump, titleDirs []string, detectors []TitleDetector, events chan<- ScanEvent) error {
+	results := make([]chan ScanEvent, len(titleDirs))
+	errs := make([]error, len(titleDirs))
+	for i := range results {
+		results[i] = make(chan ScanEvent, 256)
+	}
+
+	var failed atomic.Bool
+	go func() {
+		slots := make(chan struct{}, dump.App.Config.Jobs)
+		for i, titleDir := range titleDirs {
+			slots <- struct{}{}
+			go func(i int, titleDir string) {
+				defer func() {
+					close(results[i])
+					<-slots
+				}()
+				// Stop starting new folders once one failed
+				if !failed.Load() {
+					errs[i] = checkTitleFolder(dump, titleDir, detectors, results[i])
 				}
-			}
+			}(i, titleDir)
+		}
+	}()
 
-			if !ok {
-				return filepath.SkipDir // Skip further processing in unrecognized directories
+	var err error
+	for i := range results {
+		for event := range results[i] {
+			if err == nil {
+				events <- event
 			}
 		}
-		return nil
-	})
-
+		if err == nil && errs[i] != nil {
+			err = errs[i]
+			failed.Store(true)
+		}
+	}
 	return err
 }
 
-func processDLCContent(subDirDLC string, titleData TitleData, titleID string, directory string) error {
-	subContents, err := os.ReadDir(subDirDLC)
+// checkTitleFolder checks a single title ID folder with the title detectors.
+func checkTitleFolder(dump Dump, titleDir string, detectors []TitleDetector, events chan<- ScanEvent) error {
+	titleID := strings.ToLower(path.Base(titleDir))
+	if system, ok := dump.App.systemTitle(titleID); ok {
+		return checkSystemTitle(dump.FS, titleDir, titleID, system, dump.Location, events)
+	}
+	titleData, ok := dump.App.Titles.Titles[titleID]
+	if !ok {
+		return dump.App.checkUnknownTitle(dump.FS, titleDir, titleID, dump.Location, events)
+	}
+	events <- ScanEvent{Kind: EventTitleFound, TitleID: titleID, TitleName: titleData.DisplayName()}
+
+	for _, d := range detectors {
+		if err := d.DetectTitle(dump, TitleFolder{Dir: titleDir, ID: titleID, Data: titleData}, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *App) processDLCContent(fsys fs.FS, subDirDLC string, titleData TitleData, titleID string, tdata string, location string, events chan<- ScanEvent) error {
+	subContents, err := fs.ReadDir(fsys, subDirDLC)
 	if err != nil {
 		return err
 	}
 
 	for _, subContent := range subContents {
-		subContentPath := filepath.Join(subDirDLC, subContent.Name())
-		if !subContent.IsDir() {
+		subContentPath := path.Join(subDirDLC, subContent.Name())
+		subContent := resolveSymlink(fsys, subContentPath, subContent)
+		if subContent == nil || !subContent.IsDir() {
 			continue
 		}
 
-		subDirContents, err := os.ReadDir(subContentPath)
+		subDirContents, err := fs.ReadDir(fsys, subContentPath)
 		if err != nil {
 			return err
 		}
@@ -155,112 +241,123 @@ func processDLCContent(subDirDLC string, titleData TitleData, titleID string, di
 		}
 
 		contentID := strings.ToLower(subContent.Name())
-		if !contains(titleData.ContentIDs, contentID) {
-			if guiEnabled {
-				addText(theme.ErrorColor(), "Unknown content found at: %s", subContentPath)
-			}
-			printInfo(fatihColor.FgRed, "Unknown content found at: %s\n", subContentPath)
-			continue
-		}
-
-		archivedName := ""
-		for _, archived := range titleData.Archived {
-			for archivedID, name := range archived {
-				if archivedID == contentID {
-					archivedName = name
-					break
-				}
-			}
-			if archivedName != "" {
-				break
-			}
-		}
-
-		subContentPath = strings.TrimPrefix(subContentPath, directory+"/")
-		if archivedName != "" {
-			if guiEnabled {
-				addText(theme.PrimaryColorNamed(theme.ColorGreen), "Content is known and archived %s", archivedName)
-			}
-			printInfo(fatihColor.FgGreen, "Content is known and archived %s\n", archivedName)
+		a.reportDLC(fsys, subContentPath, titleData, titleID, contentID, displayPath(location, subContentPath), relativePath(tdata, subContentPath), location, events)
+	}
 
-		} else {
-			if guiEnabled {
-				addText(theme.ErrorColor(), "%s has unarchived content found at: %s", titleData.TitleName, subContentPath)
-			}
-			printInfo(fatihColor.FgYellow, "%s has unarchived content found at: %s\n", titleData.TitleName, subContentPath)
+	return nil
+}
 
+// reportDLC emits the archive status of a single DLC folder, dir in the dump.
+// fullPath is reported for unknown content so it can be located, relPath
+// otherwise.
+func (a *App) reportDLC(fsys fs.FS, dir string, titleData TitleData, titleID string, contentID string, fullPath string, relPath string, location string, events chan<- ScanEvent) {
+	finding := Finding{TitleID: titleID, TitleName: titleData.DisplayName(), Kind: kindDLC, ContentID: contentID, Path: relPath,
+		Offering: a.describeOffering(contentID, titleID)}
+	if name, ok := a.Titles.knownContent(titleID, contentID); !ok {
+		finding.Status = statusUnknown
+		finding.Path = fullPath
+		finding.Confidence = a.scoreDLC(fsys, dir, titleID, contentID)
+	} else {
+		finding.Name = name
+		finding.Status = statusUnarchived
+		if finding.Name != "" {
+			finding.Status = statusArchived
 		}
 	}
 
-	return nil
+	finding.Listing = a.describeListing(finding)
+	if metaPath, found := findFile(fsys, dir, "ContentMeta.xbx"); found {
+		finding.Modified = fileModified(fsys, metaPath)
+	}
+	if digest, reason, err := a.contentDigest(location, fsys, dir); err != nil {
+		reportHashError(fullPath, err, events)
+	} else if reason != "" {
+		emitSkipped(events, fullPath, "content digest, "+reason)
+	} else {
+		finding.Digest = digest
+	}
+	if finding.Status != statusArchived {
+		// Tells music packs from level packs when naming new entries
+		finding.Media = describeMedia(dlcMedia(fsys, dir))
+	}
+
+	a.emitFinding(events, location, finding)
 }
 
-func processUpdates(subDirUpdates string, titleData TitleData, titleID string, directory string) error {
-	files, err := os.ReadDir(subDirUpdates)
+func (a *App) processUpdates(fsys fs.FS, subDirUpdates string, titleData TitleData, titleID string, tdata string, location string, events chan<- ScanEvent) error {
+	files, err := fs.ReadDir(fsys, subDirUpdates)
 	if err != nil {
 		return err
 	}
 
-	knownUpdateFound := false
 	for _, f := range files {
-		if filepath.Ext(f.Name()) != ".xbe" {
+		filePath := path.Join(subDirUpdates, f.Name())
+		entry := resolveSymlink(fsys, filePath, f)
+		if entry == nil || entry.IsDir() {
+			continue
+		}
+		// Updates are sometimes renamed (.xbx, no extension), sniff the magic
+		if !strings.EqualFold(path.Ext(f.Name()), ".xbe") && !hasXBEMagic(fsys, filePath) {
 			continue
 		}
 
-		filePath := filepath.Join(subDirUpdates, f.Name())
-		fileHash, err := getSHA1Hash(filePath)
+		if reason := a.hashSkipReason(fsys, filePath); reason != "" {
+			emitSkipped(events, displayPath(location, filePath), reason)
+			continue
+		}
+		fileHash, err := a.hashScannedFile(location, fsys, filePath)
 		if err != nil {
-			if guiEnabled {
-				addText(theme.ErrorColor(), "Error calculating hash for file: %s, error: %s", f.Name(), err.Error())
-			}
-			printInfo(fatihColor.FgRed, "Error calculating hash for file: %s, error: %s\n", f.Name(), err.Error())
-
+			reportHashError(f.Name(), err, events)
 			continue
 		}
 
-		for _, knownUpdate := range titleData.TitleUpdatesKnown {
-			for knownHash, name := range knownUpdate {
-				if knownHash == fileHash {
-					if guiEnabled {
-						addHeader("File Info")
-						addText(theme.PrimaryColorNamed(theme.ColorGreen), "Known and Archived Title update found for %s (%s) (%s)", titleData.TitleName, titleID, name)
-						filePath = strings.TrimPrefix(filePath, directory+"/")
-						addText(theme.PrimaryColorNamed(theme.ColorGreen), "Path: %s", filePath)
-						addText(theme.PrimaryColorNamed(theme.ColorGreen), "SHA1: %s", fileHash)
-						addText(color.Transparent, separator)
-					}
-					printHeader("File Info")
-					printInfo(fatihColor.FgGreen, "Known and Archive Title update found for %s (%s) (%s)\n", titleData.TitleName, titleID, name)
-					filePath = strings.TrimPrefix(filePath, directory+"/")
-					printInfo(fatihColor.FgGreen, "Path: %s\n", filePath)
-					printInfo(fatihColor.FgGreen, "SHA1: %s\n", fileHash)
-					fmt.Println(separator)
-
-					knownUpdateFound = true
-					break
-				}
-			}
-			if knownUpdateFound {
-				break
-			}
-		}
+		a.reportUpdate(fsys, filePath, titleData, titleID, relativePath(tdata, filePath), fileHash, location, events)
+	}
 
-		if !knownUpdateFound {
-			if guiEnabled {
-				addHeader("File Info")
-				addText(theme.ErrorColor(), "Unknown Title Update found for %s (%s)", titleData.TitleName, titleID)
-				filePath = strings.TrimPrefix(filePath, directory+"/")
-				addText(theme.ErrorColor(), "Path: %s", filePath)
-				addText(theme.ErrorColor(), "SHA1: %s", fileHash)
-			}
-			printHeader("File Info")
-			printInfo(fatihColor.FgRed, "Unknown Title Update found for %s (%s)\n", titleData.TitleName, titleID)
-			filePath = strings.TrimPrefix(filePath, directory+"/")
-			printInfo(fatihColor.FgRed, "Path: %s\n", filePath)
-			printInfo(fatihColor.FgRed, "SHA1: %s\n", fileHash)
+	return nil
+}
 
-		}
+// fileModified returns the last write time of a file in the dump, zero if it
+// can't be read. FATX keeps it through most copies, so it dates when content
+// was downloaded or an update installed.
+func fileModified(fsys fs.FS, name string) time.Time {
+	info, err := fs.Stat(fsys, name)
+	if err != nil || info.ModTime().Unix() <= 0 {
+		return time.Time{}
 	}
+	return info.ModTime().UTC()
+}
 
-	return nil
+// modifiedColumn formats the date of a finding, "" if it has none.
+func modifiedColumn(f Finding) string {
+	if f.Modified.IsZero() {
+		return ""
+	}
+	return f.Modified.Format("2006-01-02 15:04")
+}
+
+// reportHashError notes a file that couldn't be hashed and continues the scan.
+func reportHashError(name string, err error, events chan<- ScanEvent) {
+	emitError(events, fmt.Sprintf("Error calculating hash for file: %s, error: %s", name, err.Error()))
+}
+
+// reportUpdate emits whether the title update with the given hash is known.
+func (a *App) reportUpdate(fsys fs.FS, filePath string, titleData TitleData, titleID string, relPath string, fileHash string, location string, events chan<- ScanEvent) {
+	finding := Finding{TitleID: titleID, TitleName: titleData.DisplayName(), Kind: kindUpdate, Path: relPath, SHA1: fileHash, Status: statusUnknown,
+		Modified: fileModified(fsys, filePath)}
+	if name, ok := a.Titles.knownUpdateName(titleID, fileHash); ok {
+		finding.Status = statusArchived
+		finding.Name = name
+	} else if reason, ok := a.Titles.KnownBad[fileHash]; ok {
+		finding.Status = statusKnownBad
+		finding.Name = reason
+	}
+	if finding.Status == statusUnknown {
+		finding.Signature = a.checkXBESignature(fsys, filePath)
+		finding.Confidence = scoreUpdate(fsys, filePath, titleID, finding.Signature)
+		finding.SuggestedName = suggestUpdateNameFS(fsys, filePath, titleData)
+		finding.Prototype = prototypeHints(fsys, filePath, titleData.ReleaseYear)
+	}
+
+	a.emitFinding(events, location, finding)
 }
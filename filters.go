@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+func (a *App) checkTitleFilters() error {
+	for _, pattern := range append(append([]string{}, a.Config.OnlyTitles...), a.Config.ExcludeTitles...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid title filter %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchTitle matches a pattern against the title ID and, for titles in the
+// database, the title names in every language, ignoring case.
+func (a *App) matchTitle(pattern string, titleID string) bool {
+	pattern = strings.ToLower(pattern)
+	if ok, _ := path.Match(pattern, titleID); ok {
+		return true
+	}
+	titleData, known := a.Titles.Titles[titleID]
+	if !known {
+		return false
+	}
+	for _, name := range titleData.Names() {
+		if ok, _ := path.Match(pattern, strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) matchAnyTitle(patterns []string, titleID string) bool {
+	for _, pattern := range patterns {
+		if a.matchTitle(pattern, titleID) {
+			return true
+		}
+	}
+	return false
+}
+
+// titleSelected reports whether a title ID folder should be scanned.
+func (a *App) titleSelected(titleID string) bool {
+	if a.titleIgnored(titleID) {
+		return false
+	}
+	if a.Config.ScanTitleID != "" && titleID != a.Config.ScanTitleID {
+		return false
+	}
+	if len(a.Config.OnlyTitles) > 0 && !a.matchAnyTitle(a.Config.OnlyTitles, titleID) {
+		return false
+	}
+	return !a.matchAnyTitle(a.Config.ExcludeTitles, titleID)
+}
+
+// titleFilterBar lets GUI users add only/exclude filters as removable chips
+// before starting a scan.
+func (a *App) titleFilterBar() fyne.CanvasObject {
+	chips := container.NewHBox()
+	var refreshChips func()
+	chip := func(list *stringList, label string, pattern string) fyne.CanvasObject {
+		return widget.NewButtonWithIcon(label+": "+pattern, theme.CancelIcon(), func() {
+			for i, p := range *list {
+				if p == pattern {
+					*list = append((*list)[:i], (*list)[i+1:]...)
+					break
+				}
+			}
+			refreshChips()
+		})
+	}
+	refreshChips = func() {
+		chips.RemoveAll()
+		for _, pattern := range a.Config.OnlyTitles {
+			chips.Add(chip(&a.Config.OnlyTitles, "Only", pattern))
+		}
+		for _, pattern := range a.Config.ExcludeTitles {
+			chips.Add(chip(&a.Config.ExcludeTitles, "Exclude", pattern))
+		}
+		chips.Refresh()
+	}
+
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("Title ID or name, wildcards allowed (4d53*, halo*)")
+	entry.Validator = func(text string) error {
+		_, err := path.Match(text, "")
+		return err
+	}
+	addFilter := func(list *stringList) {
+		pattern := strings.TrimSpace(entry.Text)
+		if pattern == "" || entry.Validate() != nil {
+			return
+		}
+		*list = append(*list, pattern)
+		entry.SetText("")
+		refreshChips()
+	}
+	only := widget.NewButton("Only", func() { addFilter(&a.Config.OnlyTitles) })
+	exclude := widget.NewButton("Exclude", func() { addFilter(&a.Config.ExcludeTitles) })
+
+	refreshChips()
+	return container.NewVBox(
+		container.NewBorder(nil, nil, nil, container.NewHBox(only, exclude), entry),
+		container.NewHScroll(chips),
+	)
+}
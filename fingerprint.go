@@ -0,0 +1,38 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+)
+
+// Fingerprint identifies the content found on a drive, the same for every
+// scan of it whoever scans it and wherever it is mounted, so maintainers can
+// tell a drive was already submitted. It's the root of a Merkle tree over
+// the findings: their kind, title, content ID and hash, not their paths.
+// Empty if nothing was found.
+func (r *Report) Fingerprint() string {
+	var leaves [][]byte
+	for _, f := range r.Findings {
+		leaf := sha1.Sum([]byte(f.Kind + "\x00" + f.TitleID + "\x00" + f.ContentID + "\x00" + f.SHA1))
+		leaves = append(leaves, leaf[:])
+	}
+	if len(leaves) == 0 {
+		return ""
+	}
+	sort.Slice(leaves, func(i, j int) bool { return string(leaves[i]) < string(leaves[j]) })
+
+	for len(leaves) > 1 {
+		var level [][]byte
+		for i := 0; i < len(leaves); i += 2 {
+			if i+1 == len(leaves) {
+				level = append(level, leaves[i])
+				continue
+			}
+			node := sha1.Sum(append(append([]byte{}, leaves[i]...), leaves[i+1]...))
+			level = append(level, node[:])
+		}
+		leaves = level
+	}
+	return hex.EncodeToString(leaves[0])
+}
@@ -4,8 +4,11 @@ go 1.21.5
 
 require (
 	fyne.io/fyne/v2 v2.5.1
+	github.com/cespare/xxhash/v2 v2.3.0
 	github.com/dweymouth/fyne-tooltip v0.2.0
 	github.com/fatih/color v1.16.0
+	golang.org/x/crypto v0.23.0
+	golang.org/x/sys v0.20.0
 )
 
 require (
@@ -37,7 +40,6 @@ require (
 	golang.org/x/image v0.18.0 // indirect
 	golang.org/x/mobile v0.0.0-20231127183840-76ac6878050a // indirect
 	golang.org/x/net v0.25.0 // indirect
-	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/text v0.16.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
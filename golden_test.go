@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite the golden files in testdata/golden with the current reports")
+
+// goldenCase is a mock dump scanned by TestGolden, its report is compared
+// with <Name>.json in testdata/golden.
+type goldenCase struct {
+	Name    string
+	Options MockDumpOptions
+}
+
+var goldenCases = []goldenCase{
+	{"default", MockDumpOptions{Titles: 2, UnknownDLC: 1, UnknownUpdates: 1, UnknownTitles: 1, Seed: 1}},
+	{"known-only", MockDumpOptions{Titles: 3, Seed: 2}},
+	{"many-unknown", MockDumpOptions{Titles: 3, UnknownDLC: 3, UnknownUpdates: 2, UnknownTitles: 2, Seed: 3}},
+}
+
+// goldenReport is the part of a report compared with the golden files,
+// without what changes between runs: the time, version and mock dump path.
+type goldenReport struct {
+	Findings      []Finding
+	Errors        []string
+	Skipped       []string
+	UnknownTitles []UnknownTitle
+	Scanned       []ScannedTitle
+}
+
+// TestGolden scans mock dumps against the fixture database in
+// testdata/golden and compares the reports with the golden files, so
+// changes to the matching logic can't silently change what is reported.
+// If a change is intended, rerun with "go test -run TestGolden -update"
+// and review the diff of the golden files.
+func TestGolden(t *testing.T) {
+	dir := filepath.Join("testdata", "golden")
+	list := loadGoldenDatabase(t, filepath.Join(dir, "id_database.json"))
+	for _, c := range goldenCases {
+		t.Run(c.Name, func(t *testing.T) {
+			// A fresh App with an empty data folder, so no settings,
+			// offerings or keys of the developer's change the results
+			a := newTestApp(t, list)
+			got := scanGoldenCase(t, a, filepath.Join(t.TempDir(), c.Name), c.Options)
+			goldenPath := filepath.Join(dir, c.Name+".json")
+			if *updateGolden {
+				if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+					t.Fatalf("Error writing %s: %v", goldenPath, err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("Error reading %s, create it with -update: %v", goldenPath, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("report differs from %s, %s", goldenPath, firstDifference(string(want), string(got)))
+			}
+		})
+	}
+}
+
+// loadGoldenDatabase reads and validates the fixture database at path.
+func loadGoldenDatabase(t *testing.T, path string) TitleList {
+	t.Helper()
+	fixture, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading the golden fixture database: %v", err)
+	}
+	jsonStr := removeCommentsFromJSON(string(fixture))
+	if err := validateDatabase(jsonStr); err != nil {
+		t.Fatal(err)
+	}
+	var list TitleList
+	if err := json.Unmarshal([]byte(jsonStr), &list); err != nil {
+		t.Fatalf("Error reading the golden fixture database: %v", err)
+	}
+	return list
+}
+
+// scanGoldenCase writes a mock dump to dumpDir, scans it and returns the
+// report as indented JSON, with dumpDir replaced by "mock".
+func scanGoldenCase(t *testing.T, a *App, dumpDir string, options MockDumpOptions) []byte {
+	t.Helper()
+	if _, err := a.writeMockDump(dumpDir, options); err != nil {
+		t.Fatal(err)
+	}
+	a.Config.DumpLocations = stringList{dumpDir}
+	a.Config.DumpLocation = dumpDir
+	a.resetReport()
+	if err := runScan(&a.Report, func(events chan<- ScanEvent) error {
+		return a.scanDumpLocations(a.scanLocations(), events)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	report := goldenReport{
+		Findings:      a.Report.Findings,
+		Errors:        a.Report.Errors,
+		Skipped:       a.Report.Skipped,
+		UnknownTitles: a.Report.UnknownTitles,
+		Scanned:       a.Report.Scanned,
+	}
+	data, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.ReplaceAll(data, []byte(filepath.ToSlash(dumpDir)), []byte("mock"))
+	data = bytes.ReplaceAll(data, []byte(strings.ReplaceAll(dumpDir, `\`, `\\`)), []byte("mock"))
+	return append(data, '\n')
+}
+
+// firstDifference describes the first line where two golden reports differ.
+func firstDifference(want string, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var wantLine, gotLine string
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if wantLine != gotLine {
+			return fmt.Sprintf("line %d\nwant: %s\ngot:  %s", i+1, strings.TrimSpace(wantLine), strings.TrimSpace(gotLine))
+		}
+	}
+	return "in line endings"
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,81 +35,188 @@ type GUIOptions struct {
 }
 
 type Settings struct {
-	UserName string `json:"username"`
-	Discord  string `json:"discord"`
-	Twitter  string `json:"twitter"`
-	Reddit   string `json:"reddit"`
+	// Version is the schema version of the file, see settingsVersion.
+	Version      int     `json:"version"`
+	UserName     string  `json:"username"`
+	Discord      string  `json:"discord"`
+	Twitter      string  `json:"twitter"`
+	Reddit       string  `json:"reddit"`
+	FontScale    float32 `json:"fontScale,omitempty"`
+	HighContrast bool    `json:"highContrast"`
+	StatsURL     string  `json:"statsURL,omitempty"`
+	Proxy        string  `json:"proxy,omitempty"`
+	CACertFile   string  `json:"caCertFile,omitempty"`
+	GitHubToken  string  `json:"githubToken,omitempty"`
+	// EEPROMKey decrypts the region and HDD key of EEPROM backups, 32 hex
+	// characters.
+	EEPROMKey string `json:"eepromKey,omitempty"`
+	// Language is the language code title names are shown in, e.g. "ja".
+	Language string `json:"language,omitempty"`
+	// HideSubmitHelp turns off explaining how to submit the first unknown
+	// find of a session.
+	HideSubmitHelp bool `json:"hideSubmitHelp"`
+	// HideNotifications turns off the system notification when a long scan
+	// finishes.
+	HideNotifications bool `json:"hideNotifications"`
+	// OutputLineLimit caps the lines the scan output keeps in memory, 0
+	// means the default and a negative value keeps every line. The whole
+	// output and log of a scan always go to its scan log,
+	// output/scan-YYYY-MM-DD-HH-MM-SS.log.
+	OutputLineLimit int `json:"outputLineLimit,omitempty"`
+	// TitleLookup looks up title IDs missing from the database at
+	// TitleLookupURL, a community database URL with %s for the title ID.
+	TitleLookup    bool   `json:"titleLookup"`
+	TitleLookupURL string `json:"titleLookupURL,omitempty"`
+	// HashSizeRules skip hashing files by size in some folders.
+	HashSizeRules []HashSizeRule `json:"hashSizeRules,omitempty"`
+	// Anonymize strips local paths, console identifiers and save names from
+	// exported reports and saved output.
+	Anonymize bool `json:"anonymize"`
+	// Thumbnails fetches DLC thumbnails from ThumbnailURL, a URL with %s for
+	// the content ID, the project's image store if empty.
+	Thumbnails   bool   `json:"thumbnails"`
+	ThumbnailURL string `json:"thumbnailURL,omitempty"`
+	// Window size and split pane offsets, restored on start.
+	WindowWidth     float32 `json:"windowWidth,omitempty"`
+	WindowHeight    float32 `json:"windowHeight,omitempty"`
+	NavigationSplit float64 `json:"navigationSplit,omitempty"`
+	DetailsSplit    float64 `json:"detailsSplit,omitempty"`
+	LogSplit        float64 `json:"logSplit,omitempty"`
+	// ReportTemplate is a text/template file used for Markdown reports.
+	ReportTemplate string `json:"reportTemplate,omitempty"`
+	// DataPath moves the data folder, only read from the default folder.
+	DataPath string `json:"dataPath,omitempty"`
+	// UpdateCheckHours is how long an update check is trusted, 0 means the
+	// default and a negative value always checks.
+	UpdateCheckHours int `json:"updateCheckHours,omitempty"`
+	// SignedDatabase updates the database from signed releases, see
+	// Config.SignedUpdate. DatabaseReleaseURL overrides the official release,
+	// DatabasePublicKey the key of builds without one, see releasePublicKey.
+	SignedDatabase     bool   `json:"signedDatabase"`
+	DatabaseReleaseURL string `json:"databaseReleaseURL,omitempty"`
+	DatabasePublicKey  string `json:"databasePublicKey,omitempty"`
+	// CompileIndex compiles the database into a binary index, see
+	// Config.CompileIndex.
+	CompileIndex bool `json:"compileIndex"`
+	// HashCache remembers the hashes of scanned files, see Config.HashCache.
+	HashCache bool `json:"hashCache,omitempty"`
+	// Detectors are the content categories scanned for, see
+	// enabledDetectors. Unset scans for every category but the optional ones.
+	Detectors []string `json:"detectors"`
+	// IgnoredTitles are title IDs never scanned, see App.IgnoredTitles.
+	IgnoredTitles []string `json:"ignoredTitles,omitempty"`
+	// IgnoredItems are findings marked as ignored during triage, see
+	// App.IgnoredItems.
+	IgnoredItems []string `json:"ignoredItems,omitempty"`
+	// WebhookStatuses and WebhookKinds pick the findings posted to the
+	// webhook, see resolveWebhookFilter. Unset posts unknown and unarchived
+	// findings of every kind.
+	WebhookStatuses []string `json:"webhookStatuses,omitempty"`
+	WebhookKinds    []string `json:"webhookKinds,omitempty"`
+
+	// ShareStats opts in to posting aggregate scan counts to ShareStatsURL.
+	ShareStats    bool   `json:"shareStats"`
+	ShareStatsURL string `json:"shareStatsURL,omitempty"`
+
+	ScheduleEnabled bool `json:"scheduleEnabled"`
+	ScheduleMinutes int  `json:"scheduleMinutes,omitempty"`
+
+	// RecentLocations are the last dump folders used in the GUI, newest
+	// first, see rememberLocation.
+	RecentLocations []string `json:"recentLocations,omitempty"`
+
+	// unknown holds the keys of the file this version doesn't know, see
+	// decodeSettings.
+	unknown map[string]json.RawMessage
 }
 
 var (
-	outputContainer = container.New(layout.NewVBoxLayout())
-	guiCyan         = color.RGBA{0, 139, 139, 255}
+	guiCyan = color.RGBA{0, 139, 139, 255}
+	// guiWindow is the main window, dialogs shown during scans open on it.
+	guiWindow fyne.Window
 )
 
 const (
 	guiHeaderWidth = 50
 )
 
-func addHeader(title string) {
+func (a *App) addHeader(title string) {
 	title = strings.TrimSpace(title)
 	if len(title) > guiHeaderWidth-6 { // -6 to account for spaces and equals signs
 		title = title[:guiHeaderWidth-4] + "..."
 	}
 	formattedTitle := "== " + title + " =="
 	padLen := (guiHeaderWidth - len(formattedTitle)) / 2
-	addText(theme.ForegroundColor(), strings.Repeat("=", padLen)+formattedTitle+strings.Repeat("=", guiHeaderWidth-padLen-len(formattedTitle)))
+	a.addText(theme.ForegroundColor(), strings.Repeat("=", padLen)+formattedTitle+strings.Repeat("=", guiHeaderWidth-padLen-len(formattedTitle)))
 }
 
-func addText(textColor color.Color, format string, args ...interface{}) {
-	output := canvas.NewText(fmt.Sprintf(format, args...), textColor)
-	outputContainer.Add(output)
-	outputContainer.Refresh()
-	outputContainer.Show()
+// addTitleHeader adds a title header that shows the title's details when clicked.
+func (a *App) addTitleHeader(titleID string, title string) {
+	title = strings.TrimSpace(title)
+	if len(title) > guiHeaderWidth-6 { // -6 to account for spaces and equals signs
+		title = title[:guiHeaderWidth-4] + "..."
+	}
+	a.addOutput(outputLine{Text: "== " + title + " ==", Color: theme.PrimaryColor(), TitleID: titleID})
+}
+
+// addThumbnail adds the thumbnail of a content ID to the output, it is
+// downloaded in the background so the scan isn't held up.
+func (a *App) addThumbnail(contentID string) {
+	id, scan := a.reserveThumbnailRow()
+	go func() {
+		thumbnail, err := a.fetchThumbnail(contentID)
+		if err != nil {
+			a.addLog(guiWarnColor(), "%s", err)
+			return
+		}
+		if thumbnail == "" {
+			return
+		}
+		setOutputThumbnail(id, scan, thumbnail)
+	}()
 }
 
-func loadSettings() (*Settings, error) {
-	settingsPath := filepath.Join(dataPath, "pineconeSettings.json")
-	settingsFile, err := os.Open(settingsPath)
+func (a *App) loadSettings() (*Settings, error) {
+	settingsPath := filepath.Join(a.Config.DataPath, "pineconeSettings.json")
+	data, err := os.ReadFile(settingsPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// If the settings file doesn't exist, return default settings
-			return &Settings{}, nil
+			return &Settings{Version: settingsVersion}, nil
 		}
 		return nil, err
 	}
-	defer settingsFile.Close()
 
-	settings := &Settings{}
-	err = json.NewDecoder(settingsFile).Decode(settings)
+	settings, err := decodeSettings(data)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Error reading settings %s: %v", settingsPath, err)
 	}
-
 	return settings, nil
 }
 
-func saveSettings(settings *Settings) error {
-	settingsPath := filepath.Join(dataPath, "pineconeSettings.json")
-	settingsFile, err := os.Create(settingsPath)
+// saveSettings writes the settings through a temporary file, so a crash
+// while saving leaves the previous file. A file that can't be read is backed
+// up to pineconeSettings.json.bak first.
+func (a *App) saveSettings(settings *Settings) error {
+	settingsPath := filepath.Join(a.Config.DataPath, "pineconeSettings.json")
+	data, err := encodeSettings(settings)
 	if err != nil {
 		return err
 	}
-	defer settingsFile.Close()
-
-	encoder := json.NewEncoder(settingsFile)
-	encoder.SetIndent("", "    ")
+	backupUnreadableSettings(settingsPath)
 
-	err = encoder.Encode(settings)
-	if err != nil {
+	tmpPath := settingsPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
 		return err
 	}
-
-	return nil
+	return os.Rename(tmpPath, settingsPath)
 }
 
-func showSettingsDialog(settings *Settings, app fyne.App) {
+// showSettingsDialog edits the settings, onSave is called once they are saved
+// so changes can be applied right away.
+func (a *App) showSettingsDialog(settings *Settings, app fyne.App, onSave func(*Settings)) {
 	settingsWindow := app.NewWindow("Settings")
-	settingsWindow.Resize(fyne.Size{Width: 200, Height: 100})
+	settingsWindow.Resize(fyne.Size{Width: 480, Height: 640})
 
 	userNameEntry := widget.NewEntry()
 	userNameEntry.SetPlaceHolder("User Name")
@@ -138,12 +246,215 @@ func showSettingsDialog(settings *Settings, app fyne.App) {
 		settings.Reddit = text
 	}
 
+	fontScale := float64(settings.FontScale)
+	if fontScale < minFontScale || fontScale > maxFontScale {
+		fontScale = 1
+	}
+	fontScaleLabel := widget.NewLabel(fmt.Sprintf("Font Size: %.0f%%", fontScale*100))
+	fontScaleSlider := widget.NewSlider(minFontScale, maxFontScale)
+	fontScaleSlider.Step = 0.1
+	fontScaleSlider.SetValue(fontScale)
+	fontScaleSlider.OnChanged = func(value float64) {
+		settings.FontScale = float32(value)
+		fontScaleLabel.SetText(fmt.Sprintf("Font Size: %.0f%%", value*100))
+	}
+
+	highContrastCheck := widget.NewCheck("High Contrast", func(checked bool) {
+		settings.HighContrast = checked
+	})
+	highContrastCheck.SetChecked(settings.HighContrast)
+
+	languageEntry := widget.NewEntry()
+	languageEntry.SetPlaceHolder("Title name language, e.g. ja (default from the system)")
+	languageEntry.SetText(settings.Language)
+	languageEntry.OnChanged = func(text string) {
+		settings.Language = text
+	}
+
+	statsURLEntry := widget.NewEntry()
+	statsURLEntry.SetPlaceHolder("Remote Stats URL (optional)")
+	statsURLEntry.SetText(settings.StatsURL)
+	statsURLEntry.OnChanged = func(text string) {
+		settings.StatsURL = text
+	}
+
+	proxyEntry := widget.NewEntry()
+	proxyEntry.SetPlaceHolder("Proxy URL (default from HTTPS_PROXY)")
+	proxyEntry.SetText(settings.Proxy)
+	proxyEntry.OnChanged = func(text string) {
+		settings.Proxy = text
+	}
+
+	caCertEntry := widget.NewEntry()
+	caCertEntry.SetPlaceHolder("Extra CA certificate file (optional)")
+	caCertEntry.SetText(settings.CACertFile)
+	caCertEntry.OnChanged = func(text string) {
+		settings.CACertFile = text
+	}
+
+	githubTokenEntry := widget.NewPasswordEntry()
+	githubTokenEntry.SetPlaceHolder("GitHub Token (optional, avoids rate limits)")
+	githubTokenEntry.SetText(settings.GitHubToken)
+	githubTokenEntry.OnChanged = func(text string) {
+		settings.GitHubToken = text
+	}
+
+	reportTemplateEntry := widget.NewEntry()
+	reportTemplateEntry.SetPlaceHolder("Markdown report template file (optional)")
+	reportTemplateEntry.SetText(settings.ReportTemplate)
+	reportTemplateEntry.OnChanged = func(text string) {
+		settings.ReportTemplate = text
+	}
+
+	eepromKeyEntry := widget.NewPasswordEntry()
+	eepromKeyEntry.SetPlaceHolder("EEPROM key, 32 hex characters (optional, for console region)")
+	eepromKeyEntry.SetText(settings.EEPROMKey)
+	eepromKeyEntry.OnChanged = func(text string) {
+		settings.EEPROMKey = text
+	}
+
+	thumbnailsCheck := widget.NewCheck("Show DLC thumbnails (downloaded from the image store)", func(checked bool) {
+		settings.Thumbnails = checked
+	})
+	thumbnailsCheck.SetChecked(settings.Thumbnails)
+
+	thumbnailURLEntry := widget.NewEntry()
+	thumbnailURLEntry.SetPlaceHolder("Thumbnail URL, %s is the content ID (optional)")
+	thumbnailURLEntry.SetText(settings.ThumbnailURL)
+	thumbnailURLEntry.OnChanged = func(text string) {
+		settings.ThumbnailURL = strings.TrimSpace(text)
+	}
+
+	titleLookupCheck := widget.NewCheck("Look up unknown title IDs in a community database", func(checked bool) {
+		settings.TitleLookup = checked
+	})
+	titleLookupCheck.SetChecked(settings.TitleLookup)
+
+	titleLookupURLEntry := widget.NewEntry()
+	titleLookupURLEntry.SetPlaceHolder("Title lookup URL, %s is the title ID")
+	titleLookupURLEntry.SetText(settings.TitleLookupURL)
+	titleLookupURLEntry.OnChanged = func(text string) {
+		settings.TitleLookupURL = strings.TrimSpace(text)
+	}
+
+	// Set after the current selection, so opening the settings doesn't store
+	// the default detectors
+	detectorsGroup := widget.NewCheckGroup(detectorNames(detectors), nil)
+	detectorsGroup.Horizontal = true
+	if enabled, err := a.enabledDetectors(); err == nil {
+		detectorsGroup.SetSelected(detectorNames(enabled))
+	}
+	detectorsGroup.OnChanged = func(selected []string) {
+		settings.Detectors = append([]string{}, selected...)
+	}
+
+	signedDatabaseCheck := widget.NewCheck("Update the database from signed releases only", func(checked bool) {
+		settings.SignedDatabase = checked
+	})
+	signedDatabaseCheck.SetChecked(settings.SignedDatabase)
+
+	databasePublicKeyEntry := widget.NewEntry()
+	databasePublicKeyEntry.SetPlaceHolder("Database release public key, minisign (optional)")
+	databasePublicKeyEntry.SetText(settings.DatabasePublicKey)
+	databasePublicKeyEntry.OnChanged = func(text string) {
+		settings.DatabasePublicKey = strings.TrimSpace(text)
+	}
+
+	anonymizeCheck := widget.NewCheck("Anonymize reports (no local paths, console serial or save names)", func(checked bool) {
+		settings.Anonymize = checked
+	})
+	anonymizeCheck.SetChecked(settings.Anonymize)
+
+	submitHelpCheck := widget.NewCheck("Explain how to submit the first unknown find", func(checked bool) {
+		settings.HideSubmitHelp = !checked
+	})
+	submitHelpCheck.SetChecked(!settings.HideSubmitHelp)
+
+	notificationsCheck := widget.NewCheck("Notify when a long scan finishes", func(checked bool) {
+		settings.HideNotifications = !checked
+	})
+	notificationsCheck.SetChecked(!settings.HideNotifications)
+
+	shareStatsCheck := widget.NewCheck("Share anonymous scan counts (version, titles scanned, unknown/unarchived/archived totals)", func(checked bool) {
+		settings.ShareStats = checked
+	})
+	shareStatsCheck.SetChecked(settings.ShareStats)
+
+	shareStatsURLEntry := widget.NewEntry()
+	shareStatsURLEntry.SetPlaceHolder("Stats endpoint URL")
+	shareStatsURLEntry.SetText(settings.ShareStatsURL)
+	shareStatsURLEntry.OnChanged = func(text string) {
+		settings.ShareStatsURL = text
+	}
+
+	scheduleCheck := widget.NewCheck("Rescan Periodically", func(checked bool) {
+		settings.ScheduleEnabled = checked
+	})
+	scheduleCheck.SetChecked(settings.ScheduleEnabled)
+
+	scheduleEntry := widget.NewEntry()
+	scheduleEntry.SetPlaceHolder(fmt.Sprintf("Interval in minutes (default %d)", defaultScheduleMinutes))
+	if settings.ScheduleMinutes > 0 {
+		scheduleEntry.SetText(strconv.Itoa(settings.ScheduleMinutes))
+	}
+	scheduleEntry.Validator = func(text string) error {
+		if text == "" {
+			return nil
+		}
+		if minutes, err := strconv.Atoi(text); err != nil || minutes <= 0 {
+			return fmt.Errorf("enter a number of minutes")
+		}
+		return nil
+	}
+	scheduleEntry.OnChanged = func(text string) {
+		settings.ScheduleMinutes, _ = strconv.Atoi(text)
+	}
+
+	outputLimitEntry := widget.NewEntry()
+	outputLimitEntry.SetPlaceHolder(fmt.Sprintf("Output lines kept in the window (default %d, -1 for all)", defaultOutputLineLimit))
+	if settings.OutputLineLimit != 0 {
+		outputLimitEntry.SetText(strconv.Itoa(settings.OutputLineLimit))
+	}
+	outputLimitEntry.Validator = func(text string) error {
+		if text == "" {
+			return nil
+		}
+		if _, err := strconv.Atoi(text); err != nil {
+			return fmt.Errorf("enter a number of lines")
+		}
+		return nil
+	}
+	outputLimitEntry.OnChanged = func(text string) {
+		settings.OutputLineLimit, _ = strconv.Atoi(text)
+	}
+
+	ignoredTitlesEntry := widget.NewEntry()
+	ignoredTitlesEntry.SetPlaceHolder("Title IDs never scanned, comma separated (homebrew under made up IDs)")
+	ignoredTitlesEntry.SetText(strings.Join(settings.IgnoredTitles, ", "))
+	ignoredTitlesEntry.Validator = func(text string) error {
+		for _, pattern := range strings.Split(text, ",") {
+			if _, err := path.Match(strings.TrimSpace(pattern), ""); err != nil {
+				return fmt.Errorf("invalid title ID pattern %q", pattern)
+			}
+		}
+		return nil
+	}
+	ignoredTitlesEntry.OnChanged = func(text string) {
+		settings.IgnoredTitles = nil
+		for _, titleID := range strings.Split(text, ",") {
+			if titleID = strings.TrimSpace(titleID); titleID != "" {
+				settings.IgnoredTitles = append(settings.IgnoredTitles, titleID)
+			}
+		}
+	}
+
 	saveButton := widget.NewButton("Save", func() {
-		err := saveSettings(settings)
+		err := a.saveSettings(settings)
 		if err != nil {
 			dialog.ShowError(err, settingsWindow)
 			return
 		}
+		onSave(settings)
 		settingsWindow.Close()
 	})
 
@@ -151,96 +462,127 @@ func showSettingsDialog(settings *Settings, app fyne.App) {
 		settingsWindow.Close()
 	})
 
-	content := container.NewVBox(
+	fields := container.NewVBox(
 		canvas.NewText("User Info:", theme.ForegroundColor()),
 		userNameEntry,
 		discordEntry,
 		twitterEntry,
 		redditEntry,
-		container.NewHBox(
-			layout.NewSpacer(),
-			saveButton,
-			cancelButton,
-		),
+		canvas.NewText("Accessibility:", theme.ForegroundColor()),
+		fontScaleLabel,
+		fontScaleSlider,
+		highContrastCheck,
+		languageEntry,
+		canvas.NewText("Dashboard:", theme.ForegroundColor()),
+		statsURLEntry,
+		canvas.NewText("Network:", theme.ForegroundColor()),
+		proxyEntry,
+		caCertEntry,
+		githubTokenEntry,
+		thumbnailsCheck,
+		thumbnailURLEntry,
+		titleLookupCheck,
+		titleLookupURLEntry,
+		signedDatabaseCheck,
+		databasePublicKeyEntry,
+		canvas.NewText("Scan for:", theme.ForegroundColor()),
+		detectorsGroup,
+		ignoredTitlesEntry,
+		canvas.NewText("Reports:", theme.ForegroundColor()),
+		reportTemplateEntry,
+		eepromKeyEntry,
+		anonymizeCheck,
+		submitHelpCheck,
+		notificationsCheck,
+		outputLimitEntry,
+		canvas.NewText("Stats:", theme.ForegroundColor()),
+		shareStatsCheck,
+		shareStatsURLEntry,
+		canvas.NewText("Scheduler:", theme.ForegroundColor()),
+		scheduleCheck,
+		scheduleEntry,
+	)
+	buttons := container.NewHBox(
+		layout.NewSpacer(),
+		saveButton,
+		cancelButton,
 	)
 
+	// The settings don't fit on smaller screens, keep the buttons in view.
+	content := container.NewBorder(nil, buttons, nil, nil, container.NewVScroll(fields))
 	settingsWindow.SetContent(content)
 	settingsWindow.Show()
 }
 
-func setDumpFolder(window fyne.Window) {
-	dialog.ShowFolderOpen(func(list fyne.ListableURI, err error) {
-		if err != nil {
-			dialog.ShowError(err, window)
-			return
-		}
-		if list == nil { // user cancelled
-			return
-		}
-		// Set tmpDumpPath to the selected folder
-		tmpDumpPath := list.String()
-		// Convert path to be used in the checkForContent function
-		tmpDumpPath = strings.Replace(tmpDumpPath, "file://", "", -1)
-		// set global scanpath variable to the selected folder
-
-		if _, err := os.Stat(path.Join(tmpDumpPath + "TDATA")); os.IsNotExist(err) {
-			dumpLocation = tmpDumpPath
-			output := canvas.NewText("Path set to: "+tmpDumpPath, theme.ForegroundColor())
-			outputContainer.Add(output)
-		} else {
-			output := canvas.NewText("Incorrect pathing. Please select a dump with TDATA folder.\n", theme.ForegroundColor())
-			outputContainer.Add(output)
-		}
-	}, window)
+func (a *App) setDumpFolder(window fyne.Window) {
+	a.pickFolder(window, "Select a dump folder", a.useDumpFolder)
 }
 
-func guiScanDump() {
-	err := checkDumpFolder(dumpLocation)
+func (a *App) guiScanDump() {
+	err := a.checkDumpFolder(a.Config.DumpLocation)
 	if nil != err {
 		fmt.Println("ERROR: ", err.Error())
-		addText(theme.ErrorColor(), err.Error())
+		a.addText(theme.ErrorColor(), err.Error())
+	} else if len(a.Config.DumpLocations) == 0 {
+		a.rememberLocation(a.Config.DumpLocation)
 	}
 
-	err = checkParsingSettings()
+	err = a.checkParsingSettings()
 	if nil != err {
 		fmt.Println("ERROR: ", err.Error())
-		addText(theme.ErrorColor(), err.Error())
+		a.addText(theme.ErrorColor(), err.Error())
 	}
 }
 
-func guiStartScan(options GUIOptions, window fyne.Window) {
-	outputContainer.RemoveAll()
-	if dumpLocation == "" {
-		output := canvas.NewText("Please set a path first.", theme.ForegroundColor())
-		outputContainer.Add(output)
+// guiLoadTitles loads the local database if no scan has loaded it yet.
+func (a *App) guiLoadTitles(options GUIOptions) error {
+	if len(a.Titles.Titles) > 0 {
+		return nil
+	}
+	if _, err := os.Stat(options.JSONFilePath); os.IsNotExist(err) {
+		return fmt.Errorf("database not found, please update the database first")
+	}
+	return a.loadJSONData(options.JSONFilePath, "Xbox-Preservation-Project", "Pinecone", databaseRepoPath, &a.Titles, false)
+}
+
+// guiStartScan starts a scan from the Scan button, unless one is running.
+func (a *App) guiStartScan(options GUIOptions, window fyne.Window) {
+	if !exclusiveScan(func() { a.startScan(options, window) }) {
+		a.addText(theme.ForegroundColor(), "A scan is already running.")
+	}
+}
+
+func (a *App) startScan(options GUIOptions, window fyne.Window) {
+	clearOutput()
+	clearPanes()
+	if a.Config.DumpLocation == "" {
+		a.addText(theme.ForegroundColor(), "Please set a path first.")
 	} else {
-		output := canvas.NewText("Checking for Content...", theme.ForegroundColor())
-		outputContainer.Add(output)
-		err := checkDatabaseFile(options.JSONFilePath, options.JSONUrl, updateFlag, window)
+		a.addText(theme.ForegroundColor(), "Checking for Content...")
+		err := a.checkDatabaseFile(options.JSONFilePath, options.JSONUrl, a.Config.Update, window)
 		if err != nil {
 			fmt.Println("ERROR: ", err.Error())
-			addText(theme.ErrorColor(), err.Error())
+			a.addText(theme.ErrorColor(), err.Error())
 		}
 	}
 }
 
-func guiShowDownloadConfirmation(window fyne.Window, filePath string, url string) {
+func (a *App) guiShowDownloadConfirmation(window fyne.Window, filePath string, url string) {
 	message := fmt.Sprintf("The required JSON data is not found.\nIt can be downloaded from:\n%s\nDo you want to download it now?", url)
 	confirmation := dialog.NewConfirm("Confirmation", message, func(confirmed bool) {
 		if confirmed {
 			// Action to perform if confirmed
-			err := loadJSONData(filePath, "Xbox-Preservation-Project", "Pinecone", dataPath+"/id_database.json", &titles, true)
+			err := a.loadJSONData(filePath, "Xbox-Preservation-Project", "Pinecone", databaseRepoPath, &a.Titles, true)
 			if err != nil {
-				text := fmt.Sprintf("error downloading data: %v", err)
-				output := canvas.NewText(text, theme.ErrorColor())
-				outputContainer.Add(output)
+				a.addText(theme.ErrorColor(), "error downloading data: %v", err)
 				return
 			}
-			guiScanDump()
+			if !exclusiveScan(a.guiScanDump) {
+				a.addText(theme.ForegroundColor(), "A scan is already running.")
+			}
 		} else {
 			// Action to perform if canceled
-			output := canvas.NewText("Download aborted by user", theme.ErrorColor())
-			outputContainer.Add(output)
+			a.addText(theme.ErrorColor(), "Download aborted by user")
 		}
 	}, window)
 
@@ -248,13 +590,13 @@ func guiShowDownloadConfirmation(window fyne.Window, filePath string, url string
 	confirmation.Show()
 }
 
-func saveOutput(settings *Settings) {
+func (a *App) saveOutput(settings *Settings) {
 	// Get current time
 	t := time.Now()
 	// Format time to be used in filename
 	timestamp := t.Format("2006-01-02-15-04-05")
 	// Define the path to the output file
-	outputPath := filepath.Join(dataPath, "output", "output-"+timestamp+".txt")
+	outputPath := filepath.Join(a.Config.DataPath, "output", "output-"+timestamp+".txt")
 	// Create the 'output' directory if it doesn't exist
 	outputDir := filepath.Dir(outputPath)
 	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
@@ -263,34 +605,27 @@ func saveOutput(settings *Settings) {
 			panic(err)
 		}
 	}
+	// Write output to file
 	fileText := ""
-	// Add user info to top of file
-	if settings.UserName != "" {
-		fileText += fmt.Sprintf("Username: %s\n", settings.UserName)
-	}
-	if settings.Discord != "" {
-		fileText += fmt.Sprintf("Discord Username: @%s\n", settings.Discord)
+	if lines := outputText(); len(lines) > 0 {
+		fileText = strings.Join(lines, "\n") + "\n"
 	}
-	if settings.Twitter != "" {
-		fileText += fmt.Sprintf("Twitter Username: @%s\n", settings.Twitter)
+	if lines := logLines(); len(lines) > 0 {
+		fileText += "\nLog:\n" + strings.Join(lines, "\n") + "\n"
 	}
-	if settings.Reddit != "" {
-		fileText += fmt.Sprintf("Reddit Username: u/%s\n", settings.Reddit)
+	// Credit the user for new finds
+	if credit := creditBlock(&a.Report, settings); credit != "" {
+		fileText += "\n" + credit + "\n"
 	}
-	// Write output to file
-	for _, obj := range outputContainer.Objects {
-		if textObj, ok := obj.(*canvas.Text); ok {
-			// Append the text value to the string
-			fileText += textObj.Text + "\n"
-		}
+	if a.anonymizeEnabled(settings) {
+		fileText = anonymizeOutput(fileText, &a.Report)
 	}
 	err := os.WriteFile(outputPath, []byte(fileText), 0o644)
 	if err != nil {
 		panic(err)
 	}
 	// Debug output, show the path we're scanning
-	output := widget.NewLabel("Output saved to: " + outputPath + "\n")
-	outputContainer.Add(output)
+	a.addText(theme.ForegroundColor(), "Output saved to: %s", outputPath)
 }
 
 func loadImage(name, path string) *fyne.StaticResource {
@@ -304,66 +639,183 @@ func loadImage(name, path string) *fyne.StaticResource {
 	}
 }
 
-func startGUI(options GUIOptions) {
-	a := app.New()
+func (a *App) startGUI(options GUIOptions) {
+	if err := checkDataFolder(options.DataFolder); err != nil {
+		fmt.Println(err)
+	}
+	fyneApp := app.New()
 	windowName := fmt.Sprintf("Pinecone %s", version)
-	w := a.NewWindow(windowName)
-	output := widget.NewLabel("")
+	w := fyneApp.NewWindow(windowName)
+	guiWindow = w
+
+	applySettings := func(settings *Settings) {
+		applyTheme(fyneApp, settings)
+		a.applySchedule(settings, options, w)
+		titleLanguage = a.resolveTitleLanguage(settings)
+		outputLineLimit = resolveOutputLineLimit(settings)
+	}
+	startSettings, err := a.loadSettings()
+	if err == nil {
+		applySettings(startSettings)
+	} else {
+		startSettings = &Settings{}
+	}
+	a.retrySubmissionsLater()
+	outputList := a.newOutputList()
 
 	// First Load welcome message
-	fakeConsole := fmt.Sprintf("Welcome to Pinecone v%s\n", version)
-	output.SetText(output.Text + fakeConsole)
+	a.addText(theme.ForegroundColor(), "Welcome to Pinecone v%s", version)
 
-	w.Resize(fyne.Size{Width: 800, Height: 600})
+	w.Resize(windowSize(startSettings))
 
 	tdataButtonIcon := loadImage("tdatabutton", "./images/xboxIcon.svg")
 
 	// set folder to scan, but only if it is a TDATA folder.
 	setFolder := ttwidget.NewButtonWithIcon("", tdataButtonIcon, func() {
-		setDumpFolder(w)
+		a.setDumpFolder(w)
 	})
-	setFolder.SetToolTip("Set Dump Folder")
+	setFolder.SetToolTip("Set Dump Folder (Ctrl+O)")
 
 	scanPath := ttwidget.NewButtonWithIcon("", theme.SearchIcon(), func() {
-		guiStartScan(options, w)
+		a.guiStartScan(options, w)
 	})
-	scanPath.SetToolTip("Scan For Content")
+	scanPath.SetToolTip("Scan For Content (F5)")
 
 	// Save output to a file in the homeDir with a timestamp.
 	saveOutput := ttwidget.NewButtonWithIcon("", theme.DocumentSaveIcon(), func() {
-		settings, err := loadSettings()
+		settings, err := a.loadSettings()
+		if err != nil {
+			fmt.Println(err)
+			settings = &Settings{}
+		}
+		a.saveOutput(settings)
+	})
+	saveOutput.SetToolTip("Save Output (Ctrl+E)")
+
+	// Export the last scan as a shareable HTML report.
+	exportHTML := ttwidget.NewButtonWithIcon("", theme.FileTextIcon(), func() {
+		settings, err := a.loadSettings()
+		if err != nil {
+			fmt.Println(err)
+			settings = &Settings{}
+		}
+		reportPath := a.defaultReportPath("report", ".html")
+		err = a.exportHTMLReport(reportPath, settings)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		a.addText(theme.ForegroundColor(), "HTML report saved to: %s", reportPath)
+	})
+	exportHTML.SetToolTip("Export HTML Report")
+
+	// Export the last scan as Markdown, formatted for GitHub issues.
+	exportMarkdown := ttwidget.NewButtonWithIcon("", theme.DocumentIcon(), func() {
+		settings, err := a.loadSettings()
+		if err != nil {
+			fmt.Println(err)
+			settings = &Settings{}
+		}
+		reportPath := a.defaultReportPath("report", ".md")
+		err = a.exportMarkdownReport(reportPath, settings)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		a.addText(theme.ForegroundColor(), "Markdown report saved to: %s", reportPath)
+	})
+	exportMarkdown.SetToolTip("Export Markdown Report")
+
+	// Copy only the unknown/unarchived findings, ready to submit.
+	copyFindings := ttwidget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+		findings := a.Report.Interesting()
+		if len(findings.Findings) == 0 {
+			a.addText(theme.ForegroundColor(), "No unknown or unarchived content to copy.")
+			return
+		}
+		settings, err := a.loadSettings()
 		if err != nil {
 			fmt.Println(err)
 			settings = &Settings{}
 		}
-		saveOutput(settings)
+		var b strings.Builder
+		err = a.writeMarkdownReport(&b, findings, settings)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		w.Clipboard().SetContent(b.String())
+		a.addText(theme.ForegroundColor(), "Copied %d findings to the clipboard.", len(findings.Findings))
+	})
+	copyFindings.SetToolTip("Copy Findings")
+
+	// Copy the unknown and unarchived files out of the dump to review them.
+	collect := ttwidget.NewButtonWithIcon("", theme.FolderNewIcon(), func() {
+		if len(a.Report.Interesting().Findings) == 0 {
+			a.addText(theme.ForegroundColor(), "No unknown or unarchived content to collect.")
+			return
+		}
+		a.pickFolder(w, "Select a folder to collect the finds to", func(folder string) {
+			settings, err := a.loadSettings()
+			if err != nil {
+				settings = &Settings{}
+			}
+			copied, err := a.collectFinds(folder, settings)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			dialog.ShowInformation("Finds collected", fmt.Sprintf("%d file(s) copied to %s with a report of them.", copied, folder), w)
+		})
+	})
+	collect.SetToolTip("Collect Finds")
+
+	// Step through the unknown items, deciding what to do with each.
+	triage := ttwidget.NewButtonWithIcon("", theme.QuestionIcon(), func() {
+		a.showTriageWizard(w)
 	})
-	saveOutput.SetToolTip("Save Output")
+	triage.SetToolTip("Triage Unknown Items")
 
 	updateJSON := ttwidget.NewButtonWithIcon("", theme.DownloadIcon(), func() {
 		updateJSON := true
-		err := checkDatabaseFile(options.JSONFilePath, options.JSONUrl, updateJSON, nil)
+		// An explicit click always checks, later scans go back to skipping
+		// recent checks unless -force-update was given.
+		forced := a.Config.ForceUpdate
+		a.Config.ForceUpdate = true
+		err := a.checkDatabaseFile(options.JSONFilePath, options.JSONUrl, updateJSON, nil)
+		a.Config.ForceUpdate = forced
 		if err != nil {
 			fmt.Println(err)
 		}
 	})
 	updateJSON.SetToolTip("Update Database")
 
+	// Search the database by title name
+	searchTitles := ttwidget.NewButtonWithIcon("", theme.ListIcon(), func() {
+		err := a.guiLoadTitles(options)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		a.showSearchWindow()
+	})
+	searchTitles.SetToolTip("Search Titles (Ctrl+F)")
+
 	// Create the settings button with the settings icon
 	settingsButton := ttwidget.NewButtonWithIcon("", theme.SettingsIcon(), func() {
 		// Open the settings screen
-		settings, err := loadSettings()
+		settings, err := a.loadSettings()
 		if err != nil {
 			fmt.Println(err)
 			settings = &Settings{}
 		}
-		showSettingsDialog(settings, a)
+		a.showSettingsDialog(settings, fyneApp, applySettings)
 	})
 	settingsButton.SetToolTip("Settings")
 
 	// Exit the application
 	exit := ttwidget.NewButtonWithIcon("", theme.LogoutIcon(), func() {
-		a.Quit()
+		fyneApp.Quit()
 	})
 	exit.SetToolTip("Exit")
 
@@ -371,22 +823,96 @@ func startGUI(options GUIOptions) {
 	sideMenu := container.NewVBox()
 
 	// Create a container with vertical box layout for the buttons
-	buttons := container.NewVBox(setFolder, scanPath, updateJSON, saveOutput, settingsButton, exit)
+	buttons := container.NewVBox(setFolder, scanPath, searchTitles, updateJSON, saveOutput, exportHTML, exportMarkdown, copyFindings, collect, triage, settingsButton, exit)
 
 	// Add the hamburger button to the hamburgerMenu
 	sideMenu.Add(buttons)
 
-	outputContainer.Add(output)
-	// Create a container with scroll for the output
-	outputScroll := container.NewScroll(outputContainer)
+	// The same actions from the menu bar and the keyboard
+	w.SetMainMenu(fyne.NewMainMenu(
+		fyne.NewMenu("File",
+			menuAction(w, "Set Dump Folder...", shortcutSetFolder, setFolder.OnTapped),
+			a.newRecentLocationsMenu(),
+			menuAction(w, "Scan For Content", shortcutScan, scanPath.OnTapped),
+			fyne.NewMenuItemSeparator(),
+			menuAction(w, "Save Output", shortcutSaveOutput, saveOutput.OnTapped),
+			menuAction(w, "Export HTML Report", nil, exportHTML.OnTapped),
+			menuAction(w, "Export Markdown Report", nil, exportMarkdown.OnTapped),
+			menuAction(w, "Copy Findings", nil, copyFindings.OnTapped),
+			menuAction(w, "Collect Finds...", nil, collect.OnTapped),
+			menuAction(w, "Triage Unknown Items...", nil, triage.OnTapped),
+			fyne.NewMenuItemSeparator(),
+			menuAction(w, "Settings...", nil, settingsButton.OnTapped),
+		),
+		fyne.NewMenu("Database",
+			menuAction(w, "Search Titles...", shortcutSearch, searchTitles.OnTapped),
+			menuAction(w, "Update Database", nil, updateJSON.OnTapped),
+			menuAction(w, "Database Changes...", nil, func() { a.showDatabaseChanges(w) }),
+		),
+	))
+
+	// The dashboard is rebuilt every time it's opened to reflect the loaded database
+	dashboardTab := container.NewTabItemWithIcon("Dashboard", theme.InfoIcon(), widget.NewLabel(""))
+	panes, storeOffsets := a.scanPanes(outputList, startSettings)
+	scanTab := container.NewBorder(a.titleFilterBar(), nil, nil, nil, panes)
+	// Same for the database browser
+	databaseTab := container.NewTabItemWithIcon("Database", theme.StorageIcon(), widget.NewLabel(""))
+	// And the submission log, which CLI scans add to
+	submissionsTab := container.NewTabItemWithIcon("Submissions", theme.MailSendIcon(), widget.NewLabel(""))
+	tabs := container.NewAppTabs(container.NewTabItemWithIcon("Scan", theme.SearchIcon(), scanTab), dashboardTab, databaseTab, submissionsTab)
+	tabs.OnSelected = func(tab *container.TabItem) {
+		if tab == submissionsTab {
+			submissionsTab.Content = a.submissionsView(w)
+			tabs.Refresh()
+			return
+		}
+		if tab != dashboardTab && tab != databaseTab {
+			return
+		}
+		if err := a.guiLoadTitles(options); err != nil {
+			fmt.Println(err)
+		}
+		if tab == databaseTab {
+			databaseTab.Content = a.databaseBrowser()
+			tabs.Refresh()
+			return
+		}
+		settings, err := a.loadSettings()
+		if err != nil {
+			fmt.Println(err)
+			settings = &Settings{}
+		}
+		dashboardTab.Content = a.coverageDashboard(settings)
+		tabs.Refresh()
+	}
 
 	// Create a container to hold the main content of the window
-	mainContent := container.NewBorder(nil, nil, nil, nil, outputScroll)
+	mainContent := container.NewBorder(nil, nil, nil, nil, tabs)
 
 	// Create a container that includes the hamburger menu and main content
 	fullContent := container.NewBorder(nil, nil, sideMenu, nil, mainContent)
 
 	// Place the buttons to the left and the output to the center
 	w.SetContent(fynetooltip.AddWindowToolTipLayer(fullContent, w.Canvas()))
+
+	// Remember the window and pane sizes for the next session
+	fyneApp.Lifecycle().SetOnStopped(func() {
+		settings, err := a.loadSettings()
+		if err != nil {
+			settings = &Settings{}
+		}
+		size := w.Canvas().Size()
+		settings.WindowWidth, settings.WindowHeight = size.Width, size.Height
+		storeOffsets(settings)
+		if err := a.saveSettings(settings); err != nil {
+			fmt.Println(err)
+		}
+	})
+
+	// In tray mode the window stays hidden until opened from the tray menu
+	if a.Config.Tray && a.setupTray(fyneApp, w, tdataButtonIcon, options) {
+		fyneApp.Run()
+		return
+	}
 	w.ShowAndRun()
 }
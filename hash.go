@@ -0,0 +1,30 @@
+package main
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const defaultHashBlockSize = 1024 * 1024
+
+var (
+	// hashBlockSize is the read size used when hashing, set with -block-size.
+	hashBlockSize = defaultHashBlockSize
+	// useMmap hashes dump files through a memory mapping, set with -mmap.
+	useMmap = false
+)
+
+var errMmapUnsupported = errors.New("mmap is not supported on this platform")
+
+// getSHA1HashMmap hashes a file through a read-only memory mapping.
+func getSHA1HashMmap(file *os.File) (string, error) {
+	data, unmap, err := mmapFile(file)
+	if err != nil {
+		return "", err
+	}
+	defer unmap()
+
+	return fmt.Sprintf("%x", sha1.Sum(data)), nil
+}
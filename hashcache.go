@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// HashCacheEntry is the SHA1 of a file as of its size and modification time,
+// and the dump it is in.
+type HashCacheEntry struct {
+	SHA1     string `json:"sha1"`
+	Location string `json:"location"`
+}
+
+// HashCache is loaded when a scan starts, nil entries when disabled. Its keys
+// are the xxHash64 of a file's location, path, size and modification time: a
+// file changed since it was cached gets another key. SHA1 is still what's
+// matched against the database.
+type HashCache struct {
+	sync.Mutex
+	entries map[string]HashCacheEntry
+	used    map[string]bool
+}
+
+func (a *App) hashCachePath() string {
+	return filepath.Join(a.Config.DataPath, "hash_cache.json")
+}
+
+func (a *App) hashCacheEnabled() bool {
+	if a.Config.HashCache {
+		return true
+	}
+	settings, err := a.loadSettings()
+	return err == nil && settings.HashCache
+}
+
+// loadHashCache loads the cache of earlier scans, when enabled. A missing or
+// unreadable cache starts empty.
+func (a *App) loadHashCache() {
+	a.HashCache.Lock()
+	defer a.HashCache.Unlock()
+	a.HashCache.entries, a.HashCache.used = nil, nil
+	if !a.hashCacheEnabled() {
+		return
+	}
+	a.HashCache.entries = make(map[string]HashCacheEntry)
+	a.HashCache.used = make(map[string]bool)
+	if data, err := os.ReadFile(a.hashCachePath()); err == nil {
+		json.Unmarshal(data, &a.HashCache.entries)
+	}
+}
+
+// saveHashCache writes the cache after a scan. The entries of the scanned
+// locations that weren't used, files since changed or removed, are dropped.
+func (a *App) saveHashCache(locations []string) error {
+	a.HashCache.Lock()
+	defer a.HashCache.Unlock()
+	if a.HashCache.entries == nil {
+		return nil
+	}
+	scanned := make([]string, len(locations))
+	for i, location := range locations {
+		scanned[i] = hashCacheLocation(location)
+	}
+	for key, entry := range a.HashCache.entries {
+		if !a.HashCache.used[key] && contains(scanned, entry.Location) {
+			delete(a.HashCache.entries, key)
+		}
+	}
+	data, err := json.Marshal(a.HashCache.entries)
+	a.HashCache.entries, a.HashCache.used = nil, nil
+	if err != nil {
+		return fmt.Errorf("Error saving the hash cache: %v", err)
+	}
+	if err := os.WriteFile(a.hashCachePath(), data, 0o644); err != nil {
+		return fmt.Errorf("Error saving the hash cache: %v", err)
+	}
+	return nil
+}
+
+// hashCacheLocation is how a location is stored in the cache, absolute so
+// the cache works from any working directory.
+func hashCacheLocation(location string) string {
+	if abs, err := filepath.Abs(location); err == nil && !isArchive(location) {
+		return abs
+	}
+	return location
+}
+
+// hashCacheKey is the key of a file of the dump at location, "" if it can't
+// be read or the cache is disabled. Files listed on stdin aren't cached,
+// their paths aren't relative to one location.
+func (a *App) hashCacheKey(location string, fsys fs.FS, name string) string {
+	a.HashCache.Lock()
+	enabled := a.HashCache.entries != nil
+	a.HashCache.Unlock()
+	if !enabled || location == stdinLocation {
+		return ""
+	}
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%016x", xxhash.Sum64String(fmt.Sprintf("%s\x00%s\x00%d\x00%d", hashCacheLocation(location), name, info.Size(), info.ModTime().UnixNano())))
+}
+
+// cachedHash returns the cached SHA1 of a file.
+func (a *App) cachedHash(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	a.HashCache.Lock()
+	defer a.HashCache.Unlock()
+	entry, ok := a.HashCache.entries[key]
+	if ok {
+		a.HashCache.used[key] = true
+	}
+	return entry.SHA1, ok
+}
+
+// cacheHash remembers the SHA1 of a file of the dump at location.
+func (a *App) cacheHash(location string, key string, fileHash string) {
+	if key == "" {
+		return
+	}
+	a.HashCache.Lock()
+	defer a.HashCache.Unlock()
+	if a.HashCache.entries != nil {
+		a.HashCache.entries[key] = HashCacheEntry{SHA1: fileHash, Location: hashCacheLocation(location)}
+		a.HashCache.used[key] = true
+	}
+}
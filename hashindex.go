@@ -0,0 +1,57 @@
+package main
+
+// IndexEntry is an item of the reverse indexes: the title listing it and its
+// archived name, "" for content that isn't archived.
+type IndexEntry struct {
+	TitleID string
+	Name    string
+}
+
+// buildIndexes builds the reverse indexes of a database. They map the hashes
+// of known title updates and the content IDs of DLC to the titles listing
+// them, so matching a file is a single lookup instead of a loop over the
+// title's lists. An item can be listed by several titles, see audit.
+func buildIndexes(list *TitleList) {
+	updateIndex := make(map[string][]IndexEntry)
+	contentIndex := make(map[string][]IndexEntry)
+	for titleID, titleData := range list.Titles {
+		for _, knownUpdate := range titleData.TitleUpdatesKnown {
+			for hash, name := range knownUpdate {
+				updateIndex[hash] = append(updateIndex[hash], IndexEntry{TitleID: titleID, Name: name})
+			}
+		}
+
+		archived := make(map[string]string)
+		for _, item := range titleData.Archived {
+			for contentID, name := range item {
+				if _, ok := archived[contentID]; !ok {
+					archived[contentID] = name
+				}
+			}
+		}
+		for _, contentID := range titleData.ContentIDs {
+			contentIndex[contentID] = append(contentIndex[contentID], IndexEntry{TitleID: titleID, Name: archived[contentID]})
+		}
+	}
+	list.updateIndex, list.contentIndex = updateIndex, contentIndex
+}
+
+// knownUpdateName returns the archived name of a title update of titleID.
+func (list *TitleList) knownUpdateName(titleID string, fileHash string) (string, bool) {
+	return lookupIndex(list.updateIndex, titleID, fileHash)
+}
+
+// knownContent tells whether titleID lists a content ID, and its archived
+// name if it is archived.
+func (list *TitleList) knownContent(titleID string, contentID string) (string, bool) {
+	return lookupIndex(list.contentIndex, titleID, contentID)
+}
+
+func lookupIndex(index map[string][]IndexEntry, titleID string, key string) (string, bool) {
+	for _, entry := range index[key] {
+		if entry.TitleID == titleID {
+			return entry.Name, true
+		}
+	}
+	return "", false
+}
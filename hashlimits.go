@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// HashSizeRule skips hashing the files of folders matching Folder (e.g. "$u",
+// wildcards allowed) smaller than MinSize or larger than MaxSize bytes. A
+// zero size is no limit.
+type HashSizeRule struct {
+	Folder  string `json:"folder"`
+	MinSize int64  `json:"minSize,omitempty"`
+	MaxSize int64  `json:"maxSize,omitempty"`
+}
+
+func (a *App) loadHashSizeRules() error {
+	a.HashSizeRules = nil
+	settings, err := a.loadSettings()
+	if err != nil {
+		return nil
+	}
+	for _, rule := range settings.HashSizeRules {
+		if _, err := path.Match(strings.ToLower(rule.Folder), ""); err != nil {
+			return fmt.Errorf("Error in hashSizeRules: invalid folder pattern %q: %v", rule.Folder, err)
+		}
+	}
+	a.HashSizeRules = settings.HashSizeRules
+	return nil
+}
+
+// hashSkipReason tells why a file isn't hashed under the size rules, "" if it
+// is hashed.
+func (a *App) hashSkipReason(fsys fs.FS, name string) string {
+	if len(a.HashSizeRules) == 0 {
+		return ""
+	}
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return "" // hashing reports the error
+	}
+	folder := strings.ToLower(path.Base(path.Dir(name)))
+	for _, rule := range a.HashSizeRules {
+		if matched, _ := path.Match(strings.ToLower(rule.Folder), folder); !matched {
+			continue
+		}
+		if rule.MaxSize > 0 && info.Size() > rule.MaxSize {
+			return fmt.Sprintf("larger than %s", formatSize(rule.MaxSize))
+		}
+		if rule.MinSize > 0 && info.Size() < rule.MinSize {
+			return fmt.Sprintf("smaller than %s", formatSize(rule.MinSize))
+		}
+	}
+	return ""
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, suffix := float64(size), "KMGT"
+	i := -1
+	for value >= unit && i < len(suffix)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, suffix[i])
+}
+
+// emitSkipped notes a file that wasn't hashed, skipped files are listed in
+// reports so nothing is silently missed.
+func emitSkipped(events chan<- ScanEvent, displayedPath string, reason string) {
+	events <- ScanEvent{Kind: EventSkipped, Message: fmt.Sprintf("Not hashed, %s: %s", reason, displayedPath)}
+}
@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+// newTestApp returns an App with list as its database, with the data folder
+// in a temporary folder so no settings are read.
+func newTestApp(t *testing.T, list TitleList) *App {
+	t.Helper()
+	// One title folder at a time, so events come in folder order
+	a := &App{Config: Config{DataPath: t.TempDir(), Jobs: 1}, Titles: list}
+	buildIndexes(&a.Titles)
+	return a
+}
+
+// collectEvents runs scan and returns the events it emitted.
+func collectEvents(scan func(events chan<- ScanEvent) error) ([]ScanEvent, error) {
+	events := make(chan ScanEvent)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(events)
+		errc <- scan(events)
+	}()
+
+	var collected []ScanEvent
+	for event := range events {
+		collected = append(collected, event)
+	}
+	return collected, <-errc
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//go:embed images/xboxIcon.svg
+var xboxIconSVG []byte
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"statusLabel":    statusLabel,
+	"displayTitleID": displayTitleID,
+	"offeringColumn": offeringColumn,
+	"modifiedColumn": modifiedColumn,
+	"nameColumn":     nameColumn,
+	"join":           strings.Join,
+	"unknownTitle":   unknownTitleContents,
+	// Depend on the database and data folder, writeHTMLReport sets the App's
+	"thumbnail":     func(string) template.URL { return "" },
+	"titleMetadata": func(string) string { return "" },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Pinecone Report - {{.Report.Created.Format "2006-01-02 15:04:05"}}</title>
+<link rel="icon" href="{{.Icon}}">
+<style>
+body { background: #1e1e1e; color: #ddd; font-family: sans-serif; margin: 2em; }
+header { display: flex; align-items: center; gap: 1em; }
+header img { width: 48px; height: 48px; }
+details { background: #2a2a2a; border-radius: 6px; margin: 0.5em 0; padding: 0.5em 1em; }
+summary { cursor: pointer; font-weight: bold; color: #00b3b3; }
+table { border-collapse: collapse; width: 100%; margin-top: 0.5em; }
+td, th { text-align: left; padding: 0.25em 0.5em; border-bottom: 1px solid #3a3a3a; }
+code { font-family: monospace; }
+.archived { color: #4caf50; }
+.unarchived { color: #ffc107; }
+.unknown { color: #f44336; }
+.bad { color: #9e9e9e; }
+img.thumbnail { display: block; max-width: 96px; max-height: 96px; margin-top: 0.25em; }
+</style>
+</head>
+<body>
+<header>
+<img src="{{.Icon}}" alt="">
+<div>
+<h1>Pinecone v{{.Report.Version}}</h1>
+<div>Scanned {{.Report.DumpLocation}} on {{.Report.Created.Format "2006-01-02 15:04:05"}}</div>
+{{with .Report.ID}}<div>Report ID: <code>{{.}}</code></div>{{end}}
+<div>{{.Report.Producer}}</div>
+{{with .Report.Console}}<div>Console region: {{.RegionSummary}}</div>{{end}}
+{{with .Report.Fingerprint}}<div>Dump fingerprint: <code>{{.}}</code></div>{{end}}
+</div>
+</header>
+<p>
+<span class="unknown">{{.Report.Count "unknown"}} unknown</span>,
+<span class="unarchived">{{.Report.Count "unarchived"}} unarchived</span>,
+<span class="archived">{{.Report.Count "archived"}} archived</span>
+</p>
+<h2>Action needed</h2>
+{{if and (not .Interesting.Findings) (not .Report.UnknownTitles)}}<p>Nothing new, everything found is already archived.</p>{{end}}
+{{with .Report.UnknownByConfidence}}
+<details open>
+<summary>Unknown content, most promising first</summary>
+<table>
+<tr><th>Confidence</th><th>Title</th><th>Type</th><th>Path</th><th>Signals</th></tr>
+{{range .}}<tr class="unknown"><td>{{.Confidence.Score}}%</td><td>{{.TitleName}}</td><td>{{.Kind}}</td><td><code>{{.Path}}</code></td><td>{{join .Confidence.Signals ", "}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}
+{{template "titles" .Interesting.Titles}}
+{{with .Report.UnknownTitles}}
+<details open>
+<summary>Titles missing from the database</summary>
+<table>
+<tr><th>Title ID</th><th>Community name</th><th>Contents</th><th>Path</th></tr>
+{{range .}}<tr class="unknown"><td>{{displayTitleID .TitleID}}</td><td>{{if .Name}}{{.Name}} ({{.NameSource}}){{end}}</td><td>{{unknownTitle .}}</td><td><code>{{.Path}}</code></td></tr>
+{{end}}</table>
+</details>
+{{end}}
+{{with .Report.PossiblePrototypes}}
+<h2>Possible prototypes</h2>
+<p>Executables with hints of a prototype or pre-release build, worth a closer look.</p>
+<table>
+<tr><th>Title</th><th>Type</th><th>Path</th><th>SHA1</th><th>Hints</th></tr>
+{{range .}}<tr class="unknown"><td>{{.TitleName}}</td><td>{{.Kind}}</td><td><code>{{.Path}}</code></td><td><code>{{.SHA1}}</code></td><td>{{join .Prototype ", "}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Archived.Titles}}
+<h2>Already archived</h2>
+{{template "titles" .}}
+{{end}}
+{{with .KnownBad.Titles}}
+<h2>Known bad</h2>
+<p>Corrupt or fake files from the database's known bad list, not worth submitting.</p>
+{{template "titles" .}}
+{{end}}
+{{with .Report.SystemTitles}}
+<h2>System titles</h2>
+<p>Folders of the dashboard and other system software, not checked against the database.</p>
+<ul>
+{{range .}}<li><code>{{.TitleID}}</code>{{with .Name}} {{.}}{{end}}{{with .Description}}, {{.}}{{end}}: {{.Contents}} at <code>{{.Path}}</code></li>
+{{end}}</ul>
+{{end}}
+{{if or .NotFound .Report.Skipped}}
+<h2>Not found</h2>
+{{with .NotFound}}
+<details open>
+<summary>Titles scanned without any content or updates</summary>
+<ul>
+{{range .}}<li>{{.TitleName}} ({{displayTitleID .TitleID}})</li>
+{{end}}</ul>
+</details>
+{{end}}
+{{with .Report.Skipped}}
+<details open>
+<summary>Not hashed</summary>
+<ul>
+{{range .}}<li class="unarchived">{{.}}</li>
+{{end}}</ul>
+</details>
+{{end}}
+{{end}}
+{{with .Credit}}<footer><hr><p>{{.}}</p></footer>{{end}}
+</body>
+</html>
+{{define "titles"}}{{range .}}
+<details open>
+<summary>{{.TitleName}} ({{displayTitleID .TitleID}})</summary>
+{{with titleMetadata .TitleID}}<p><em>{{.}}</em></p>{{end}}
+<table>
+<tr><th>Type</th><th>Status</th><th>Name</th><th>Offering</th><th>Modified</th><th>Path</th><th>SHA1</th></tr>
+{{range .Findings}}<tr class="{{.Status}}"><td>{{.Kind}}</td><td>{{statusLabel .Status}}</td><td>{{nameColumn .}}{{with thumbnail .ContentID}}<img class="thumbnail" src="{{.}}" alt="">{{end}}</td><td>{{offeringColumn .}}</td><td>{{modifiedColumn .}}</td><td><code>{{.Path}}</code>{{range .Also}}<br>also <code>{{.}}</code>{{end}}</td><td>{{if .SHA1}}<code>{{.SHA1}}</code>{{else if .Digest}}digest <code>{{.Digest}}</code>{{end}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}{{end}}`))
+
+func statusLabel(status string) string {
+	switch status {
+	case statusArchived:
+		return "Known and archived"
+	case statusUnarchived:
+		return "Known, not archived"
+	case statusKnownBad:
+		return "Known bad"
+	default:
+		return "Unknown"
+	}
+}
+
+// writeHTMLReport renders a self-contained HTML report, the icon is embedded
+// so the file can be shared on its own. Like the Markdown report, findings
+// are grouped into action needed, already archived and not found.
+func (a *App) writeHTMLReport(w io.Writer, report *Report, settings *Settings) error {
+	if a.anonymizeEnabled(settings) {
+		report = a.anonymizeReport(report)
+	}
+	tmpl := template.Must(htmlReportTemplate.Clone()).Funcs(template.FuncMap{
+		"thumbnail":     a.thumbnailDataURI,
+		"titleMetadata": a.titleMetadata,
+	})
+	return tmpl.Execute(w, struct {
+		Report      *Report
+		Interesting *Report
+		Archived    *Report
+		KnownBad    *Report
+		NotFound    []ScannedTitle
+		Icon        template.URL
+		Credit      string
+	}{
+		Report:      report,
+		Interesting: report.Interesting(),
+		Archived:    report.Archived(),
+		KnownBad:    report.KnownBad(),
+		NotFound:    report.NotFound(),
+		Icon:        template.URL("data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(xboxIconSVG)),
+		Credit:      creditBlock(report, settings),
+	})
+}
+
+func (a *App) exportHTMLReport(outputPath string, settings *Settings) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return a.writeHTMLReport(file, &a.Report, settings)
+}
+
+// defaultReportPath returns a timestamped path in the output folder.
+func (a *App) defaultReportPath(prefix string, ext string) string {
+	timestamp := time.Now().Format("2006-01-02-15-04-05")
+	return filepath.Join(a.Config.DataPath, "output", prefix+"-"+timestamp+ext)
+}
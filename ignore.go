@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+func (a *App) loadIgnoredTitles() error {
+	a.IgnoredTitles = nil
+	settings, err := a.loadSettings()
+	if err != nil {
+		return nil
+	}
+	a.IgnoredItems = settings.IgnoredItems
+	for _, pattern := range settings.IgnoredTitles {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("Error in ignoredTitles: invalid title ID pattern %q: %v", pattern, err)
+		}
+		a.IgnoredTitles = append(a.IgnoredTitles, pattern)
+	}
+	return nil
+}
+
+// titleIgnored tells whether a title ID is in the ignored titles.
+func (a *App) titleIgnored(titleID string) bool {
+	for _, pattern := range a.IgnoredTitles {
+		if ok, _ := path.Match(pattern, strings.ToLower(titleID)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// itemIgnored tells whether a finding was ignored during triage.
+func (a *App) itemIgnored(f Finding) bool {
+	return f.key() != "" && contains(a.IgnoredItems, f.key())
+}
+
+// ignoreTitle adds a title ID to the ignored titles in the settings.
+func (a *App) ignoreTitle(titleID string) error {
+	return a.ignoreInSettings(nil, []string{titleID})
+}
+
+// ignoreInSettings adds finding keys to the ignored items and title IDs to
+// the ignored titles in the settings.
+func (a *App) ignoreInSettings(itemKeys []string, titleIDs []string) error {
+	settings, err := a.loadSettings()
+	if err != nil {
+		return err
+	}
+	for _, key := range itemKeys {
+		if !contains(settings.IgnoredItems, key) {
+			settings.IgnoredItems = append(settings.IgnoredItems, key)
+		}
+	}
+	for _, titleID := range titleIDs {
+		if !contains(settings.IgnoredTitles, titleID) {
+			settings.IgnoredTitles = append(settings.IgnoredTitles, titleID)
+		}
+	}
+	return a.saveSettings(settings)
+}
+
+// navigationItem is a title in the navigation pane, right click it to ignore
+// the title in future scans.
+type navigationItem struct {
+	widget.Label
+	app     *App
+	titleID string
+}
+
+func (a *App) newNavigationItem() *navigationItem {
+	item := &navigationItem{app: a}
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+func (item *navigationItem) TappedSecondary(event *fyne.PointEvent) {
+	titleID := item.titleID
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem(fmt.Sprintf("Ignore %s in future scans", displayTitleID(titleID)), func() {
+			if err := item.app.ignoreTitle(titleID); err != nil {
+				dialog.ShowError(err, guiWindow)
+				return
+			}
+			dialog.ShowInformation("Title ignored", fmt.Sprintf("%s won't be scanned anymore. Remove it from the ignored title IDs in the settings to scan it again.",
+				displayTitleID(titleID)), guiWindow)
+		}),
+	)
+	widget.ShowPopUpMenuAtPosition(menu, fyne.CurrentApp().Driver().CanvasForObject(item), event.AbsolutePosition)
+}
@@ -8,97 +8,280 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"strings"
 
 	"fyne.io/fyne/v2/theme"
 )
 
+// removeCommentsFromJSON strips comments, keeping line breaks so that line
+// numbers in validation errors match the original file.
 func removeCommentsFromJSON(jsonStr string) string {
 	// remove // style comments
-	re := regexp.MustCompile(`(?m)^[ \t]*//.*\n?`)
+	re := regexp.MustCompile(`(?m)^[ \t]*//.*$`)
 	jsonStr = re.ReplaceAllString(jsonStr, "")
 
 	// remove /* ... */ style comments
 	re = regexp.MustCompile(`/\*[\s\S]*?\*/`)
-	jsonStr = re.ReplaceAllString(jsonStr, "")
+	jsonStr = re.ReplaceAllStringFunc(jsonStr, func(comment string) string {
+		return strings.Repeat("\n", strings.Count(comment, "\n"))
+	})
 
 	return jsonStr
 }
 
-func downloadJSONData(url string) ([]byte, error) {
+func (a *App) downloadJSONData(url string) ([]byte, error) {
+	return a.githubRequest(url, "application/vnd.github.v3.raw")
+}
+
+func (a *App) githubRequest(url string, accept string) ([]byte, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/vnd.github.v3.raw")
+	req.Header.Set("Accept", accept)
+	if token := a.githubToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := a.httpDo(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return nil, fmt.Errorf("GitHub API rate limit exceeded, set a GitHub token in the settings or GITHUB_TOKEN")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from %s: %s", url, resp.Status)
+	}
+
 	return io.ReadAll(resp.Body)
 }
 
-func loadJSONData(jsonFilePath, owner, repo, path string, v interface{}, updateFlag bool) error {
-	if updateFlag {
+// githubToken returns the token from the settings, or GITHUB_TOKEN. Anonymous
+// API calls are rate limited per IP, which shared networks hit quickly.
+func (a *App) githubToken() string {
+	if settings, err := a.loadSettings(); err == nil && settings.GitHubToken != "" {
+		return settings.GitHubToken
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
 
-		// Notify we're checking for updates
-		fmt.Printf("Checking for PineCone updates..\n")
+// fetchContentSHA returns the git blob SHA GitHub reports for a file.
+func (a *App) fetchContentSHA(url string) (string, error) {
+	body, err := a.githubRequest(url, "application/vnd.github.v3+json")
+	if err != nil {
+		return "", err
+	}
 
-		// Download JSON data
-		jsonData, err := downloadJSONData(fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", owner, repo, path))
+	var content struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.Unmarshal(body, &content); err != nil {
+		return "", err
+	}
+	if content.SHA == "" {
+		return "", fmt.Errorf("no SHA in response from %s", url)
+	}
+	return content.SHA, nil
+}
+
+// gitBlobSHA hashes data the way git does for blobs, matching the SHA of the
+// GitHub content API.
+func gitBlobSHA(data []byte) string {
+	hash := sha1.New()
+	fmt.Fprintf(hash, "blob %d\x00", len(data))
+	hash.Write(data)
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// verifyJSONData makes sure a downloaded database is complete and usable
+// before it replaces the local copy.
+func verifyJSONData(jsonData []byte, expectedSHA string) error {
+	if sha := gitBlobSHA(jsonData); sha != expectedSHA {
+		return fmt.Errorf("downloaded database is corrupt (SHA %s, expected %s)", sha, expectedSHA)
+	}
+	return checkDownloadedDatabase(jsonData)
+}
+
+// checkDownloadedDatabase makes sure a download parses, passes validation and
+// lists titles.
+func checkDownloadedDatabase(jsonData []byte) error {
+	jsonStr := removeCommentsFromJSON(string(jsonData))
+	if err := validateDatabase(jsonStr); err != nil {
+		return fmt.Errorf("downloaded database is invalid: %v", err)
+	}
+	var list TitleList
+	if err := json.Unmarshal([]byte(jsonStr), &list); err != nil {
+		return fmt.Errorf("downloaded database is invalid: %v", err)
+	}
+	if len(list.Titles) == 0 {
+		return fmt.Errorf("downloaded database contains no titles")
+	}
+	return nil
+}
+
+func (a *App) loadJSONData(jsonFilePath, owner, repo, path string, v interface{}, updateFlag bool) error {
+	if updateFlag && a.recentlyChecked(jsonFilePath) {
+		printLine("Database was checked for updates recently and is up to date, use -force-update to check again.")
+		updateFlag = false
+	}
+
+	if updateFlag && a.signedUpdateEnabled() {
+		printLine("Checking for signed database releases..")
+		jsonData, signature, err := a.downloadSignedDatabase(a.databaseReleaseURL(owner, repo))
 		if err != nil {
 			return err
 		}
-
-		// Check if downloaded JSON is different from existing JSON
-		if _, err := os.Stat(jsonFilePath); err == nil {
-			existingData, err := os.ReadFile(jsonFilePath)
-			if err != nil {
-				return err
-			}
-			existingHash := fmt.Sprintf("%x", sha1.Sum(existingData))
-			newHash := fmt.Sprintf("%x", sha1.Sum(jsonData))
-			if existingHash == newHash {
-				return json.Unmarshal(existingData, &v)
-			}
+		if err := checkDownloadedDatabase(jsonData); err != nil {
+			return err
 		}
+		if err := a.installJSONData(jsonFilePath, path, jsonData, signature, v); err != nil {
+			return err
+		}
+		a.saveUpdateCheck(gitBlobSHA(jsonData))
+		return nil
+	}
+
+	if updateFlag {
 
-		// Write the newly downloaded JSON to file
-		if guiEnabled {
-			addText(theme.ForegroundColor(), "Updating %s...", jsonFilePath)
-		} else {
-			fmt.Printf("Updating %s...\n", jsonFilePath)
+		// Notify we're checking for updates
+		printLine("Checking for PineCone updates..")
+
+		// Compare the remote hash first, there's nothing to download if the
+		// local copy is current
+		contentURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", owner, repo, path)
+		expectedSHA, err := a.fetchContentSHA(contentURL)
+		if err != nil {
+			return fmt.Errorf("could not check for database updates: %v", err)
 		}
-		err = os.WriteFile(jsonFilePath, jsonData, 0o644)
+		if localData, err := os.ReadFile(jsonFilePath); err == nil && gitBlobSHA(localData) == expectedSHA {
+			a.saveUpdateCheck(expectedSHA)
+			printLine("Database is up to date.")
+			return a.loadJSONData(jsonFilePath, owner, repo, path, v, false)
+		}
+
+		// Download JSON data
+		jsonData, err := a.downloadJSONData(contentURL)
 		if err != nil {
 			return err
 		}
 
-		// Load the newly downloaded JSON data
-		if guiEnabled {
-			addText(theme.ForegroundColor(), "Reloading %s...", path)
-		} else {
-			fmt.Printf("Reloading %s...\n", path)
+		// Verify the download before it can replace the local copy
+		if err := verifyJSONData(jsonData, expectedSHA); err != nil {
+			return err
 		}
-		jsonStr := removeCommentsFromJSON(string(jsonData))
-		err = json.Unmarshal([]byte(jsonStr), &v)
-		if err != nil {
+		if err := a.installJSONData(jsonFilePath, path, jsonData, nil, v); err != nil {
 			return err
 		}
+		a.saveUpdateCheck(expectedSHA)
+		return nil
 	} else {
 		// Load existing JSON data
 		jsonData, err := os.ReadFile(jsonFilePath)
 		if err != nil {
 			return err
 		}
+		if a.signedUpdateEnabled() {
+			if err := a.verifyLocalDatabase(jsonFilePath, jsonData); err != nil {
+				return err
+			}
+		}
+		// The compiled index skips parsing, as long as it matches the JSON
+		if list, ok := v.(*TitleList); ok && a.compileIndexEnabled() && loadDatabaseIndex(jsonFilePath, jsonData, list) {
+			return nil
+		}
 		jsonStr := removeCommentsFromJSON(string(jsonData))
+		if err := validateDatabase(jsonStr); err != nil {
+			return err
+		}
 		err = json.Unmarshal([]byte(jsonStr), &v)
 		if err != nil {
 			return err
 		}
+		return a.databaseLoaded(jsonFilePath, jsonData, v)
+	}
+}
+
+// installJSONData replaces the local database, and its signature when given,
+// with a verified download and loads it.
+func (a *App) installJSONData(jsonFilePath string, path string, jsonData []byte, signature []byte, v interface{}) error {
+	// Check if downloaded JSON is different from existing JSON
+	var existingData []byte
+	if _, err := os.Stat(jsonFilePath); err == nil {
+		existingData, err = os.ReadFile(jsonFilePath)
+		if err != nil {
+			return err
+		}
+		existingHash := fmt.Sprintf("%x", sha1.Sum(existingData))
+		newHash := fmt.Sprintf("%x", sha1.Sum(jsonData))
+		if existingHash == newHash {
+			if signature != nil {
+				if err := replaceDatabaseFiles(jsonFilePath, nil, signature); err != nil {
+					return err
+				}
+			}
+			if err := json.Unmarshal(existingData, &v); err != nil {
+				return err
+			}
+			return a.databaseLoaded(jsonFilePath, existingData, v)
+		}
 	}
 
+	// Write the newly downloaded JSON to file
+	if a.Config.GUI {
+		a.addText(theme.ForegroundColor(), "Updating %s...", jsonFilePath)
+	} else {
+		printLine(fmt.Sprintf("Updating %s...", jsonFilePath))
+	}
+	if err := replaceDatabaseFiles(jsonFilePath, jsonData, signature); err != nil {
+		return err
+	}
+	a.reportDatabaseChanges(existingData, jsonData)
+
+	// Load the newly downloaded JSON data
+	if a.Config.GUI {
+		a.addText(theme.ForegroundColor(), "Reloading %s...", path)
+	} else {
+		printLine(fmt.Sprintf("Reloading %s...", path))
+	}
+	jsonStr := removeCommentsFromJSON(string(jsonData))
+	if err := validateDatabase(jsonStr); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &v); err != nil {
+		return err
+	}
+	return a.databaseLoaded(jsonFilePath, jsonData, v)
+}
+
+// replaceDatabaseFiles writes the database and its signature, either may be
+// nil, to temporary files first and only renames them into place once both
+// are written. An interrupted update leaves the old copy in place.
+func replaceDatabaseFiles(jsonFilePath string, jsonData, signature []byte) error {
+	var written [][2]string
+	for _, file := range []struct {
+		path string
+		data []byte
+	}{{jsonFilePath, jsonData}, {jsonFilePath + signatureSuffix, signature}} {
+		if file.data == nil {
+			continue
+		}
+		tmpPath := file.path + ".tmp"
+		if err := os.WriteFile(tmpPath, file.data, 0o644); err != nil {
+			for _, w := range written {
+				os.Remove(w[0])
+			}
+			os.Remove(tmpPath)
+			return fmt.Errorf("Error writing %s: %v", file.path, err)
+		}
+		written = append(written, [2]string{tmpPath, file.path})
+	}
+	for _, w := range written {
+		if err := os.Rename(w[0], w[1]); err != nil {
+			return fmt.Errorf("Error replacing %s: %v", w[1], err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// titleLanguage is the language code localized title names are shown in,
+// empty for the database's default names.
+var titleLanguage = ""
+
+// resolveTitleLanguage picks the language for title names: the -lang flag,
+// then "language" in the settings, then the system locale (LC_ALL,
+// LC_MESSAGES or LANG, e.g. "ja_JP.UTF-8" is "ja").
+func (a *App) resolveTitleLanguage(settings *Settings) string {
+	if a.Config.Language != "" {
+		return normalizeLanguage(a.Config.Language)
+	}
+	if settings != nil && settings.Language != "" {
+		return normalizeLanguage(settings.Language)
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			return normalizeLanguage(locale)
+		}
+	}
+	return ""
+}
+
+func normalizeLanguage(locale string) string {
+	language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(locale)), "_")
+	language, _, _ = strings.Cut(language, ".")
+	language, _, _ = strings.Cut(language, "-")
+	if language == "c" || language == "posix" {
+		return ""
+	}
+	return language
+}
+
+// DisplayName is the title's name in the preferred language, followed by the
+// database's default name when they differ, e.g. "ガンパレード・オーケストラ
+// (Gunparade Orchestra)".
+func (t TitleData) DisplayName() string {
+	name := t.LocalizedNames[titleLanguage]
+	if name == "" || name == t.TitleName {
+		return t.TitleName
+	}
+	return name + " (" + t.TitleName + ")"
+}
+
+// Names returns the default and every localized name of the title, for
+// searching and filtering.
+func (t TitleData) Names() []string {
+	names := []string{t.TitleName}
+	for _, name := range t.LocalizedNames {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Default window size and split pane offsets, used until the user resizes
+// them.
+const (
+	defaultWindowWidth     = 1000
+	defaultWindowHeight    = 700
+	defaultNavigationSplit = 0.22
+	defaultDetailsSplit    = 0.65
+	defaultLogSplit        = 0.8
+	// logLineLimit caps the lines the log pane keeps, the oldest tenth is
+	// dropped past it.
+	logLineLimit = 5000
+)
+
+// navigationTitle is a title found by the last scan, listed in the navigation
+// pane.
+type navigationTitle struct {
+	TitleID   string
+	TitleName string
+}
+
+var (
+	navigationTitles []navigationTitle
+	navigationList   *widget.List
+	detailsContainer = container.NewStack(detailsPlaceholder())
+	// The log pane is a virtualized list like the scan output, a drive full
+	// of unreadable files can log as many lines as it has findings.
+	logMu      sync.Mutex
+	logEntries []outputLine
+	logList    *widget.List
+	// logDropped is set once the log pane dropped lines, which are read back
+	// from the scan log.
+	logDropped bool
+)
+
+func detailsPlaceholder() fyne.CanvasObject {
+	return widget.NewLabel("Select a title to see its details.")
+}
+
+// addNavigationTitle lists a title found during a scan in the navigation pane.
+func addNavigationTitle(titleID string, titleName string) {
+	for _, title := range navigationTitles {
+		if title.TitleID == titleID {
+			return
+		}
+	}
+	navigationTitles = append(navigationTitles, navigationTitle{TitleID: titleID, TitleName: titleName})
+	if navigationList != nil {
+		navigationList.Refresh()
+	}
+}
+
+// clearPanes empties the navigation, details and log panes for a new scan.
+func clearPanes() {
+	navigationTitles = nil
+	if navigationList != nil {
+		navigationList.UnselectAll()
+		navigationList.Refresh()
+	}
+	detailsContainer.Objects = []fyne.CanvasObject{detailsPlaceholder()}
+	detailsContainer.Refresh()
+	logMu.Lock()
+	logEntries = nil
+	logDropped = false
+	logMu.Unlock()
+	if logList != nil {
+		logList.ScrollToTop()
+		logList.Refresh()
+	}
+}
+
+// showDetailsPane shows the details of a title next to the results.
+func (a *App) showDetailsPane(titleID string) {
+	if details, ok := a.titleDetailsContent(titleID); ok {
+		detailsContainer.Objects = []fyne.CanvasObject{container.NewVScroll(details)}
+		detailsContainer.Refresh()
+	}
+}
+
+// addLog adds a line to the log pane, scan warnings and errors go there so
+// they don't get lost between the findings. They are streamed to the scan
+// log too.
+func (a *App) addLog(textColor color.Color, format string, args ...interface{}) {
+	text := fmt.Sprintf(format, args...)
+	a.writeScanLogLine(text)
+	logMu.Lock()
+	logEntries = append(logEntries, outputLine{Text: text, Color: textColor})
+	if len(logEntries) > logLineLimit {
+		logEntries = append([]outputLine(nil), logEntries[len(logEntries)-logLineLimit*9/10:]...)
+		logDropped = true
+	}
+	logMu.Unlock()
+	if logList != nil {
+		logList.Refresh()
+	}
+}
+
+// logLines returns the text of the log pane. The lines dropped from the
+// window are read back from the scan log.
+func logLines() []string {
+	logMu.Lock()
+	defer logMu.Unlock()
+	if logDropped {
+		outputMu.Lock()
+		lines, ok := scanLogLines(true)
+		outputMu.Unlock()
+		if ok {
+			return lines
+		}
+	}
+	lines := make([]string, 0, len(logEntries))
+	for _, line := range logEntries {
+		lines = append(lines, line.Text)
+	}
+	return lines
+}
+
+func logLineAt(id widget.ListItemID) (outputLine, bool) {
+	logMu.Lock()
+	defer logMu.Unlock()
+	if id < 0 || id >= len(logEntries) {
+		return outputLine{}, false
+	}
+	return logEntries[id], true
+}
+
+// newLogList creates the list showing the log pane.
+func newLogList() *widget.List {
+	logList = widget.NewList(
+		func() int {
+			logMu.Lock()
+			defer logMu.Unlock()
+			return len(logEntries)
+		},
+		func() fyne.CanvasObject {
+			return canvas.NewText("", theme.ForegroundColor())
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			line, ok := logLineAt(id)
+			if !ok {
+				return
+			}
+			text := obj.(*canvas.Text)
+			text.Text = line.Text
+			text.Color = line.Color
+			text.Refresh()
+		},
+	)
+	logList.OnSelected = func(id widget.ListItemID) {
+		logList.Unselect(id)
+	}
+	return logList
+}
+
+// scanPanes lays out the Scan tab as resizable split panes: the titles found
+// on the left, the results with the selected title's details next to them
+// and the log below. The returned function stores the pane sizes in the
+// settings.
+func (a *App) scanPanes(results fyne.CanvasObject, settings *Settings) (fyne.CanvasObject, func(*Settings)) {
+	navigationList = widget.NewList(
+		func() int {
+			return len(navigationTitles)
+		},
+		func() fyne.CanvasObject {
+			return a.newNavigationItem()
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			item := obj.(*navigationItem)
+			item.titleID = navigationTitles[id].TitleID
+			item.SetText(navigationTitles[id].TitleName)
+		},
+	)
+	navigationList.OnSelected = func(id widget.ListItemID) {
+		if id < len(navigationTitles) {
+			a.showDetailsPane(navigationTitles[id].TitleID)
+		}
+	}
+
+	detailsSplit := container.NewHSplit(results, detailsContainer)
+	detailsSplit.Offset = splitOffset(settings.DetailsSplit, defaultDetailsSplit)
+	logSplit := container.NewVSplit(detailsSplit, newLogList())
+	logSplit.Offset = splitOffset(settings.LogSplit, defaultLogSplit)
+	navigationSplit := container.NewHSplit(navigationList, logSplit)
+	navigationSplit.Offset = splitOffset(settings.NavigationSplit, defaultNavigationSplit)
+
+	storeOffsets := func(settings *Settings) {
+		settings.NavigationSplit = navigationSplit.Offset
+		settings.DetailsSplit = detailsSplit.Offset
+		settings.LogSplit = logSplit.Offset
+	}
+	return navigationSplit, storeOffsets
+}
+
+func splitOffset(offset float64, fallback float64) float64 {
+	if offset <= 0 || offset >= 1 {
+		return fallback
+	}
+	return offset
+}
+
+// windowSize is the window size saved in the settings, or the default one.
+func windowSize(settings *Settings) fyne.Size {
+	if settings.WindowWidth <= 0 || settings.WindowHeight <= 0 {
+		return fyne.NewSize(defaultWindowWidth, defaultWindowHeight)
+	}
+	return fyne.NewSize(settings.WindowWidth, settings.WindowHeight)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestEntry is a file of a dump with its hash, the path is relative to
+// the dump and slash separated.
+type ManifestEntry struct {
+	Path string
+	SHA1 string
+}
+
+// buildManifest hashes every file under the TDATA and UDATA folders of a dump.
+func (a *App) buildManifest(location string) ([]ManifestEntry, error) {
+	fsys, tdata, closeDump, err := openDump(location)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDump()
+
+	roots := []string{tdata}
+	if udata, found := findSubDir(fsys, path.Dir(tdata), "UDATA"); found {
+		roots = append(roots, udata)
+	}
+
+	var entries []ManifestEntry
+	for _, root := range roots {
+		err := walkDump(fsys, root, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			fileHash, err := a.getSHA1HashFS(location, fsys, name)
+			if err != nil {
+				return fmt.Errorf("Error calculating hash for file: %s, error: %v", displayPath(location, name), err)
+			}
+			entries = append(entries, ManifestEntry{Path: name, SHA1: fileHash})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
+
+// writeManifest writes the entries in the format of sha1sum, so the manifest
+// can also be checked with "sha1sum -c" from the dump folder.
+func writeManifest(w io.Writer, entries []ManifestEntry) error {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "%s  %s\n", entry.SHA1, entry.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *App) exportManifest(location string, outputPath string) error {
+	entries, err := a.buildManifest(location)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := writeManifest(file, entries); err != nil {
+		return err
+	}
+	fmt.Printf("Manifest of %d file(s) in %s saved to: %s\n", len(entries), location, outputPath)
+	return nil
+}
+
+// readManifest parses a sha1sum style manifest, "<sha1>  <path>" per line.
+// The "*" binary mode marker of sha1sum is accepted.
+func readManifest(r io.Reader) ([]ManifestEntry, error) {
+	var entries []ManifestEntry
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hash, name, found := strings.Cut(line, " ")
+		name = strings.TrimPrefix(strings.TrimPrefix(name, " "), "*")
+		if !found || !sha1Pattern.MatchString(strings.ToLower(hash)) || name == "" {
+			return nil, fmt.Errorf("Error reading manifest: line %d isn't \"<sha1>  <path>\"", lineNumber)
+		}
+		entries = append(entries, ManifestEntry{Path: name, SHA1: strings.ToLower(hash)})
+	}
+	return entries, scanner.Err()
+}
+
+// ManifestDifferences are the files of a dump that don't match its manifest.
+type ManifestDifferences struct {
+	Changed []string
+	Missing []string
+	Added   []string
+}
+
+func (d ManifestDifferences) Empty() bool {
+	return len(d.Changed) == 0 && len(d.Missing) == 0 && len(d.Added) == 0
+}
+
+// compareManifest compares a dump's current files with its manifest.
+func compareManifest(manifest []ManifestEntry, current []ManifestEntry) ManifestDifferences {
+	expected := make(map[string]string)
+	for _, entry := range manifest {
+		expected[entry.Path] = entry.SHA1
+	}
+
+	var differences ManifestDifferences
+	found := make(map[string]bool)
+	for _, entry := range current {
+		found[entry.Path] = true
+		hash, ok := expected[entry.Path]
+		if !ok {
+			differences.Added = append(differences.Added, entry.Path)
+		} else if hash != entry.SHA1 {
+			differences.Changed = append(differences.Changed, entry.Path)
+		}
+	}
+	for _, entry := range manifest {
+		if !found[entry.Path] {
+			differences.Missing = append(differences.Missing, entry.Path)
+		}
+	}
+	sort.Strings(differences.Missing)
+	return differences
+}
+
+// verifyManifest re-hashes a dump and prints the files that changed, went
+// missing or were added since the manifest was made, returning the
+// differences.
+func (a *App) verifyManifest(location string, manifestPath string) (ManifestDifferences, error) {
+	file, err := os.Open(manifestPath)
+	if err != nil {
+		return ManifestDifferences{}, fmt.Errorf("Error opening manifest: %v", err)
+	}
+	manifest, err := readManifest(file)
+	file.Close()
+	if err != nil {
+		return ManifestDifferences{}, err
+	}
+
+	current, err := a.buildManifest(location)
+	if err != nil {
+		return ManifestDifferences{}, err
+	}
+	differences := compareManifest(manifest, current)
+
+	for _, group := range []struct {
+		label string
+		paths []string
+	}{
+		{"Changed", differences.Changed},
+		{"Missing", differences.Missing},
+		{"Added", differences.Added},
+	} {
+		for _, p := range group.paths {
+			fmt.Printf("%s: %s\n", group.label, p)
+		}
+	}
+	fmt.Printf("%d file(s) in the manifest, %d changed, %d missing, %d added.\n",
+		len(manifest), len(differences.Changed), len(differences.Missing), len(differences.Added))
+	return differences, nil
+}
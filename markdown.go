@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// creditLine formats the user info from the settings the way contributors are
+// credited in submissions, e.g. "Found by: Cleet (Discord: @cleet)".
+func creditLine(settings *Settings) string {
+	var socials []string
+	if settings.Discord != "" {
+		socials = append(socials, "Discord: @"+settings.Discord)
+	}
+	if settings.Twitter != "" {
+		socials = append(socials, "Twitter: @"+settings.Twitter)
+	}
+	if settings.Reddit != "" {
+		socials = append(socials, "Reddit: u/"+settings.Reddit)
+	}
+
+	name := settings.UserName
+	if name == "" && len(socials) == 0 {
+		return ""
+	}
+	if name == "" {
+		name = "Anonymous"
+	}
+	if len(socials) == 0 {
+		return "Found by: " + name
+	}
+	return fmt.Sprintf("Found by: %s (%s)", name, strings.Join(socials, ", "))
+}
+
+// creditBlock returns the credit line to append to a report that includes
+// unknown or unarchived content, so maintainers can credit the contributor
+// in the database changelog without asking. Empty if there's nothing new or
+// no user info is configured.
+func creditBlock(report *Report, settings *Settings) string {
+	if len(report.Interesting().Findings) == 0 {
+		return ""
+	}
+	return creditLine(settings)
+}
+
+// offeringColumn is the offering decoding and marketplace match of a finding.
+func offeringColumn(f Finding) string {
+	if f.Listing == "" {
+		return f.Offering
+	}
+	return f.Offering + "; " + f.Listing
+}
+
+// nameColumn is the name of a finding followed by its media, if any. Unknown
+// updates show the name proposed for them.
+func nameColumn(f Finding) string {
+	if f.SuggestedName != "" {
+		return "Suggested: " + f.SuggestedName
+	}
+	if f.Media == "" {
+		return f.Name
+	}
+	if f.Name == "" {
+		return "Media: " + f.Media
+	}
+	return f.Name + "; media: " + f.Media
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// writeMarkdownReport renders the report as GitHub flavored Markdown tables
+// per title, ready to paste into an issue, or with the user's report template
+// if one is configured. Findings are grouped into what needs action (unknown
+// and unarchived), what is already archived and the titles where nothing was
+// found, so the important part comes first.
+func (a *App) writeMarkdownReport(w io.Writer, report *Report, settings *Settings) error {
+	if a.anonymizeEnabled(settings) {
+		report = a.anonymizeReport(report)
+	}
+	if templatePath := a.reportTemplateFile(settings); templatePath != "" {
+		return a.writeTemplateReport(w, templatePath, report, settings)
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Pinecone v%s report\n\n", report.Version)
+	fmt.Fprintf(&b, "Scanned on %s\n\n", report.Created.Format("2006-01-02 15:04:05"))
+	if report.ID != "" {
+		fmt.Fprintf(&b, "Report ID: `%s`  \n%s\n\n", report.ID, report.Producer())
+	}
+	if report.Console != nil {
+		fmt.Fprintf(&b, "Console region: %s\n\n", report.Console.RegionSummary())
+	}
+	if fingerprint := report.Fingerprint(); fingerprint != "" {
+		fmt.Fprintf(&b, "Dump fingerprint: `%s`\n\n", fingerprint)
+	}
+	fmt.Fprintf(&b, "**%d unknown, %d unarchived, %d archived", report.Count(statusUnknown), report.Count(statusUnarchived), report.Count(statusArchived))
+	if knownBad := report.Count(statusKnownBad); knownBad > 0 {
+		fmt.Fprintf(&b, ", %d known bad", knownBad)
+	}
+	b.WriteString("**\n\n")
+
+	b.WriteString("### Action needed\n\n")
+	interesting := report.Interesting()
+	if len(interesting.Findings) == 0 && len(report.UnknownTitles) == 0 {
+		b.WriteString("Nothing new, everything found is already archived.\n\n")
+	}
+	if unknown := report.UnknownByConfidence(); len(unknown) > 0 {
+		b.WriteString("#### Unknown content, most promising first\n\n")
+		b.WriteString("| Confidence | Title | Type | Path | Signals |\n")
+		b.WriteString("|------------|-------|------|------|---------|\n")
+		for _, f := range unknown {
+			fmt.Fprintf(&b, "| %d%% | %s | %s | `%s` | %s |\n", f.Confidence.Score, markdownEscape(f.TitleName), f.Kind,
+				markdownEscape(f.Path), markdownEscape(strings.Join(f.Confidence.Signals, ", ")))
+		}
+		b.WriteString("\n")
+	}
+	a.writeMarkdownTitles(&b, interesting.Titles())
+
+	if len(report.UnknownTitles) > 0 {
+		b.WriteString("#### Titles missing from the database\n\n")
+		b.WriteString("| Title ID | Community name | Contents | Path |\n")
+		b.WriteString("|----------|----------------|----------|------|\n")
+		for _, u := range report.UnknownTitles {
+			name := u.Name
+			if name != "" {
+				name += " (" + u.NameSource + ")"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | `%s` |\n", displayTitleID(u.TitleID), markdownEscape(name),
+				markdownEscape(unknownTitleContents(u)), markdownEscape(u.Path))
+		}
+		b.WriteString("\n")
+	}
+
+	if prototypes := report.PossiblePrototypes(); len(prototypes) > 0 {
+		b.WriteString("### Possible prototypes\n\n")
+		b.WriteString("Executables with hints of a prototype or pre-release build, worth a closer look.\n\n")
+		b.WriteString("| Title | Type | Path | SHA1 | Hints |\n")
+		b.WriteString("|-------|------|------|------|-------|\n")
+		for _, f := range prototypes {
+			fmt.Fprintf(&b, "| %s | %s | `%s` | `%s` | %s |\n", markdownEscape(f.TitleName), f.Kind, markdownEscape(f.Path), f.SHA1,
+				markdownEscape(strings.Join(f.Prototype, ", ")))
+		}
+		b.WriteString("\n")
+	}
+
+	if archived := report.Archived().Titles(); len(archived) > 0 {
+		b.WriteString("### Already archived\n\n")
+		a.writeMarkdownTitles(&b, archived)
+	}
+
+	if knownBad := report.KnownBad().Titles(); len(knownBad) > 0 {
+		b.WriteString("### Known bad\n\n")
+		b.WriteString("Corrupt or fake files from the database's known bad list, not worth submitting.\n\n")
+		a.writeMarkdownTitles(&b, knownBad)
+	}
+
+	if len(report.SystemTitles) > 0 {
+		b.WriteString("### System titles\n\n")
+		b.WriteString("Folders of the dashboard and other system software, not checked against the database.\n\n")
+		for _, s := range report.SystemTitles {
+			fmt.Fprintf(&b, "- `%s`", s.TitleID)
+			if s.Name != "" {
+				fmt.Fprintf(&b, " %s", markdownEscape(s.Name))
+			}
+			if s.Description != "" {
+				fmt.Fprintf(&b, ", %s", markdownEscape(s.Description))
+			}
+			fmt.Fprintf(&b, ": %s at `%s`\n", s.Contents(), markdownEscape(s.Path))
+		}
+		b.WriteString("\n")
+	}
+
+	if notFound := report.NotFound(); len(notFound) > 0 || len(report.Skipped) > 0 {
+		b.WriteString("### Not found\n\n")
+		if len(notFound) > 0 {
+			b.WriteString("Titles scanned without any content or updates:\n\n")
+			for _, title := range notFound {
+				fmt.Fprintf(&b, "- %s (`%s`)\n", markdownEscape(title.TitleName), displayTitleID(title.TitleID))
+			}
+			b.WriteString("\n")
+		}
+		if len(report.Skipped) > 0 {
+			b.WriteString("#### Not hashed\n\n")
+			for _, skipped := range report.Skipped {
+				fmt.Fprintf(&b, "- %s\n", markdownEscape(skipped))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if credit := creditBlock(report, settings); credit != "" {
+		fmt.Fprintf(&b, "---\n\n%s\n", credit)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownTitles writes a table per title.
+func (a *App) writeMarkdownTitles(b *strings.Builder, titles []ReportTitle) {
+	for _, title := range titles {
+		fmt.Fprintf(b, "#### %s (`%s`)\n\n", markdownEscape(title.TitleName), displayTitleID(title.TitleID))
+		if metadata := a.titleMetadata(title.TitleID); metadata != "" {
+			fmt.Fprintf(b, "_%s_\n\n", markdownEscape(metadata))
+		}
+		b.WriteString("| Type | Status | Name | Offering | Modified | Path | SHA1 |\n")
+		b.WriteString("|------|--------|------|----------|----------|------|------|\n")
+		for _, f := range title.Findings {
+			sha1 := ""
+			if f.SHA1 != "" {
+				sha1 = "`" + f.SHA1 + "`"
+			} else if f.Digest != "" {
+				sha1 = "digest `" + f.Digest + "`"
+			}
+			paths := "`" + markdownEscape(f.Path) + "`"
+			for _, also := range f.Also {
+				paths += "<br>also `" + markdownEscape(also) + "`"
+			}
+			fmt.Fprintf(b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				f.Kind, statusLabel(f.Status), markdownEscape(nameColumn(f)), markdownEscape(offeringColumn(f)), modifiedColumn(f), paths, sha1)
+		}
+		b.WriteString("\n")
+	}
+}
+
+func (a *App) exportMarkdownReport(outputPath string, settings *Settings) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return a.writeMarkdownReport(file, &a.Report, settings)
+}
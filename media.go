@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Media formats found in DLC: music packs ship WMA (or WAV) tracks, video
+// content XMV movies.
+const (
+	mediaWMA = "WMA audio"
+	mediaWAV = "WAV audio"
+	mediaXMV = "XMV video"
+)
+
+const mediaHeaderSize = 4096
+
+var (
+	asfHeaderGUID         = []byte{0x30, 0x26, 0xb2, 0x75, 0x8e, 0x66, 0xcf, 0x11, 0xa6, 0xd9, 0x00, 0xaa, 0x00, 0x62, 0xce, 0x6c}
+	asfFilePropertiesGUID = []byte{0xa1, 0xdc, 0xab, 0x8c, 0x47, 0xa9, 0xcf, 0x11, 0x8e, 0xe4, 0x00, 0xc0, 0x0c, 0x20, 0x53, 0x65}
+)
+
+// MediaFile is an audio or video file identified by its header, Duration is
+// 0 when the header doesn't tell.
+type MediaFile struct {
+	Format   string
+	Duration time.Duration
+}
+
+// identifyMedia reads the header of a file and returns its media format and
+// duration.
+func identifyMedia(fsys fs.FS, name string) (MediaFile, bool) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return MediaFile{}, false
+	}
+	defer file.Close()
+
+	header := make([]byte, mediaHeaderSize)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return MediaFile{}, false
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, asfHeaderGUID):
+		return MediaFile{Format: mediaWMA, Duration: asfDuration(header)}, true
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		return MediaFile{Format: mediaWAV, Duration: wavDuration(header)}, true
+	case len(header) >= 32 && string(header[12:16]) == "xobX":
+		// Next, this and maximum packet size, the tag, version, width and
+		// height, then the duration in milliseconds.
+		return MediaFile{Format: mediaXMV, Duration: time.Duration(binary.LittleEndian.Uint32(header[28:])) * time.Millisecond}, true
+	}
+	return MediaFile{}, false
+}
+
+// asfDuration reads the play duration from the File Properties object of an
+// ASF header, less the preroll.
+func asfDuration(header []byte) time.Duration {
+	i := bytes.Index(header, asfFilePropertiesGUID)
+	// GUID and size, file ID, file size, creation date, packet count, play
+	// and send duration in 100ns units, then the preroll in milliseconds.
+	if i < 0 || i+88 > len(header) {
+		return 0
+	}
+	play := time.Duration(binary.LittleEndian.Uint64(header[i+64:])) * 100
+	preroll := time.Duration(binary.LittleEndian.Uint64(header[i+80:])) * time.Millisecond
+	if play <= preroll {
+		return 0
+	}
+	return play - preroll
+}
+
+// wavDuration is the size of the data chunk divided by the byte rate of the
+// fmt chunk.
+func wavDuration(header []byte) time.Duration {
+	var byteRate uint32
+	for i := 12; i+8 <= len(header); {
+		id := string(header[i : i+4])
+		size := binary.LittleEndian.Uint32(header[i+4:])
+		switch {
+		case id == "fmt " && i+20 <= len(header):
+			// Format tag, channels and sample rate come first
+			byteRate = binary.LittleEndian.Uint32(header[i+16:])
+		case id == "data" && byteRate > 0:
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second))
+		}
+		i += 8 + int(size) + int(size%2)
+	}
+	return 0
+}
+
+// dlcMedia identifies the media files of a DLC folder.
+func dlcMedia(fsys fs.FS, dir string) []MediaFile {
+	var media []MediaFile
+	walkDump(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if file, ok := identifyMedia(fsys, name); ok {
+			media = append(media, file)
+		}
+		return nil
+	})
+	return media
+}
+
+// describeMedia summarizes media files per format, e.g. "12 WMA audio
+// (48:10)", "" without media.
+func describeMedia(media []MediaFile) string {
+	counts := make(map[string]int)
+	durations := make(map[string]time.Duration)
+	for _, file := range media {
+		counts[file.Format]++
+		durations[file.Format] += file.Duration
+	}
+	var parts []string
+	for format, count := range counts {
+		part := fmt.Sprintf("%d %s", count, format)
+		if durations[format] > 0 {
+			part += " (" + formatDuration(durations[format]) + ")"
+		}
+		parts = append(parts, part)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+func mmapFile(file *os.File) ([]byte, func(), error) {
+	return nil, nil, errMmapUnsupported
+}
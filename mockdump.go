@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+	"unicode/utf16"
+)
+
+// MockDumpOptions configure the mock dump written by "devtool mockdump".
+type MockDumpOptions struct {
+	Titles         int // titles with content picked from the database
+	UnknownDLC     int // content folders missing from the database, per title
+	UnknownUpdates int // title updates missing from the database, per title
+	UnknownTitles  int // title ID folders of titles missing from the database
+	Seed           int64
+}
+
+// MockDump counts what a mock dump holds, what a scan of it should find.
+type MockDump struct {
+	Titles         int
+	KnownDLC       int
+	UnknownDLC     int
+	UnknownUpdates int
+	UnknownTitles  int
+	WantedSaves    int
+}
+
+const (
+	mockFileSize   = 4 << 10
+	mockUpdateSize = 32 << 10
+)
+
+// mockModified dates every mock file, so the same seed reports the same dates.
+var mockModified = time.Date(2005, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// runDevtool runs the maintainer and contributor tools, "pinecone devtool
+// <tool>".
+func (a *App) runDevtool(args []string) {
+	if len(args) == 0 {
+		exitWithError(fmt.Errorf("Usage: pinecone devtool mockdump [options] <folder> | quickhash <file>..."))
+	}
+	switch args[0] {
+	case "mockdump":
+		flags := flag.NewFlagSet("mockdump", flag.ExitOnError)
+		var options MockDumpOptions
+		flags.IntVar(&options.Titles, "titles", 3, "Titles with content to pick from the database")
+		flags.IntVar(&options.UnknownDLC, "unknown-dlc", 1, "Unknown content folders per title")
+		flags.IntVar(&options.UnknownUpdates, "unknown-updates", 1, "Unknown title updates per title")
+		flags.IntVar(&options.UnknownTitles, "unknown-titles", 1, "Folders of titles missing from the database")
+		flags.Int64Var(&options.Seed, "seed", 1, "Seed picking the titles and IDs, the same seed writes the same dump")
+		flags.Parse(args[1:])
+		if flags.NArg() != 1 {
+			exitWithError(fmt.Errorf("Usage: pinecone devtool mockdump [options] <folder>"))
+		}
+
+		mock, err := a.writeMockDump(flags.Arg(0), options)
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("Mock dump written to %s: %d titles, %d known content, %d unknown content, %d unknown updates, %d unknown titles, %d wanted saves\n",
+			flags.Arg(0), mock.Titles, mock.KnownDLC, mock.UnknownDLC, mock.UnknownUpdates, mock.UnknownTitles, mock.WantedSaves)
+	case "quickhash":
+		for _, name := range args[1:] {
+			fsys := dumpDirFS(filepath.Dir(name))
+			quick, err := quickHash(fsys, filepath.Base(name))
+			if err != nil {
+				exitWithError(fmt.Errorf("Error hashing %s: %v", name, err))
+			}
+			if quick == "" {
+				fmt.Printf("%s: smaller than %s, always hashed in full\n", name, formatSize(quickHashMinSize))
+				continue
+			}
+			fileHash, err := hashFileFS(fsys, filepath.Base(name))
+			if err != nil {
+				exitWithError(fmt.Errorf("Error hashing %s: %v", name, err))
+			}
+			fmt.Printf("\"%s\": \"%s\",\n", quick, fileHash)
+		}
+	default:
+		exitWithError(fmt.Errorf("Unknown devtool %q, see -help for usage", args[0]))
+	}
+}
+
+// writeMockDump writes a synthetic dump to dir: a TDATA folder with the
+// content of titles picked from the loaded database plus unknown content and
+// updates, title folders missing from the database and the wanted saves of
+// the picked titles in UDATA. Known title updates can't be made up, their
+// hashes are of the real files.
+func (a *App) writeMockDump(dir string, options MockDumpOptions) (MockDump, error) {
+	var mock MockDump
+	if _, err := os.Stat(filepath.Join(dir, "TDATA")); err == nil {
+		return mock, fmt.Errorf("Error: %s already holds a dump", dir)
+	}
+	random := rand.New(rand.NewSource(options.Seed))
+
+	var candidates []string
+	for titleID, titleData := range a.Titles.Titles {
+		if len(titleData.ContentIDs) > 0 {
+			candidates = append(candidates, titleID)
+		}
+	}
+	sort.Strings(candidates)
+	random.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	if options.Titles < len(candidates) {
+		candidates = candidates[:options.Titles]
+	}
+	sort.Strings(candidates)
+
+	tdata := filepath.Join(dir, "TDATA")
+	for _, titleID := range candidates {
+		titleData := a.Titles.Titles[titleID]
+		mock.Titles++
+		for _, contentID := range titleData.ContentIDs {
+			if err := writeMockContent(filepath.Join(tdata, titleID, "$c", contentID), random); err != nil {
+				return mock, err
+			}
+			mock.KnownDLC++
+		}
+		for i := 0; i < options.UnknownDLC; i++ {
+			contentID := titleID + fmt.Sprintf("%08x", random.Uint32())
+			if contains(titleData.ContentIDs, contentID) {
+				continue
+			}
+			if err := writeMockContent(filepath.Join(tdata, titleID, "$c", contentID), random); err != nil {
+				return mock, err
+			}
+			mock.UnknownDLC++
+		}
+		for i := 0; i < options.UnknownUpdates; i++ {
+			name := "default.xbe"
+			if i > 0 {
+				name = fmt.Sprintf("update%d.xbe", i)
+			}
+			if err := writeMockXBE(filepath.Join(tdata, titleID, "$u", name), titleID, titleData.TitleName, random); err != nil {
+				return mock, err
+			}
+			mock.UnknownUpdates++
+		}
+
+		for _, wanted := range a.Titles.WantedSaves[titleID] {
+			if wanted.SHA1 != "" {
+				continue // a signature can't be made up either
+			}
+			saveDir := filepath.Join(dir, "UDATA", titleID, fmt.Sprintf("%012X", random.Int63()&0xffffffffffff))
+			if err := writeMockSave(saveDir, wanted.Name); err != nil {
+				return mock, err
+			}
+			mock.WantedSaves++
+		}
+	}
+
+	for mock.UnknownTitles < options.UnknownTitles {
+		// Publisher codes are two letters, "ZZ" isn't used by anyone
+		titleID := fmt.Sprintf("5a5a%04x", random.Intn(0x10000))
+		if _, ok := a.Titles.Titles[titleID]; ok {
+			continue
+		}
+		contentID := titleID + fmt.Sprintf("%08x", random.Uint32())
+		if err := writeMockContent(filepath.Join(tdata, titleID, "$c", contentID), random); err != nil {
+			return mock, err
+		}
+		mock.UnknownTitles++
+	}
+
+	if err := os.MkdirAll(tdata, 0o755); err != nil {
+		return mock, fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	return mock, nil
+}
+
+// writeMockContent writes a content folder: a ContentMeta.xbx with its XCNT
+// header and a content file.
+func writeMockContent(contentDir string, random *rand.Rand) error {
+	if err := os.MkdirAll(contentDir, 0o755); err != nil {
+		return fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	meta := make([]byte, 0x200)
+	copy(meta[contentMetaMagicOffset:], contentMetaMagic)
+	if err := os.WriteFile(filepath.Join(contentDir, "ContentMeta.xbx"), meta, 0o644); err != nil {
+		return fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	if err := os.Chtimes(filepath.Join(contentDir, "ContentMeta.xbx"), mockModified, mockModified); err != nil {
+		return fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	data := make([]byte, mockFileSize)
+	random.Read(data)
+	if err := os.WriteFile(filepath.Join(contentDir, "content.dat"), data, 0o644); err != nil {
+		return fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	return nil
+}
+
+// writeMockXBE writes an unsigned XBE whose certificate is for titleID, the
+// rest is random so every update has its own hash.
+func writeMockXBE(filePath string, titleID string, titleName string, random *rand.Rand) error {
+	const baseAddress, headerSize, certOffset = 0x10000, 0x1000, 0x178
+
+	data := make([]byte, mockUpdateSize)
+	random.Read(data[headerSize:])
+	copy(data, xbeMagic)
+	binary.LittleEndian.PutUint32(data[0x104:], baseAddress)
+	binary.LittleEndian.PutUint32(data[0x108:], headerSize)
+	binary.LittleEndian.PutUint32(data[0x118:], baseAddress+certOffset)
+
+	cert := data[certOffset:]
+	var id uint32
+	fmt.Sscanf(titleID, "%08x", &id)
+	binary.LittleEndian.PutUint32(cert[0x08:], id)
+	for i, unit := range utf16.Encode([]rune(titleName)) {
+		if i == xbeCertTitleLen {
+			break
+		}
+		binary.LittleEndian.PutUint16(cert[xbeCertTitleName+i*2:], unit)
+	}
+	binary.LittleEndian.PutUint32(cert[xbeCertRegion:], 0x1)
+	binary.LittleEndian.PutUint32(cert[xbeCertVersion:], uint32(random.Intn(16)+1))
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	if err := os.Chtimes(filePath, mockModified, mockModified); err != nil {
+		return fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	return nil
+}
+
+// writeMockSave writes a save folder with a SaveMeta.xbx naming it.
+func writeMockSave(saveDir string, name string) error {
+	if err := os.MkdirAll(saveDir, 0o755); err != nil {
+		return fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	meta := []byte{0xff, 0xfe}
+	for _, unit := range utf16.Encode([]rune("Name=" + name + "\r\n")) {
+		meta = binary.LittleEndian.AppendUint16(meta, unit)
+	}
+	if err := os.WriteFile(filepath.Join(saveDir, "SaveMeta.xbx"), meta, 0o644); err != nil {
+		return fmt.Errorf("Error writing mock dump: %v", err)
+	}
+	return nil
+}
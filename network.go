@@ -0,0 +1,83 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	httpTimeout    = 30 * time.Second
+	httpRetries    = 3
+	httpRetryDelay = time.Second // doubled after every attempt
+)
+
+// newHTTPClient builds the client used for all downloads. The proxy comes
+// from the settings, falling back to HTTP_PROXY/HTTPS_PROXY/NO_PROXY, and an
+// extra CA certificate can be trusted for networks that intercept TLS.
+func newHTTPClient(settings *Settings) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if settings.Proxy != "" {
+		proxyURL, err := url.Parse(settings.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing proxy URL: %v", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if settings.CACertFile != "" {
+		pem, err := os.ReadFile(settings.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading CA certificate: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", settings.CACertFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Timeout: httpTimeout, Transport: transport}, nil
+}
+
+// httpDo sends a request, retrying with exponential backoff on network errors
+// and server side failures. Only use it for requests without a body.
+func (a *App) httpDo(req *http.Request) (*http.Response, error) {
+	settings, err := a.loadSettings()
+	if err != nil {
+		settings = &Settings{}
+	}
+	client, err := newHTTPClient(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req)
+		retry := err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		if !retry || attempt == httpRetries {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		time.Sleep(httpRetryDelay << attempt)
+	}
+}
+
+// httpGet is http.Get going through httpDo.
+func (a *App) httpGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return a.httpDo(req)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// notifyAfter is how long a scan must take before the GUI sends a system
+// notification when it finishes, shorter scans are watched anyway.
+const notifyAfter = 30 * time.Second
+
+// notifyScanFinished sends a system notification that a long GUI scan
+// finished and whether anything worth submitting was found, so the user can
+// switch away during multi-hour scans of large drives.
+func (a *App) notifyScanFinished(elapsed time.Duration, scanErr error) {
+	if !a.Config.GUI || isBackgroundScan() || elapsed < notifyAfter {
+		return
+	}
+	if settings, err := a.loadSettings(); err == nil && settings.HideNotifications {
+		return
+	}
+	current := fyne.CurrentApp()
+	if current == nil {
+		return
+	}
+	current.SendNotification(scanNotification(&a.Report, elapsed, scanErr))
+}
+
+func scanNotification(report *Report, elapsed time.Duration, scanErr error) *fyne.Notification {
+	elapsed = elapsed.Round(time.Second)
+	if scanErr != nil {
+		return fyne.NewNotification("Pinecone scan failed", fmt.Sprintf("After %s: %v", elapsed, scanErr))
+	}
+	unknown, unarchived := report.Count(statusUnknown), report.Count(statusUnarchived)
+	if unknown == 0 && unarchived == 0 && len(report.UnknownTitles) == 0 {
+		return fyne.NewNotification("Pinecone scan finished", fmt.Sprintf("Nothing new found in %s, everything is already archived.", elapsed))
+	}
+	return fyne.NewNotification("Pinecone found new content", fmt.Sprintf("%d unknown, %d unarchived and %d titles missing from the database, scanned in %s.",
+		unknown, unarchived, len(report.UnknownTitles), elapsed))
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Offering is a marketplace listing from the known offerings dataset.
+type Offering struct {
+	Name    string   `json:"name"`
+	Regions []string `json:"regions,omitempty"`
+	Source  string   `json:"source,omitempty"` // where the listing was scraped from
+}
+
+func (a *App) defaultOfferingsPath() string {
+	return filepath.Join(a.Config.DataPath, "known_offerings.json")
+}
+
+// loadOfferings loads the known offerings dataset: a JSON object of content
+// IDs to listings. The dataset is optional, a missing default file isn't an
+// error.
+func (a *App) loadOfferings() error {
+	a.Offerings = nil
+	path := a.Config.Offerings
+	if path == "" {
+		path = a.defaultOfferingsPath()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Error reading known offerings: %v", err)
+	}
+	var offerings map[string]Offering
+	if err := json.Unmarshal([]byte(removeCommentsFromJSON(string(data))), &offerings); err != nil {
+		return fmt.Errorf("Error parsing known offerings %s: %v", path, err)
+	}
+
+	a.Offerings = make(map[string]Offering, len(offerings))
+	for contentID, offering := range offerings {
+		a.Offerings[strings.ToLower(contentID)] = offering
+	}
+	return nil
+}
+
+// describeListing cross-references a DLC finding with the known offerings,
+// e.g. `matches marketplace offering "Killtacular Pack" (NTSC-U), never
+// archived`. Empty if the content isn't listed.
+func (a *App) describeListing(f Finding) string {
+	offering, ok := a.Offerings[f.ContentID]
+	if !ok {
+		return ""
+	}
+	description := fmt.Sprintf("matches marketplace offering %q", offering.Name)
+	if len(offering.Regions) > 0 {
+		description += " (" + strings.Join(offering.Regions, ", ") + ")"
+	}
+	if f.Status != statusArchived {
+		description += ", never archived"
+	}
+	return description
+}
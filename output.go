@@ -0,0 +1,372 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// thumbnailHeight is the height of thumbnail rows in the scan output.
+const thumbnailHeight = 96
+
+// defaultOutputLineLimit is how many lines the GUI scan output keeps unless
+// "outputLineLimit" is set, older lines are dropped from the window but stay
+// in the scan log.
+const defaultOutputLineLimit = 20000
+
+// outputLine is a line of the GUI scan output. Title headers have a TitleID
+// and show the title's details when tapped, thumbnail rows have the path of
+// a cached image instead of text.
+type outputLine struct {
+	Text      string
+	Color     color.Color
+	TitleID   string
+	Thumbnail string
+}
+
+// The scan output is kept as plain lines shown by a virtualized list, only
+// the rows on screen are rendered, so scans with tens of thousands of lines
+// stay responsive.
+var (
+	outputMu    sync.Mutex
+	outputLines []outputLine
+	outputList  *widget.List
+	// thumbnailRows are the rows resized for thumbnails, the list keeps row
+	// heights by index so they are reset for the next scan.
+	thumbnailRows []int
+	// outputScan counts the scans, thumbnails downloaded for an older scan
+	// are dropped.
+	outputScan int
+	// outputLineLimit caps outputLines, set from the settings, negative
+	// keeps every line. outputDropped counts the lines dropped since the
+	// scan started, the IDs returned by addOutput count them too.
+	outputLineLimit = defaultOutputLineLimit
+	outputDropped   int
+	// outputLog streams every line of the current scan, and of the log
+	// pane, to its scan log in the output folder as it comes, whether or
+	// not the window still holds it, so a crash mid-scan loses nothing.
+	// outputLogPath is its path.
+	outputLog     *os.File
+	outputLogPath string
+)
+
+// scanLogPrefix marks the lines of the log pane in the scan log, which is
+// streamed there between the output lines.
+const scanLogPrefix = "Log: "
+
+// keptScanLogs is how many scan logs are kept in the output folder, the
+// oldest are removed when a scan starts.
+const keptScanLogs = 20
+
+func resolveOutputLineLimit(settings *Settings) int {
+	if settings.OutputLineLimit == 0 {
+		return defaultOutputLineLimit
+	}
+	return settings.OutputLineLimit
+}
+
+// scanLogPath is the scan log of a scan started now, e.g.
+// output/scan-2024-05-01-20-15-00.log.
+func (a *App) scanLogPath() string {
+	return filepath.Join(a.Config.DataPath, "output", "scan-"+time.Now().Format("2006-01-02-15-04-05")+".log")
+}
+
+// pruneScanLogs removes all but the newest scan logs. Their names sort by
+// date.
+func (a *App) pruneScanLogs(keep int) {
+	logs, err := filepath.Glob(filepath.Join(a.Config.DataPath, "output", "scan-*.log"))
+	if err != nil || len(logs) <= keep {
+		return
+	}
+	sort.Strings(logs)
+	for _, name := range logs[:len(logs)-keep] {
+		os.Remove(name)
+	}
+}
+
+// addOutput appends a line to the scan output and returns its ID. Past the
+// line limit the oldest tenth of the lines is dropped.
+func (a *App) addOutput(line outputLine) int {
+	outputMu.Lock()
+	a.writeOutputLog(line)
+	outputLines = append(outputLines, line)
+	id := outputDropped + len(outputLines) - 1
+	var rows []int
+	drop := 0
+	if outputLineLimit > 0 && len(outputLines) > outputLineLimit {
+		drop = len(outputLines) - outputLineLimit*9/10
+		outputLines = append([]outputLine(nil), outputLines[drop:]...)
+		outputDropped += drop
+		rows = thumbnailRows
+	}
+	outputMu.Unlock()
+	if drop > 0 {
+		shiftThumbnailRows(rows, drop)
+	}
+	if outputList != nil {
+		outputList.Refresh()
+	}
+	return id
+}
+
+// writeOutputLog appends a text line to the scan log, opening it for the
+// first line of a scan. Called with outputMu held.
+func (a *App) writeOutputLog(line outputLine) {
+	if line.Text == "" || !a.openOutputLog() {
+		return
+	}
+	fmt.Fprintln(outputLog, line.Text)
+	// Title headers come every few lines at most, syncing on them keeps the
+	// log on disk even if the system goes down
+	if line.TitleID != "" {
+		outputLog.Sync()
+	}
+}
+
+// writeScanLogLine appends a line of the log pane to the scan log. Warnings
+// and errors are few, each is synced.
+func (a *App) writeScanLogLine(text string) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	if !a.openOutputLog() {
+		return
+	}
+	fmt.Fprintln(outputLog, scanLogPrefix+text)
+	outputLog.Sync()
+}
+
+// openOutputLog opens the scan log for the first line of a scan, returning
+// whether it is open. Called with outputMu held.
+func (a *App) openOutputLog() bool {
+	if outputLog != nil {
+		return true
+	}
+	logPath := a.scanLogPath()
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return false
+	}
+	a.pruneScanLogs(keptScanLogs - 1)
+	file, err := os.Create(logPath)
+	if err != nil {
+		return false
+	}
+	outputLog, outputLogPath = file, logPath
+	return true
+}
+
+// scanLogLines reads back the current scan log, the output lines or the log
+// pane's ones without their prefix. Called with outputMu held.
+func scanLogLines(logPane bool) ([]string, bool) {
+	if outputLog == nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(outputLogPath)
+	if err != nil {
+		return nil, false
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if text, found := strings.CutPrefix(line, scanLogPrefix); found == logPane {
+			lines = append(lines, text)
+		}
+	}
+	return lines, true
+}
+
+// closeOutputLog closes the scan log. Called with outputMu held.
+func closeOutputLog() {
+	if outputLog == nil {
+		return
+	}
+	outputLog.Close()
+	outputLog = nil
+}
+
+// shiftThumbnailRows moves the row heights of thumbnails up by the dropped
+// lines, the list keeps them by row index.
+func shiftThumbnailRows(rows []int, drop int) {
+	if outputList == nil {
+		return
+	}
+	rowHeight := outputList.CreateItem().MinSize().Height
+	outputMu.Lock()
+	dropped := outputDropped
+	var kept []int
+	var heights []float32
+	for _, id := range rows {
+		index := id - dropped
+		if index < 0 {
+			continue
+		}
+		kept = append(kept, id)
+		if outputLines[index].Thumbnail != "" {
+			heights = append(heights, thumbnailHeight)
+		} else {
+			heights = append(heights, 0)
+		}
+	}
+	thumbnailRows = kept
+	outputMu.Unlock()
+
+	for _, id := range rows {
+		if index := id - (dropped - drop); index >= 0 {
+			outputList.SetItemHeight(index, rowHeight)
+		}
+	}
+	for i, id := range kept {
+		outputList.SetItemHeight(id-dropped, heights[i])
+	}
+}
+
+// reserveThumbnailRow adds an empty row for a thumbnail still downloading,
+// it returns the row and scan to pass to setOutputThumbnail.
+func (a *App) reserveThumbnailRow() (int, int) {
+	id := a.addOutput(outputLine{})
+	resizeOutputRow(id, 0)
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	return id, outputScan
+}
+
+// setOutputThumbnail shows a downloaded thumbnail in its reserved row.
+func setOutputThumbnail(id int, scan int, thumbnail string) {
+	outputMu.Lock()
+	index := id - outputDropped
+	if scan != outputScan || index < 0 || index >= len(outputLines) {
+		outputMu.Unlock()
+		return
+	}
+	outputLines[index].Thumbnail = thumbnail
+	outputMu.Unlock()
+	resizeOutputRow(id, thumbnailHeight)
+}
+
+func resizeOutputRow(id int, height float32) {
+	if outputList == nil {
+		return
+	}
+	outputMu.Lock()
+	thumbnailRows = append(thumbnailRows, id)
+	index := id - outputDropped
+	outputMu.Unlock()
+	if index < 0 {
+		return
+	}
+	outputList.SetItemHeight(index, height)
+	outputList.Refresh()
+}
+
+// clearOutput empties the scan output for a new scan, which starts a new
+// scan log.
+func clearOutput() {
+	outputMu.Lock()
+	closeOutputLog()
+	outputLines = nil
+	outputScan++
+	rows := thumbnailRows
+	dropped := outputDropped
+	thumbnailRows = nil
+	outputDropped = 0
+	outputMu.Unlock()
+	if outputList != nil {
+		rowHeight := outputList.CreateItem().MinSize().Height
+		for _, id := range rows {
+			if id-dropped >= 0 {
+				outputList.SetItemHeight(id-dropped, rowHeight)
+			}
+		}
+		outputList.UnselectAll()
+		outputList.ScrollToTop()
+		outputList.Refresh()
+	}
+}
+
+// outputText returns the text lines of the scan output, for saving it. The
+// lines dropped from the window are read back from the scan log.
+func outputText() []string {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	if outputDropped > 0 {
+		if lines, ok := scanLogLines(false); ok {
+			return lines
+		}
+	}
+	var lines []string
+	for _, line := range outputLines {
+		if line.Thumbnail == "" && line.Text != "" {
+			lines = append(lines, line.Text)
+		}
+	}
+	return lines
+}
+
+func outputLineAt(id widget.ListItemID) (outputLine, bool) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	if id < 0 || id >= len(outputLines) {
+		return outputLine{}, false
+	}
+	return outputLines[id], true
+}
+
+// newOutputList creates the list showing the scan output.
+func (a *App) newOutputList() *widget.List {
+	outputList = widget.NewList(
+		func() int {
+			outputMu.Lock()
+			defer outputMu.Unlock()
+			return len(outputLines)
+		},
+		func() fyne.CanvasObject {
+			image := canvas.NewImageFromResource(nil)
+			image.FillMode = canvas.ImageFillContain
+			image.SetMinSize(fyne.NewSize(thumbnailHeight, thumbnailHeight))
+			image.Hide()
+			return container.NewStack(canvas.NewText("", theme.ForegroundColor()), container.NewHBox(image))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			line, ok := outputLineAt(id)
+			if !ok {
+				return
+			}
+			row := obj.(*fyne.Container)
+			text := row.Objects[0].(*canvas.Text)
+			image := row.Objects[1].(*fyne.Container).Objects[0].(*canvas.Image)
+			if line.Thumbnail != "" {
+				text.Hide()
+				image.File = line.Thumbnail
+				image.Show()
+				image.Refresh()
+				return
+			}
+			image.Hide()
+			text.Text = line.Text
+			text.Color = line.Color
+			text.TextStyle = fyne.TextStyle{Bold: line.TitleID != ""}
+			text.Show()
+			text.Refresh()
+		},
+	)
+	outputList.OnSelected = func(id widget.ListItemID) {
+		if line, ok := outputLineAt(id); ok && line.TitleID != "" {
+			a.showDetailsPane(line.TitleID)
+		}
+		outputList.Unselect(id)
+	}
+	return outputList
+}
+
+func (a *App) addText(textColor color.Color, format string, args ...interface{}) {
+	a.addOutput(outputLine{Text: fmt.Sprintf(format, args...), Color: textColor})
+}
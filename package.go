@@ -0,0 +1,126 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// packageSubmission zips the files of the given findings as found on the
+// drive, the content ID folder of DLC and the file of everything else under
+// their path in TDATA, with a Markdown report of them, ready to hand to the
+// maintainers. Returns the path of the zip in the output folder.
+func (a *App) packageSubmission(findings []Finding, settings *Settings) (string, error) {
+	zipPath := filepath.Join(a.Config.DataPath, "output", "submission-"+time.Now().Format("20060102-150405")+".zip")
+	if err := os.MkdirAll(filepath.Dir(zipPath), 0o755); err != nil {
+		return "", fmt.Errorf("Error creating submission: %v", err)
+	}
+	file, err := os.Create(zipPath)
+	if err != nil {
+		return "", fmt.Errorf("Error creating submission: %v", err)
+	}
+	defer file.Close()
+	archive := zip.NewWriter(file)
+
+	for _, f := range findings {
+		if err := addFindingToZip(archive, f); err != nil {
+			archive.Close()
+			return "", fmt.Errorf("Error packaging %s: %v", f.Path, err)
+		}
+	}
+
+	report, err := archive.Create("report.md")
+	if err != nil {
+		return "", fmt.Errorf("Error creating submission: %v", err)
+	}
+	if err := a.writeFindingsReport(report, findings, settings); err != nil {
+		return "", fmt.Errorf("Error creating submission: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		return "", fmt.Errorf("Error creating submission: %v", err)
+	}
+	return zipPath, nil
+}
+
+// writeFindingsReport writes a Markdown report of some findings of the last
+// scan.
+func (a *App) writeFindingsReport(w io.Writer, findings []Finding, settings *Settings) error {
+	report := a.Report
+	report.Findings = findings
+	report.UnknownTitles, report.SystemTitles = nil, nil
+	return a.writeMarkdownReport(w, &report, settings)
+}
+
+// addFindingToZip adds the file or folder of a finding, stored under its
+// path relative to the folder holding TDATA.
+func addFindingToZip(archive *zip.Writer, f Finding) error {
+	return walkFindingFiles(f, func(name string, info fs.FileInfo, src io.Reader) error {
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		header.Method = zip.Deflate
+		dst, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, src)
+		return err
+	})
+}
+
+// walkFindingFiles calls fn with every file of a finding as found in its
+// dump, the content ID folder of DLC and the file of everything else, named
+// by their path relative to the folder holding TDATA.
+func walkFindingFiles(f Finding, fn func(name string, info fs.FileInfo, src io.Reader) error) error {
+	fsys, tdata, closeDump, err := openDump(f.Location)
+	if err != nil {
+		return err
+	}
+	defer closeDump()
+
+	name, err := findingDumpPath(f, tdata)
+	if err != nil {
+		return err
+	}
+	root := path.Dir(tdata)
+	return walkDump(fsys, name, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		src, err := fsys.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		return fn(strings.TrimPrefix(filePath, root+"/"), info, src)
+	})
+}
+
+// findingDumpPath turns the path a finding is reported with back into its
+// path in the dump: displayed paths of the location or paths relative to
+// TDATA, see displayPath and relativePath.
+func findingDumpPath(f Finding, tdata string) (string, error) {
+	if isArchive(f.Location) {
+		if name, ok := strings.CutPrefix(f.Path, f.Location+":"); ok {
+			return name, nil
+		}
+	} else if rel, err := filepath.Rel(f.Location, f.Path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(rel), nil
+	}
+	if filepath.IsAbs(f.Path) {
+		return "", fmt.Errorf("not in the dump %s", f.Location)
+	}
+	return path.Join(tdata, filepath.ToSlash(f.Path)), nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+var windowsDrivePath = regexp.MustCompile(`^/[A-Za-z]:`)
+
+// pickFolder asks for a folder, with the desktop's own picker where Fyne's
+// is clunky: zenity or kdialog on Linux and BSD desktops, Fyne's folder
+// dialog otherwise or if neither is installed. done is called with the
+// chosen path, not at all if the user cancelled.
+func (a *App) pickFolder(window fyne.Window, title string, done func(string)) {
+	if picker := a.nativeFolderPicker(title); picker != nil {
+		go func() {
+			output, err := picker.Output()
+			var exitErr *exec.ExitError
+			switch {
+			case err == nil:
+				if folder := strings.TrimRight(string(output), "\r\n"); folder != "" {
+					done(folder)
+				}
+			case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+				// cancelled
+			default:
+				showFyneFolderPicker(window, done)
+			}
+		}()
+		return
+	}
+	showFyneFolderPicker(window, done)
+}
+
+// nativeFolderPicker returns the command showing the desktop's folder picker,
+// kdialog on KDE and zenity elsewhere, nil if there is none.
+func (a *App) nativeFolderPicker(title string) *exec.Cmd {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return nil
+	}
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return nil
+	}
+	start, _ := os.UserHomeDir()
+	if a.Config.DumpLocation != "" {
+		if abs, err := filepath.Abs(a.Config.DumpLocation); err == nil {
+			start = abs
+		}
+	}
+
+	kdialog, kdialogErr := exec.LookPath("kdialog")
+	zenity, zenityErr := exec.LookPath("zenity")
+	kde := strings.Contains(strings.ToUpper(os.Getenv("XDG_CURRENT_DESKTOP")), "KDE")
+	switch {
+	case kdialogErr == nil && (kde || zenityErr != nil):
+		return exec.Command(kdialog, "--title", title, "--getexistingdirectory", start)
+	case zenityErr == nil:
+		return exec.Command(zenity, "--file-selection", "--directory", "--title="+title, "--filename="+start+string(filepath.Separator))
+	}
+	return nil
+}
+
+func showFyneFolderPicker(window fyne.Window, done func(string)) {
+	dialog.ShowFolderOpen(func(list fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if list == nil { // user cancelled
+			return
+		}
+		done(uriToPath(list))
+	}, window)
+}
+
+// uriToPath converts a file:// URI from a Fyne dialog to a local path,
+// undoing percent encoding (spaces as %20) and the leading slash of Windows
+// drive paths (file:///C:/Dumps).
+func uriToPath(u fyne.URI) string {
+	p := u.Path()
+	if u.Scheme() != "file" || p == "" {
+		p = strings.TrimPrefix(u.String(), "file://")
+	}
+	if _, err := os.Stat(p); err != nil {
+		if unescaped, err := url.PathUnescape(p); err == nil {
+			p = unescaped
+		}
+	}
+	if windowsDrivePath.MatchString(p) {
+		p = p[1:]
+	}
+	return filepath.FromSlash(p)
+}
@@ -3,64 +3,236 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
 var (
-	titles        TitleList
-	updateFlag    = false
-	summarizeFlag = false
-	titleIDFlag   = ""
-	fatxplorer    = false
-	dumpLocation  = "dump"
-	helpFlag      = false
-	version       = "0.6.0"
-	guiEnabled    = true
-	dataPath      = "data"
+	version   = "0.6.0"
+	quietMode = false
+	noColor   = false
 )
 
+// stringList collects every value of a flag that can be repeated, e.g.
+// -location.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
 func main() {
-	flag.BoolVar(&updateFlag, "update", false, "Update the JSON data from the source URL")
-	flag.BoolVar(&updateFlag, "u", false, "Update the JSON data from the source URL")
-	flag.BoolVar(&summarizeFlag, "summarize", false, "Print summary statistics for all titles")
-	flag.BoolVar(&summarizeFlag, "s", false, "Print summary statistics for all titles")
-	flag.StringVar(&titleIDFlag, "titleid", "", "Filter statistics by Title ID")
-	flag.StringVar(&titleIDFlag, "tID", "", "Filter statistics by Title ID")
-	flag.BoolVar(&fatxplorer, "fatxplorer", false, "Use FatXplorer's X: drive")
-	flag.BoolVar(&fatxplorer, "f", false, "Use FatXplorer's X: drive")
-	flag.StringVar(&dumpLocation, "location", "dump", "Directory to search for TDATA/UDATA directories")
-	flag.StringVar(&dumpLocation, "l", "dump", "Directory to search for TDATA/UDATA directories")
-	flag.BoolVar(&helpFlag, "help", false, "Display help information")
-	flag.BoolVar(&helpFlag, "h", false, "Display help information")
-	flag.BoolVar(&guiEnabled, "gui", true, "Enable GUI")
-	flag.BoolVar(&guiEnabled, "g", true, "Enable GUI")
+	a := &App{Config: defaultConfig()}
+	help := false
+	flag.BoolVar(&a.Config.Update, "update", false, "Update the JSON data from the source URL")
+	flag.BoolVar(&a.Config.Update, "u", false, "Update the JSON data from the source URL")
+	flag.BoolVar(&a.Config.ForceUpdate, "force-update", false, "Update the JSON data even if it was checked recently")
+	flag.BoolVar(&a.Config.SignedUpdate, "signed-update", false, "Update the JSON data from the signed GitHub release, verifying its minisign signature")
+	flag.BoolVar(&a.Config.Summarize, "summarize", false, "Print summary statistics for all titles")
+	flag.BoolVar(&a.Config.Summarize, "s", false, "Print summary statistics for all titles")
+	flag.StringVar(&a.Config.TitleID, "titleid", "", "Print statistics for a Title ID and only scan its folders")
+	flag.StringVar(&a.Config.TitleID, "tID", "", "Print statistics for a Title ID and only scan its folders")
+	flag.BoolVar(&a.Config.FatXplorer, "fatxplorer", false, "Use FatXplorer's X: drive")
+	flag.BoolVar(&a.Config.FatXplorer, "f", false, "Use FatXplorer's X: drive")
+	flag.Var(&a.Config.DumpLocations, "location", "Directory (or .zip archive) to search for TDATA/UDATA directories, repeatable (default \"dump\")")
+	flag.Var(&a.Config.DumpLocations, "l", "Directory (or .zip archive) to search for TDATA/UDATA directories, repeatable (default \"dump\")")
+	flag.StringVar(&symlinkMode, "symlinks", symlinksFollow, "How to handle symlinks and junctions in a dump: follow or skip")
+	flag.IntVar(&tdataSearchDepth, "tdata-depth", tdataSearchDepth, "How many folders deep to search for a TDATA folder nested in the dump")
+	flag.IntVar(&hashBlockSize, "block-size", defaultHashBlockSize/1024, "Read size in KiB used when hashing files")
+	flag.IntVar(&a.Config.Jobs, "jobs", a.Config.Jobs, "How many title folders to check at once, 1 scans sequentially")
+	flag.BoolVar(&useMmap, "mmap", false, "Hash files through memory mapping where supported")
+	flag.Var(&a.Config.OnlyTitles, "only-title", "Only scan titles whose ID or name matches, wildcards allowed, repeatable")
+	flag.Var(&a.Config.ExcludeTitles, "exclude-title", "Skip titles whose ID or name matches, wildcards allowed, repeatable")
+	flag.StringVar(&a.Config.Language, "lang", "", "Language code to show localized title names in, e.g. ja (default from the settings or system locale)")
+	flag.StringVar(&a.Config.EEPROMPath, "eeprom", "", "EEPROM backup of the console the dump came from (default eeprom.bin found in the dump)")
+	flag.StringVar(&a.Config.Detectors, "detectors", "", "Comma separated content categories to scan for: eeprom, dlc, updates, dashboard, saves, homebrew, devkit, soundtracks or all")
+	flag.BoolVar(&a.Config.ListSaves, "saves", false, "List every save found in UDATA with its name, not only wanted saves")
+	flag.BoolVar(&a.Config.Anonymize, "anonymize", false, "Strip local paths, console identifiers and save names from reports")
+	flag.BoolVar(&a.Config.Thumbnails, "thumbnails", false, "Download and cache the thumbnails of DLC found")
+	flag.StringVar(&a.Config.XBEKey, "xbekey", "", "Microsoft's retail XBE public key (RSA1 blob) to verify update signatures with (default data/xbe_public_key.bin)")
+	flag.StringVar(&a.Config.CommunityTitles, "community-titles", "", "Community title ID dataset to name titles missing from the database (default data/community_titles.json)")
+	flag.BoolVar(&a.Config.LookupTitles, "lookup", false, "Look up titles missing from the database at titleLookupURL from the settings")
+	flag.StringVar(&a.Config.Offerings, "offerings", "", "Known marketplace offerings dataset to cross-reference DLC with (default data/known_offerings.json)")
+	flag.StringVar(&a.Config.DataPath, "data", "", "Folder for the database, settings and reports (overrides PINECONE_DATA and the settings)")
+	flag.BoolVar(&a.Config.Portable, "portable", false, "Keep the database, settings and reports next to the executable")
+	flag.BoolVar(&help, "help", false, "Display help information")
+	flag.BoolVar(&help, "h", false, "Display help information")
+	flag.BoolVar(&a.Config.GUI, "gui", true, "Enable GUI")
+	flag.BoolVar(&a.Config.GUI, "g", true, "Enable GUI")
+	flag.BoolVar(&a.Config.Tray, "tray", false, "Run the GUI minimized to the system tray and watch the dump folder")
+	flag.BoolVar(&a.Config.TUI, "tui", false, "Scan in an interactive terminal UI instead of printing the results")
+	flag.BoolVar(&a.Config.HashCache, "hash-cache", false, "Remember the hashes of scanned files so rescanning an unchanged dump is nearly instant")
+	flag.BoolVar(&a.Config.QuickHash, "quick-hash", false, "Check large files against the database's quick hashes before hashing them in full")
+	flag.StringVar(&a.Config.CollectDir, "collect", "", "Copy the unknown and unarchived files to the given folder after scanning")
+	flag.BoolVar(&a.Config.Triage, "triage", false, "Step through the unknown items after scanning, deciding what to do with each")
+	flag.StringVar(&a.Config.HTMLReport, "html", "", "Export an HTML report to the given file after scanning")
+	flag.StringVar(&a.Config.MarkdownReport, "markdown", "", "Export a Markdown report to the given file after scanning")
+	flag.StringVar(&a.Config.MarkdownReport, "md", "", "Export a Markdown report to the given file after scanning")
+	flag.StringVar(&a.Config.WebhookURL, "webhook", "", "URL to post the results to after scanning (default PINECONE_WEBHOOK)")
+	flag.StringVar(&a.Config.WebhookOnly, "webhook-only", "", "Comma separated statuses posted to the webhook (default unknown,unarchived)")
+	flag.StringVar(&a.Config.ReportTemplate, "template", "", "Go text/template file used instead of the built-in Markdown report")
+	flag.BoolVar(&quietMode, "quiet", false, "Only print unknown/unarchived content and errors")
+	flag.BoolVar(&quietMode, "q", false, "Only print unknown/unarchived content and errors")
+	flag.BoolVar(&noColor, "no-color", false, "Print without colors")
+	flag.BoolVar(&a.Config.CompileIndex, "compile-index", false, "Compile the database into a binary index for faster loading and matching")
 
 	flag.Parse() // Parse command line flags
+	setupColor()
+
+	if len(a.Config.DumpLocations) > 0 {
+		a.Config.DumpLocation = a.Config.DumpLocations[0]
+	}
+	for _, location := range a.scanLocations() {
+		if location == stdinLocation {
+			// A file list on stdin comes from a pipeline, not the GUI
+			a.Config.GUI = false
+		}
+	}
+	if a.Config.ForceUpdate || a.Config.SignedUpdate {
+		a.Config.Update = true
+	}
+	if err := checkSymlinkMode(symlinkMode); err != nil {
+		fmt.Println(err)
+		os.Exit(exitError)
+	}
+	if a.Config.WebhookOnly != "" {
+		if _, err := parseWebhookStatuses(strings.Split(a.Config.WebhookOnly, ",")); err != nil {
+			fmt.Println(err)
+			os.Exit(exitError)
+		}
+	}
+	if err := a.checkTitleFilters(); err != nil {
+		fmt.Println(err)
+		os.Exit(exitError)
+	}
+	if a.Config.ReportTemplate != "" {
+		if _, err := a.loadReportTemplate(a.Config.ReportTemplate); err != nil {
+			fmt.Println(err)
+			os.Exit(exitError)
+		}
+	}
+	if hashBlockSize <= 0 {
+		fmt.Println("-block-size must be a positive number of KiB")
+		os.Exit(exitError)
+	}
+	hashBlockSize *= 1024
 
 	// Check for help flag
-	if helpFlag {
+	if help {
 		fmt.Println("Usage of Pinecone:")
 		fmt.Println("  -u, --update:     Update the JSON data from the source URL. If not set, uses local copies of data.")
+		fmt.Println("  --force-update:   Update even if the database was checked within the last few hours (see updateCheckHours in the settings).")
+		fmt.Println("  --signed-update:  Update from the database's GitHub release instead, verified with its minisign signature before")
+		fmt.Println("                    use. The local database is then verified on every load too (signedDatabase in the settings).")
 		fmt.Println("  -s, --summarize:  Print summary statistics for all titles. If not set, checks for content in the TDATA folder.")
-		fmt.Println("  -tID, --titleid:  Filter statistics by Title ID (-titleID=ABCD1234). If not set, statistics are computed for all titles.")
+		fmt.Println("  -tID, --titleid:  Print statistics for a Title ID (-titleID=ABCD1234), then scan only its TDATA and UDATA folders.")
 		fmt.Println("  -f, --fatxplorer: Use FATXPlorer's X drive as the root directory. If not set, runs as normal. (Windows Only)")
-		fmt.Println("  -l --location:    Directory where TDATA/UDATA folders are stored. If not set, checks in \"dump\"")
+		fmt.Println("  -l --location:    Directory (or .zip archive) where TDATA/UDATA folders are stored. If not set, checks in \"dump\"")
+		fmt.Println("                    -l=- reads a list of files to check from stdin instead (find E -type f | pinecone -l=-).")
+		fmt.Println("                    Repeat it to scan several partitions into one report (-l=E -l=F).")
+		fmt.Println("  --only-title:     Only scan titles whose ID or name matches, e.g. --only-title=4d53* --only-title=\"halo*\". Repeatable.")
+		fmt.Println("  --exclude-title:  Skip titles whose ID or name matches, same patterns as --only-title. Repeatable.")
+		fmt.Println("  --lang:           Show title names in this language when the database has them (-lang=ja), default from the")
+		fmt.Println("                    language setting or the system locale.")
+		fmt.Println("  --eeprom:         eeprom.bin of the console the dump came from, otherwise one found in the dump is used. Its serial,")
+		fmt.Println("                    video standard and (with eepromKey in the settings) region and HDD key are shown.")
+		fmt.Println("  --detectors:      Only scan for these categories, comma separated (-detectors=dlc,updates): eeprom, dlc,")
+		fmt.Println("                    updates, dashboard, saves, homebrew, devkit and soundtracks, or all. Homebrew and soundtracks")
+		fmt.Println("                    are only scanned for when listed.")
+		fmt.Println("  --saves:          List every save in UDATA with its SaveMeta.xbx name. Wanted saves are always reported.")
+		fmt.Println("  --anonymize:      Strip local paths, the console serial/MAC/HDD key and save names (gamertags) from reports.")
+		fmt.Println("  --thumbnails:     Download the thumbnails of DLC found from the project's image store, cached in data/thumbnails.")
+		fmt.Println("  --xbekey:         Retail XBE public key (the kernel's 284 byte RSA1 blob) to verify update signatures (default data/xbe_public_key.bin if present).")
+		fmt.Println("  --community-titles: Community title ID to name JSON for titles missing from the database (default data/community_titles.json if present).")
+		fmt.Println("  --lookup:         Look up titles missing from the database online, at titleLookupURL from the settings.")
+		fmt.Println("  --offerings:      Known marketplace offerings JSON to cross-reference DLC with (default data/known_offerings.json if present).")
+		fmt.Println("  --symlinks:       follow (default) or skip symlinks/junctions in the dump.")
+		fmt.Println("  --tdata-depth:    How many folders deep to search for TDATA when it isn't at the root of the dump (default 3, 0 for the root only).")
+		fmt.Println("  --block-size:     Read size in KiB used when hashing files (default 1024). Larger reads help on USB and network mounts.")
+		fmt.Println("  --jobs:           How many title folders to check at once (default 4). Use 1 for spinning drives.")
+		fmt.Println("  --mmap:           Hash files through memory mapping where the platform supports it.")
+		fmt.Println("  --hash-cache:     Remember the SHA1 of scanned files by path, size and date, so unchanged files aren't")
+		fmt.Println("                    hashed again by the next scan. Also \"hashCache\" in the settings.")
+		fmt.Println("  --quick-hash:     Hash only the start and end of large files first, known ones aren't read in full. Needs a")
+		fmt.Println("                    database with \"Quick Hashes\". With -l=-, unmatched listed files aren't hashed at all.")
 		fmt.Println("  -g, --gui:        Enable the GUI interface (default = true)")
+		fmt.Println("  --tray:           Run the GUI minimized to the system tray, watching the dump folder for new content.")
+		fmt.Println("  --tui:            Scan in a full screen terminal UI with live progress and scrollable results per title.")
+		fmt.Println("  --collect=DIR:    Copy the unknown and unarchived files to a staging folder, keeping their paths, to review and upload them.")
+		fmt.Println("  --triage:         After scanning, mark each unknown item to submit, ignore or as homebrew, then package and ignore them in bulk.")
+		fmt.Println("                    Keys: arrows or j/k scroll, PgUp/PgDn page, g/G top/bottom, u only unknown content, q quit.")
+		fmt.Println("  --html:           Export an HTML report to the given file after scanning (-html=report.html).")
+		fmt.Println("  -md, --markdown:  Export a Markdown report for GitHub issues to the given file after scanning (-md=report.md).")
+		fmt.Println("  --webhook:        URL to post the results to after scanning, also set by PINECONE_WEBHOOK. Discord webhooks get")
+		fmt.Println("                    messages listing the findings per title, other URLs also the findings as JSON.")
+		fmt.Println("  --webhook-only:   Statuses posted to the webhook, unknown, unarchived, archived or bad")
+		fmt.Println("                    (--webhook-only=unknown). Defaults to unknown,unarchived or \"webhookStatuses\" in the settings.")
+		fmt.Println("  --template:       Go text/template file to render the Markdown report with instead of the built-in tables")
+		fmt.Println("                    (-md=submission.txt -template=our-format.tmpl). See the README for the fields.")
+		fmt.Println("  -q, --quiet:      Only print unknown/unarchived content and errors. Exit codes: 0 = nothing interesting,")
+		fmt.Println("                    2 = unknown/unarchived content found, 3 = errors.")
+		fmt.Println("  --no-color:       Print without colors, also set by NO_COLOR. Redirected output is never colored.")
+		fmt.Println("  --compile-index:  Compile the database into a binary index (id_database.idx) loaded instead of the JSON while")
+		fmt.Println("                    it matches. Also \"compileIndex\" in the settings.")
+		fmt.Println("  --portable:       Keep the database, settings and reports in a data folder next to the executable instead of")
+		fmt.Println("                    the user config folder. Existing data is moved over when switching modes.")
+		fmt.Println("  --data:           Folder for the database, settings and reports. Overrides PINECONE_DATA and the dataPath setting.")
 		fmt.Println("  -h, --help:       Display this help information.")
+		fmt.Println()
+		fmt.Println("Commands:")
+		fmt.Println("  search <name>:    Fuzzy search the database for a title name and show its archive status.")
+		fmt.Println("  audit:            Check the database for duplicated update hashes and unreferenced archived items.")
+		fmt.Println("  changes:          Show what the last database update changed: titles, content and update hashes added or renamed.")
+		fmt.Println("  export manifest:  Write a sha1sum style manifest of every file under TDATA/UDATA of the -l dump, to the given file")
+		fmt.Println("                    or data/output (pinecone -l=E export manifest E.sha1).")
+		fmt.Println("  verify <file>:    Re-hash the -l dump and list the files changed, missing or added since the manifest was made.")
+		fmt.Println("                    Exits with 2 when the dump doesn't match.")
+		fmt.Println("  compare <a> <b>:  List the TDATA files, by hash, present in one dump (folder or .zip) but not the other.")
+		fmt.Println("  devtool mockdump <folder>: Write a synthetic dump with known and unknown content from the database, for testing.")
+		fmt.Println("  devtool quickhash <file>...: Print the database's \"Quick Hashes\" entries of large files.")
+		fmt.Println("                    Options: -titles, -unknown-dlc, -unknown-updates, -unknown-titles, -seed.")
 		return
 	}
 
-	jsonFilePath := "data/id_database.json"
-	jsonDataFolder := "data"
+	a.Config.DataPath = a.resolveDataPath()
+	if settings, err := a.loadSettings(); err == nil {
+		titleLanguage = a.resolveTitleLanguage(settings)
+	} else {
+		titleLanguage = a.resolveTitleLanguage(nil)
+	}
+	jsonFilePath := filepath.Join(a.Config.DataPath, "id_database.json")
+	jsonDataFolder := a.Config.DataPath
 	jsonURL := "https://api.github.com/repos/MrMilenko/Pinecone/contents/data/id_database.json"
 
-	if guiEnabled {
+	if flag.NArg() > 0 {
+		a.runCommand(flag.Args(), CLIOptions{
+			DataFolder:   jsonDataFolder,
+			JSONFilePath: jsonFilePath,
+			JSONUrl:      jsonURL,
+		})
+		return
+	}
+
+	if a.Config.TUI {
+		a.startTUI(CLIOptions{
+			DataFolder:   jsonDataFolder,
+			JSONFilePath: jsonFilePath,
+			JSONUrl:      jsonURL,
+		})
+	} else if a.Config.GUI {
 		guiOpts := GUIOptions{
 			DataFolder:   jsonDataFolder,
 			JSONFilePath: jsonFilePath,
 			JSONUrl:      jsonURL,
 		}
 
-		startGUI(guiOpts)
+		a.startGUI(guiOpts)
 	} else {
 		cliOpts := CLIOptions{
 			DataFolder:   jsonDataFolder,
@@ -68,6 +240,6 @@ func main() {
 			JSONUrl:      jsonURL,
 		}
 
-		startCLI(cliOpts)
+		a.startCLI(cliOpts)
 	}
 }
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2/theme"
+	fatihColor "github.com/fatih/color"
+)
+
+// tone is how a line of scan output is colored, each presenter maps it to
+// its own colors.
+type tone int
+
+const (
+	toneNormal tone = iota
+	toneGood        // known and archived
+	toneWarn        // worth a look, e.g. unarchived content
+	toneBad         // unknown content and errors
+	toneSystem      // system titles
+)
+
+// presentedLine is a line of scan output, the same for every presenter.
+type presentedLine struct {
+	Text string
+	Tone tone
+	// Header lines are drawn as a banner, a title's header has its TitleID.
+	Header  bool
+	TitleID string
+	// Separator lines end the block of an archived title update.
+	Separator bool
+	// Log lines are warnings and errors, the GUI shows them in the log pane.
+	Log bool
+}
+
+// eventLines turns a scan event into the lines to show. Thumbnails, the
+// submit help and the GUI's navigation pane are left to the presenters.
+func eventLines(event ScanEvent) []presentedLine {
+	var lines []presentedLine
+	add := func(t tone, format string, args ...interface{}) {
+		lines = append(lines, presentedLine{Text: fmt.Sprintf(format, args...), Tone: t})
+	}
+	header := func(title string) {
+		lines = append(lines, presentedLine{Text: title, Header: true})
+	}
+	log := func(t tone, message string) {
+		lines = append(lines, presentedLine{Text: message, Tone: t, Log: true})
+	}
+
+	f := event.Finding
+	switch event.Kind {
+	case EventTitleFound:
+		lines = append(lines, presentedLine{Text: event.TitleName, Header: true, TitleID: event.TitleID})
+	case EventDuplicate:
+		add(toneNormal, "Also found in %s: %s", event.Location, f.Path)
+	case EventCopy:
+		add(toneNormal, "Identical %s also at: %s", f.Kind, f.Path)
+	case EventConsole:
+		header("Console")
+		for _, line := range consoleLines(event.Console) {
+			add(toneNormal, "%s", line)
+		}
+	case EventSave:
+		add(toneNormal, "Save \"%s\" for %s at: %s", event.Message, event.TitleName, f.Path)
+	case EventWarning, EventSkipped:
+		log(toneWarn, event.Message)
+	case EventUnknownTitle:
+		log(toneWarn, unknownTitleMessage(event.UnknownTitle))
+	case EventSystemTitle:
+		log(toneSystem, systemTitleMessage(event.SystemTitle))
+	case EventError:
+		log(toneBad, event.Message)
+	case EventFinding:
+		switch f.Kind {
+		case kindDLC:
+			switch f.Status {
+			case statusUnknown:
+				add(toneBad, "Unknown content found at: %s", f.Path)
+				if f.Offering != "" {
+					add(toneBad, "Offering: %s", f.Offering)
+				}
+				if f.Listing != "" {
+					add(toneBad, "This content %s", f.Listing)
+				}
+				if f.Media != "" {
+					add(toneBad, "Media: %s", f.Media)
+				}
+				if f.Digest != "" {
+					add(toneBad, "Content digest: %s", f.Digest)
+				}
+				if date := modifiedColumn(f); date != "" {
+					add(toneBad, "Modified: %s", date)
+				}
+				add(toneBad, "Confidence: %s", f.Confidence)
+			case statusArchived:
+				add(toneGood, "Content is known and archived %s", f.Name)
+			default:
+				add(toneWarn, "%s has unarchived content found at: %s", f.TitleName, f.Path)
+				if f.Listing != "" {
+					add(toneWarn, "This content %s", f.Listing)
+				}
+				if f.Media != "" {
+					add(toneWarn, "Media: %s", f.Media)
+				}
+				if f.Digest != "" {
+					add(toneWarn, "Content digest: %s", f.Digest)
+				}
+				if date := modifiedColumn(f); date != "" {
+					add(toneWarn, "Modified: %s", date)
+				}
+			}
+		case kindUpdate:
+			header("File Info")
+			if f.Status == statusArchived {
+				add(toneGood, "Known and Archived Title update found for %s (%s) (%s)", f.TitleName, displayTitleID(f.TitleID), f.Name)
+				add(toneGood, "Path: %s", f.Path)
+				add(toneGood, "SHA1: %s", f.SHA1)
+				lines = append(lines, presentedLine{Text: separator, Separator: true})
+			} else if f.Status == statusKnownBad {
+				add(toneWarn, "Known bad Title update found for %s (%s): %s", f.TitleName, displayTitleID(f.TitleID), f.Name)
+				add(toneWarn, "Path: %s", f.Path)
+				add(toneWarn, "SHA1: %s", f.SHA1)
+			} else {
+				add(toneBad, "Unknown Title Update found for %s (%s)", f.TitleName, displayTitleID(f.TitleID))
+				add(toneBad, "Path: %s", f.Path)
+				add(toneBad, "SHA1: %s", f.SHA1)
+				if date := modifiedColumn(f); date != "" {
+					add(toneBad, "Modified: %s", date)
+				}
+				add(toneBad, "XBE signature: %s", f.Signature)
+				add(toneBad, "Confidence: %s", f.Confidence)
+				if f.SuggestedName != "" {
+					add(toneBad, "Suggested name: %s", f.SuggestedName)
+				}
+				if len(f.Prototype) > 0 {
+					add(toneBad, "Possible prototype: %s", strings.Join(f.Prototype, ", "))
+				}
+			}
+		case kindSave:
+			header("Wanted Save")
+			add(toneWarn, "Wanted save found for %s: %s", f.TitleName, f.Name)
+			add(toneWarn, "Path: %s", f.Path)
+			add(toneWarn, "Signature: %s", f.SHA1)
+		case kindDashboard:
+			header("Dashboard")
+			if f.Status == statusArchived {
+				add(toneGood, "Known dashboard found: %s (%s)", f.Name, filepath.Base(f.Path))
+				add(toneGood, "SHA1: %s", f.SHA1)
+			} else {
+				add(toneBad, "Unknown dashboard found: %s (%s)", filepath.Base(f.Path), f.Name)
+				add(toneBad, "Path: %s", f.Path)
+				add(toneBad, "SHA1: %s", f.SHA1)
+			}
+		case kindHomebrew:
+			header("Homebrew")
+			add(toneWarn, "Homebrew found: %s (%s)", f.Name, displayTitleID(f.TitleID))
+			add(toneWarn, "Path: %s", f.Path)
+			add(toneWarn, "SHA1: %s", f.SHA1)
+		case kindSoundtrack:
+			header("Soundtracks")
+			if f.Status == statusArchived {
+				add(toneGood, "Known soundtrack found: %s (%s)", f.Name, f.Path)
+			} else {
+				add(toneWarn, "Soundtrack found: %s", f.Path)
+				if f.Media != "" {
+					add(toneWarn, "Media: %s", f.Media)
+				}
+				add(toneWarn, "Content digest: %s", f.Digest)
+			}
+		case kindDevkit:
+			header("Devkit Builds")
+			add(toneBad, "Devkit build found: %s (%s)", f.Name, displayTitleID(f.TitleID))
+			add(toneBad, "Path: %s", f.Path)
+			add(toneBad, "SHA1: %s", f.SHA1)
+			add(toneBad, "XBE signature: %s", f.Signature)
+			if len(f.Prototype) > 0 {
+				add(toneBad, "Possible prototype: %s", strings.Join(f.Prototype, ", "))
+			}
+		}
+	}
+	return lines
+}
+
+// hasThumbnail reports whether the thumbnail of a finding is shown after it.
+func (a *App) hasThumbnail(event ScanEvent) bool {
+	return event.Kind == EventFinding && event.Finding.Kind == kindDLC && event.Finding.ContentID != "" && a.thumbnailsEnabled()
+}
+
+// cliPresenter prints scan events to the console.
+type cliPresenter struct {
+	app *App
+}
+
+func (p cliPresenter) Present(event ScanEvent) {
+	for _, line := range eventLines(event) {
+		switch {
+		case line.Header:
+			printHeader(line.Text)
+		case line.Separator:
+			printLine(line.Text)
+		default:
+			printInfo(cliColor(line.Tone), "%s\n", line.Text)
+		}
+	}
+	if p.app.hasThumbnail(event) {
+		if thumbnail, err := p.app.fetchThumbnail(event.Finding.ContentID); err != nil {
+			printInfo(fatihColor.FgYellow, "%s\n", err)
+		} else if thumbnail != "" {
+			printInfo(fatihColor.FgWhite, "Thumbnail: %s\n", thumbnail)
+		}
+	}
+	if firstUnknownFind(event) && !quietMode {
+		printSubmitHelp()
+	}
+}
+
+func cliColor(t tone) fatihColor.Attribute {
+	switch t {
+	case toneGood:
+		return fatihColor.FgGreen
+	case toneWarn:
+		return fatihColor.FgYellow
+	case toneBad:
+		return fatihColor.FgRed
+	case toneSystem:
+		return fatihColor.FgCyan
+	}
+	return fatihColor.FgWhite
+}
+
+// guiPresenter adds scan events to the GUI output.
+type guiPresenter struct {
+	app *App
+}
+
+func (p guiPresenter) Present(event ScanEvent) {
+	for _, line := range eventLines(event) {
+		switch {
+		case line.TitleID != "":
+			p.app.addTitleHeader(line.TitleID, line.Text)
+			addNavigationTitle(line.TitleID, line.Text)
+		case line.Header:
+			p.app.addHeader(line.Text)
+		case line.Separator:
+			p.app.addText(color.Transparent, "%s", line.Text)
+		case line.Log:
+			p.app.addLog(guiColor(line.Tone), "%s", line.Text)
+		default:
+			p.app.addText(guiColor(line.Tone), "%s", line.Text)
+		}
+	}
+	if event.Kind == EventUnknownTitle {
+		addNavigationTitle(event.TitleID, "Unknown "+displayTitleID(event.TitleID))
+	}
+	if p.app.hasThumbnail(event) {
+		p.app.addThumbnail(event.Finding.ContentID)
+	}
+	if firstUnknownFind(event) {
+		p.app.showSubmitHelp(guiWindow)
+	}
+}
+
+func guiColor(t tone) color.Color {
+	switch t {
+	case toneGood:
+		return guiGoodColor()
+	case toneWarn:
+		return guiWarnColor()
+	case toneBad:
+		return theme.ErrorColor()
+	}
+	return theme.ForegroundColor()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// Allowed media flags of XBE certificates retail titles don't set: only
+// prototypes burnt to discs or run from a dongle need them.
+const (
+	xbeMediaCD        = 0x00000008
+	xbeMediaDVD5RW    = 0x00000040
+	xbeMediaDVD9RW    = 0x00000080
+	xbeMediaDongle    = 0x00000100
+	xbeMediaNonRetail = xbeMediaCD | xbeMediaDVD5RW | xbeMediaDVD9RW | xbeMediaDongle
+)
+
+// prototypeBuildPaths are words in the build path of an XBE naming a
+// pre-release build, e.g. "d:\halo2\beta\halo2.exe".
+var prototypeBuildPaths = []string{"alpha", "beta", "debug", "demo", "preview", "proto"}
+
+// prototypeStrings are left in executables built with asserts and debug
+// output, retail builds strip them.
+var prototypeStrings = []string{"Assertion failed", "xbdm.dll", "DbgPrint"}
+
+// prototypeHints lists why an XBE may be a prototype or pre-release build:
+// a debug build, non-retail allowed media, a build path naming a beta,
+// debug strings or a certificate dated before the title's release. Empty if
+// nothing points that way, these are hints for maintainers, not proof.
+func prototypeHints(fsys fs.FS, filePath string, releaseYear int) []string {
+	xbe, err := readXBEInfoFS(fsys, filePath)
+	if err != nil {
+		return nil
+	}
+
+	var hints []string
+	if xbe.Debug {
+		hints = append(hints, "debug build")
+	}
+	if media := mediaNames(xbe.Media & xbeMediaNonRetail); media != "" {
+		hints = append(hints, "allowed media include "+media)
+	}
+	lowerPath := strings.ToLower(xbe.DebugPath)
+	for _, word := range prototypeBuildPaths {
+		if strings.Contains(lowerPath, word) {
+			hints = append(hints, "built at "+xbe.DebugPath)
+			break
+		}
+	}
+	if year := xbe.Timestamp.Year(); releaseYear != 0 && xbe.Timestamp.Unix() > 0 && year < releaseYear {
+		hints = append(hints, fmt.Sprintf("certificate dated %s, before the %d release", xbe.Timestamp.Format("2006-01-02"), releaseYear))
+	}
+
+	if info, err := fs.Stat(fsys, filePath); err == nil && info.Size() <= updateMaximumSize {
+		if data, err := fs.ReadFile(fsys, filePath); err == nil {
+			for _, s := range prototypeStrings {
+				if bytes.Contains(data, []byte(s)) {
+					hints = append(hints, fmt.Sprintf("debug string %q", s))
+				}
+			}
+		}
+	}
+	return hints
+}
+
+// mediaNames names the allowed media flags, e.g. "CD, DVD-RW".
+func mediaNames(media uint32) string {
+	var names []string
+	if media&xbeMediaCD != 0 {
+		names = append(names, "CD")
+	}
+	if media&(xbeMediaDVD5RW|xbeMediaDVD9RW) != 0 {
+		names = append(names, "DVD-RW")
+	}
+	if media&xbeMediaDongle != 0 {
+		names = append(names, "dongle")
+	}
+	return strings.Join(names, ", ")
+}
+
+// PossiblePrototypes returns the findings with hints of a prototype build,
+// see prototypeHints.
+func (r *Report) PossiblePrototypes() []Finding {
+	var prototypes []Finding
+	for _, f := range r.Findings {
+		if len(f.Prototype) > 0 {
+			prototypes = append(prototypes, f)
+		}
+	}
+	return prototypes
+}
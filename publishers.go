@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/Xbox-Preservation-Project/Pinecone/titleid"
+)
+
+// PublisherStats is the coverage of one publisher's titles, grouped by the
+// publisher code of the title IDs.
+type PublisherStats struct {
+	Code string
+	Name string
+	DatabaseStats
+}
+
+// Completion is the share of the publisher's content that is archived.
+func (p PublisherStats) Completion() float64 {
+	if p.ContentIDs == 0 {
+		return 1
+	}
+	return float64(p.ArchivedItems) / float64(p.ContentIDs)
+}
+
+func publisherName(code string, name string) string {
+	if name == "" {
+		return "Unknown (" + code + ")"
+	}
+	return name
+}
+
+// computePublisherStats groups the database by publisher, the publishers
+// with the most content still wanted first.
+func computePublisherStats(list TitleList) []PublisherStats {
+	groups := make(map[string]TitleList)
+	names := make(map[string]string)
+	for id, titleData := range list.Titles {
+		// Codes that aren't letters are grouped by their hex prefix
+		code := id[:4]
+		if decoded, err := titleid.Decode(id); err == nil && titleid.Decoded(id) != "" {
+			code = decoded.Code
+			names[code] = decoded.Publisher
+		}
+		group, ok := groups[code]
+		if !ok {
+			group = TitleList{Titles: make(map[string]TitleData)}
+			groups[code] = group
+		}
+		group.Titles[id] = titleData
+	}
+
+	stats := make([]PublisherStats, 0, len(groups))
+	for code, group := range groups {
+		stats = append(stats, PublisherStats{
+			Code:          code,
+			Name:          publisherName(code, names[code]),
+			DatabaseStats: computeDatabaseStats(group),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].ItemsWanted != stats[j].ItemsWanted {
+			return stats[i].ItemsWanted > stats[j].ItemsWanted
+		}
+		return stats[i].Code < stats[j].Code
+	})
+	return stats
+}
+
+func (a *App) printPublisherStats() {
+	fmt.Println()
+	fmt.Println("By publisher (most content wanted first):")
+	fmt.Printf("  %-4s %-30s %7s %9s %9s %7s %9s\n", "Code", "Publisher", "Titles", "Complete", "Archived", "Wanted", "Coverage")
+	for _, p := range computePublisherStats(a.Titles) {
+		fmt.Printf("  %-4s %-30s %7d %9d %9s %7d %8.0f%%\n", p.Code, p.Name, p.Titles, p.TitlesComplete,
+			fmt.Sprintf("%d/%d", p.ArchivedItems, p.ContentIDs), p.ItemsWanted, p.Completion()*100)
+	}
+}
+
+// publisherCoverage lists the publishers that still have content wanted,
+// with a completion bar each, for the Dashboard tab.
+func (a *App) publisherCoverage() fyne.CanvasObject {
+	rows := container.NewVBox()
+	for _, p := range computePublisherStats(a.Titles) {
+		if p.ItemsWanted == 0 {
+			continue
+		}
+		bar := widget.NewProgressBar()
+		bar.SetValue(p.Completion())
+		label := widget.NewLabel(fmt.Sprintf("%s (%s): %d of %d archived, %d wanted, %d of %d titles complete",
+			p.Name, p.Code, p.ArchivedItems, p.ContentIDs, p.ItemsWanted, p.TitlesComplete, p.Titles))
+		rows.Add(container.NewGridWithColumns(2, label, bar))
+	}
+	if len(rows.Objects) == 0 {
+		rows.Add(widget.NewLabel("Every publisher's content is archived."))
+	}
+	return rows
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+const (
+	// quickHashMinSize is the size from which files get a quick check, smaller
+	// ones are hashed in full right away.
+	quickHashMinSize = 16 * 1024 * 1024
+	// quickHashSpan is how much of the start and of the end of a file the
+	// quick hash reads.
+	quickHashSpan = 64 * 1024
+)
+
+// quickHash hashes the size, the first and the last 64 KiB of a file, e.g.
+// "1073741824:3f2a...". "" for files under quickHashMinSize.
+func quickHash(fsys fs.FS, name string) (string, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.Size() < quickHashMinSize {
+		return "", nil
+	}
+	reader, ok := file.(io.ReaderAt)
+	if !ok {
+		return "", nil
+	}
+
+	hash := sha1.New()
+	buf := make([]byte, quickHashSpan)
+	for _, offset := range []int64{0, info.Size() - quickHashSpan} {
+		if _, err := reader.ReadAt(buf, offset); err != nil {
+			return "", err
+		}
+		hash.Write(buf)
+	}
+	return fmt.Sprintf("%d:%x", info.Size(), hash.Sum(nil)), nil
+}
+
+// quickCheck looks a large file's quick hash up in the database: the SHA1
+// listed for it, "" when it is none of the database's files. checked is
+// false when the file isn't checked, it must be hashed in full: without
+// -quick-hash or a database listing quick hashes, for files too small for a
+// quick hash or that can't be read.
+func (a *App) quickCheck(fsys fs.FS, name string) (fileHash string, checked bool) {
+	if !a.Config.QuickHash || len(a.Titles.QuickHashes) == 0 {
+		return "", false
+	}
+	quick, err := quickHash(fsys, name)
+	if err != nil || quick == "" {
+		return "", false
+	}
+	return a.Titles.QuickHashes[quick], true
+}
+
+// quickCheckMisses tells whether a large file can't be any file of the
+// database, from its quick hash, so hashing it in full can be skipped.
+func (a *App) quickCheckMisses(fsys fs.FS, name string) bool {
+	fileHash, checked := a.quickCheck(fsys, name)
+	return checked && fileHash == ""
+}
+
+// hashScannedFile hashes a file a scan checks against the database. A large
+// file whose quick hash is in the database gets the SHA1 listed for it,
+// others are hashed in full with getSHA1HashFS: the SHA1 of unknown content
+// is needed to submit it.
+func (a *App) hashScannedFile(location string, fsys fs.FS, name string) (string, error) {
+	if fileHash, _ := a.quickCheck(fsys, name); fileHash != "" {
+		return fileHash, nil
+	}
+	return a.getSHA1HashFS(location, fsys, name)
+}
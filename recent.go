@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// maxRecentLocations is how many dump folders the GUI remembers.
+const maxRecentLocations = 10
+
+// recentLocationsMenu is the File menu's list of recent dump folders.
+var recentLocationsMenu *fyne.MenuItem
+
+// rememberLocation puts a dump folder first in "recentLocations" in the
+// settings, dropping the oldest past maxRecentLocations.
+func (a *App) rememberLocation(folder string) {
+	if abs, err := filepath.Abs(folder); err == nil && !isArchive(folder) {
+		folder = abs
+	}
+	settings, err := a.loadSettings()
+	if err != nil {
+		return
+	}
+	recent := []string{folder}
+	for _, location := range settings.RecentLocations {
+		if location != folder && len(recent) < maxRecentLocations {
+			recent = append(recent, location)
+		}
+	}
+	settings.RecentLocations = recent
+	if err := a.saveSettings(settings); err != nil {
+		fmt.Println(err)
+	}
+	a.refreshRecentLocationsMenu()
+}
+
+// useDumpFolder sets the folder to scan, if it holds a TDATA folder.
+func (a *App) useDumpFolder(folder string) {
+	if _, found := findTDATA(dumpDirFS(folder)); !found {
+		a.addText(theme.ForegroundColor(), "Incorrect pathing. Please select a dump with TDATA folder.")
+		return
+	}
+	a.Config.DumpLocation = folder
+	a.Config.DumpLocations = nil
+	a.addText(theme.ForegroundColor(), "Path set to: %s", folder)
+	a.rememberLocation(folder)
+}
+
+// newRecentLocationsMenu returns the File menu item listing the recent dump
+// folders, newest first.
+func (a *App) newRecentLocationsMenu() *fyne.MenuItem {
+	recentLocationsMenu = fyne.NewMenuItem("Recent Dump Folders", nil)
+	a.updateRecentLocationsMenu()
+	return recentLocationsMenu
+}
+
+func (a *App) updateRecentLocationsMenu() {
+	var items []*fyne.MenuItem
+	if settings, err := a.loadSettings(); err == nil {
+		for _, location := range settings.RecentLocations {
+			location := location
+			items = append(items, fyne.NewMenuItem(location, func() { a.useDumpFolder(location) }))
+		}
+	}
+	if len(items) == 0 {
+		none := fyne.NewMenuItem("No recent dump folders", nil)
+		none.Disabled = true
+		items = append(items, none)
+	}
+	recentLocationsMenu.ChildMenu = fyne.NewMenu("", items...)
+}
+
+// refreshRecentLocationsMenu updates the menu after a folder was remembered.
+func (a *App) refreshRecentLocationsMenu() {
+	if recentLocationsMenu == nil || guiWindow == nil {
+		return
+	}
+	a.updateRecentLocationsMenu()
+	if menu := guiWindow.MainMenu(); menu != nil {
+		menu.Refresh()
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Xbox-Preservation-Project/Pinecone/titleid"
+)
+
+const (
+	kindDLC    = "DLC"
+	kindUpdate = "Title Update"
+
+	statusArchived   = "archived"
+	statusUnarchived = "unarchived"
+	statusUnknown    = "unknown"
+	// statusKnownBad is a file in the database's known bad list, corrupt or
+	// fake. The reason is in the finding's Name.
+	statusKnownBad = "bad"
+)
+
+// Finding is a single item reported during a scan.
+type Finding struct {
+	TitleID   string
+	TitleName string
+	Kind      string
+	Status    string
+	ContentID string // DLC only
+	Offering  string // DLC only, see describeOffering
+	Listing   string // DLC only, see describeListing
+	Digest    string // DLC only, hash of the whole content folder, see contentDigest
+	Name      string // archived content or known update name, if any
+	Path      string
+	SHA1      string
+	Location  string    // dump location the item was found in
+	Modified  time.Time // DLC and updates, see fileModified
+	Also      []string  // "location: path" of the same item found elsewhere, or just the path of an identical copy in the same location
+	// Confidence scores unknown DLC and title updates, see Confidence.
+	Confidence Confidence
+	// Media summarizes the audio and video files of unarchived DLC, see
+	// describeMedia.
+	Media string
+	// Signature is the signature status of unknown title updates, see
+	// checkXBESignature.
+	Signature string
+	// SuggestedName is the proposed database name of unknown title updates,
+	// see suggestUpdateName.
+	SuggestedName string
+	// Prototype lists hints that an unknown executable is a prototype or
+	// pre-release build, see prototypeHints.
+	Prototype []string
+}
+
+// Report collects the findings of the last scan so they can be exported.
+type Report struct {
+	ID            string // random UUID of the scan, see newReportID
+	Version       string
+	OS            string // GOOS/GOARCH Pinecone ran on
+	Database      DatabaseInfo
+	Created       time.Time
+	Duration      time.Duration
+	DumpLocation  string
+	Findings      []Finding
+	Errors        []string            // files that couldn't be checked
+	Skipped       []string            // files not hashed because of the hash size rules
+	UnknownTitles []UnknownTitle      // title ID folders of titles missing from the database
+	SystemTitles  []SystemTitleFolder // title ID folders of system titles
+	Scanned       []ScannedTitle      // folders of titles known to the database, see NotFound
+	Console       *ConsoleInfo
+}
+
+// ScannedTitle is a title known to the database whose folder was scanned.
+type ScannedTitle struct {
+	TitleID   string
+	TitleName string
+}
+
+// ReportTitle groups the findings of a single title.
+type ReportTitle struct {
+	TitleID   string
+	TitleName string
+	Findings  []Finding
+}
+
+// displayTitleID returns a title ID with its publisher decoding, e.g.
+// "4d530064 MS-100", for showing next to titles.
+func displayTitleID(id string) string {
+	if decoded := titleid.Decoded(id); decoded != "" {
+		return id + " " + decoded
+	}
+	return id
+}
+
+func (a *App) resetReport() {
+	a.Report = Report{
+		ID:           newReportID(),
+		Version:      version,
+		OS:           reportOS(),
+		Database:     a.currentDatabaseInfo(),
+		Created:      time.Now(),
+		DumpLocation: strings.Join(a.scanLocations(), ", "),
+	}
+}
+
+// addFinding records a finding, returning the event kind to emit for it. An
+// item already found in another location, or a file with the same hash at
+// another path of this location (copied folders, backups), is merged into the
+// first finding instead.
+func (r *Report) addFinding(f Finding) EventKind {
+	for i, existing := range r.Findings {
+		if existing.TitleID != f.TitleID || existing.key() != f.key() {
+			continue
+		}
+		if existing.Location != f.Location {
+			r.Findings[i].Also = append(existing.Also, f.Location+": "+f.Path)
+			return EventDuplicate
+		}
+		if f.SHA1 != "" && existing.Path != f.Path {
+			r.Findings[i].Also = append(existing.Also, f.Path)
+			return EventCopy
+		}
+	}
+	r.Findings = append(r.Findings, f)
+	return EventFinding
+}
+
+// addScannedTitle records a title folder scanned, once for all locations.
+func (r *Report) addScannedTitle(titleID string, titleName string) {
+	for _, title := range r.Scanned {
+		if title.TitleID == titleID {
+			return
+		}
+	}
+	r.Scanned = append(r.Scanned, ScannedTitle{TitleID: titleID, TitleName: titleName})
+}
+
+// key identifies the item a finding is about, updates by their hash, DLC
+// by their content ID and soundtracks by their digest.
+func (f Finding) key() string {
+	if f.SHA1 != "" {
+		return f.SHA1
+	}
+	if f.ContentID == "" {
+		return f.Digest
+	}
+	return f.ContentID
+}
+
+// Titles returns the findings grouped per title, in the order they were found
+// except for DLC, which comes first and is grouped by offering group.
+func (r *Report) Titles() []ReportTitle {
+	var grouped []ReportTitle
+	index := make(map[string]int)
+	for _, f := range r.Findings {
+		i, ok := index[f.TitleID]
+		if !ok {
+			i = len(grouped)
+			index[f.TitleID] = i
+			grouped = append(grouped, ReportTitle{TitleID: f.TitleID, TitleName: f.TitleName})
+		}
+		grouped[i].Findings = append(grouped[i].Findings, f)
+	}
+	for _, title := range grouped {
+		sort.SliceStable(title.Findings, func(i, j int) bool {
+			return offeringSortKey(title.Findings[i]) < offeringSortKey(title.Findings[j])
+		})
+	}
+	return grouped
+}
+
+func offeringSortKey(f Finding) string {
+	if f.Kind != kindDLC {
+		return "~" // after all DLC
+	}
+	if c, err := decodeContentID(f.ContentID); err == nil {
+		return c.GroupKey()
+	}
+	return f.ContentID
+}
+
+// Count returns the number of findings with the given status.
+func (r *Report) Count(status string) int {
+	count := 0
+	for _, f := range r.Findings {
+		if f.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Interesting returns a copy of the report holding only the findings worth
+// submitting: unknown and unarchived content.
+func (r *Report) Interesting() *Report {
+	filtered := *r
+	filtered.Findings = nil
+	for _, f := range r.Findings {
+		if f.Status == statusUnknown || f.Status == statusUnarchived {
+			filtered.Findings = append(filtered.Findings, f)
+		}
+	}
+	return &filtered
+}
+
+// Archived returns a copy of the report holding only the archived findings.
+func (r *Report) Archived() *Report {
+	return r.withStatus(statusArchived)
+}
+
+// KnownBad returns a copy of the report holding only the known bad findings.
+func (r *Report) KnownBad() *Report {
+	return r.withStatus(statusKnownBad)
+}
+
+func (r *Report) withStatus(status string) *Report {
+	filtered := *r
+	filtered.Findings = nil
+	for _, f := range r.Findings {
+		if f.Status == status {
+			filtered.Findings = append(filtered.Findings, f)
+		}
+	}
+	return &filtered
+}
+
+// NotFound returns the titles whose folder was scanned without finding any
+// content or updates in it.
+func (r *Report) NotFound() []ScannedTitle {
+	found := make(map[string]bool)
+	for _, f := range r.Findings {
+		found[f.TitleID] = true
+	}
+	var notFound []ScannedTitle
+	for _, title := range r.Scanned {
+		if !found[title.TitleID] {
+			notFound = append(notFound, title)
+		}
+	}
+	return notFound
+}
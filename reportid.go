@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// DatabaseInfo identifies the database a report was made with, so it's
+// clear whether it was stale.
+type DatabaseInfo struct {
+	SHA           string    // git blob SHA, as on GitHub, see gitBlobSHA
+	SchemaVersion int       // see TitleList.SchemaVersion
+	Updated       time.Time // when the local copy was last written
+}
+
+// newReportID returns a random (version 4) UUID identifying a scan.
+func newReportID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// currentDatabaseInfo describes the local database, zero if it can't be
+// read.
+func (a *App) currentDatabaseInfo() DatabaseInfo {
+	jsonFilePath := filepath.Join(a.Config.DataPath, "id_database.json")
+	data, err := os.ReadFile(jsonFilePath)
+	if err != nil {
+		return DatabaseInfo{}
+	}
+	info := DatabaseInfo{SHA: gitBlobSHA(data), SchemaVersion: a.Titles.SchemaVersion}
+	if info.SchemaVersion == 0 {
+		info.SchemaVersion = 1
+	}
+	if stat, err := os.Stat(jsonFilePath); err == nil {
+		info.Updated = stat.ModTime()
+	}
+	return info
+}
+
+// String summarizes the database, e.g. "3f2a9c1 (schema 2, updated
+// 2024-05-01)".
+func (d DatabaseInfo) String() string {
+	if d.SHA == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (schema %d, updated %s)", d.SHA[:7], d.SchemaVersion, d.Updated.Format("2006-01-02"))
+}
+
+// Producer describes what made the report, e.g. "Pinecone v0.6.0 on
+// linux/amd64, database 3f2a9c1 (schema 2, updated 2024-05-01), scan took
+// 42s".
+func (r *Report) Producer() string {
+	producer := fmt.Sprintf("Pinecone v%s on %s, database %s", r.Version, r.OS, r.Database)
+	if r.Duration >= time.Second {
+		producer += ", scan took " + r.Duration.Round(time.Second).String()
+	} else if r.Duration > 0 {
+		producer += ", scan took " + r.Duration.Round(time.Millisecond).String()
+	}
+	return producer
+}
+
+func reportOS() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
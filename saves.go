@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+const kindSave = "Save"
+
+// WantedSave is a save the preservation community is looking for, e.g. a
+// promo or unlock save, matched by its SaveMeta.xbx name or its signature.
+type WantedSave struct {
+	Name  string `json:"Name"`
+	SHA1  string `json:"SHA1,omitempty"` // signature of the save, see saveSignature
+	Notes string `json:"Notes,omitempty"`
+}
+
+// readXboxMeta parses a SaveMeta.xbx or TitleMeta.xbx file: UTF-16LE
+// "Key=Value" lines.
+func readXboxMeta(fsys fs.FS, name string) (map[string]string, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte{0xff, 0xfe})
+	if len(data)%2 != 0 {
+		data = data[:len(data)-1]
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+
+	meta := make(map[string]string)
+	for _, line := range strings.Split(string(utf16.Decode(units)), "\n") {
+		key, value, found := strings.Cut(strings.TrimRight(line, "\r\x00"), "=")
+		if found {
+			meta[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return meta, nil
+}
+
+// saveSignature hashes the names and contents of every file in a save
+// folder, so a copied save has the same signature wherever it is found.
+func saveSignature(fsys fs.FS, saveDir string) (string, error) {
+	var files []string
+	err := walkDump(fsys, saveDir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			files = append(files, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return strings.ToLower(files[i]) < strings.ToLower(files[j])
+	})
+
+	hash := sha1.New()
+	for _, name := range files {
+		rel := strings.ToLower(strings.TrimPrefix(name, saveDir+"/"))
+		fmt.Fprintf(hash, "%s\x00", rel)
+		file, err := fsys.Open(name)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(hash, file)
+		file.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// checkForSaves checks the save folders in the UDATA folder of a dump for
+// wanted saves. Only titles with wanted saves are read unless every save is
+// listed, so large save trees don't slow down normal scans.
+func (a *App) checkForSaves(fsys fs.FS, root string, location string, events chan<- ScanEvent) error {
+	if len(a.Titles.WantedSaves) == 0 && !a.Config.ListSaves {
+		return nil
+	}
+	udata, found := findSubDir(fsys, root, "UDATA")
+	if !found {
+		return nil
+	}
+
+	entries, err := fs.ReadDir(fsys, udata)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		titleDir := path.Join(udata, entry.Name())
+		entry := resolveSymlink(fsys, titleDir, entry)
+		if entry == nil || !entry.IsDir() || len(entry.Name()) != 8 {
+			continue
+		}
+		titleID := strings.ToLower(entry.Name())
+		if !a.titleSelected(titleID) {
+			continue
+		}
+		if _, wanted := a.Titles.WantedSaves[titleID]; !wanted && !a.Config.ListSaves {
+			continue
+		}
+
+		if err := a.checkTitleSaves(fsys, titleDir, titleID, location, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTitleSaves checks the save folders of a single title ID folder in
+// UDATA.
+func (a *App) checkTitleSaves(fsys fs.FS, titleDir string, titleID string, location string, events chan<- ScanEvent) error {
+	titleName := titleID
+	if titleData, ok := a.Titles.Titles[titleID]; ok {
+		titleName = titleData.DisplayName()
+	} else if metaPath, found := findFile(fsys, titleDir, "TitleMeta.xbx"); found {
+		if meta, err := readXboxMeta(fsys, metaPath); err == nil && meta["TitleName"] != "" {
+			titleName = meta["TitleName"]
+		}
+	}
+
+	saves, err := fs.ReadDir(fsys, titleDir)
+	if err != nil {
+		return err
+	}
+	wanted := a.Titles.WantedSaves[titleID]
+	for _, save := range saves {
+		saveDir := path.Join(titleDir, save.Name())
+		save := resolveSymlink(fsys, saveDir, save)
+		if save == nil || !save.IsDir() {
+			continue
+		}
+
+		saveName := save.Name()
+		if metaPath, found := findFile(fsys, saveDir, "SaveMeta.xbx"); found {
+			if meta, err := readXboxMeta(fsys, metaPath); err == nil && meta["Name"] != "" {
+				saveName = meta["Name"]
+			}
+		}
+		if a.Config.ListSaves {
+			events <- ScanEvent{Kind: EventSave, TitleID: titleID, TitleName: titleName, Location: location, Message: saveName,
+				Finding: Finding{Path: displayPath(location, saveDir)}}
+		}
+		if len(wanted) == 0 {
+			continue
+		}
+
+		signature, err := saveSignature(fsys, saveDir)
+		if err != nil {
+			reportHashError(saveDir, err, events)
+			continue
+		}
+		for _, w := range wanted {
+			// A signature is exact, names alone can be shared by ordinary saves.
+			if w.SHA1 == signature || (w.SHA1 == "" && strings.EqualFold(w.Name, saveName)) {
+				a.reportSave(titleID, titleName, w, saveName, displayPath(location, saveDir), signature, location, events)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// reportSave emits a save matching an entry of the database's Wanted Saves.
+func (a *App) reportSave(titleID string, titleName string, wanted WantedSave, saveName string, displayedPath string, signature string, location string, events chan<- ScanEvent) {
+	name := saveName
+	if wanted.Notes != "" {
+		name += " (" + wanted.Notes + ")"
+	}
+	a.emitFinding(events, location, Finding{TitleID: titleID, TitleName: titleName, Kind: kindSave, Status: statusUnarchived, Name: name, Path: displayedPath, SHA1: signature})
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const defaultScheduleMinutes = 60
+
+var (
+	scheduleStop chan struct{}
+	// scanRunning is set while a GUI scan runs, whether started with the Scan
+	// button, by the scheduler or by the tray, see exclusiveScan.
+	scanRunning atomic.Bool
+	// seenMu guards seenFindings and backgroundScan, which the scheduler and
+	// tray goroutines share with the GUI.
+	seenMu sync.Mutex
+	// seenFindings holds the interesting findings already notified about, so
+	// periodic scans only notify about new ones.
+	seenFindings = make(map[string]bool)
+	// backgroundScan is set during scheduled and tray rescans, which notify
+	// about new findings instead of every finished scan.
+	backgroundScan bool
+)
+
+// applySchedule starts or stops the periodic rescan to match the settings.
+func (a *App) applySchedule(settings *Settings, options GUIOptions, window fyne.Window) {
+	stopScheduler()
+	if !settings.ScheduleEnabled {
+		return
+	}
+
+	minutes := settings.ScheduleMinutes
+	if minutes <= 0 {
+		minutes = defaultScheduleMinutes
+	}
+	a.startScheduler(time.Duration(minutes)*time.Minute, options, window)
+}
+
+func (a *App) startScheduler(interval time.Duration, options GUIOptions, window fyne.Window) {
+	// Anything found before the scheduler started has already been seen.
+	a.markFindingsSeen()
+
+	stop := make(chan struct{})
+	scheduleStop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.backgroundRescan(options, window)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func stopScheduler() {
+	if scheduleStop != nil {
+		close(scheduleStop)
+		scheduleStop = nil
+	}
+}
+
+// markFindingsSeen records the interesting findings of the last scan,
+// returning the ones that weren't seen before.
+func (a *App) markFindingsSeen() []Finding {
+	seenMu.Lock()
+	defer seenMu.Unlock()
+	var newFindings []Finding
+	for _, f := range a.Report.Interesting().Findings {
+		key := f.TitleID + "/" + f.key()
+		if !seenFindings[key] {
+			seenFindings[key] = true
+			newFindings = append(newFindings, f)
+		}
+	}
+	return newFindings
+}
+
+// exclusiveScan runs scan unless another GUI scan is running, all scans share
+// the report and the output. Returns whether it ran.
+func exclusiveScan(scan func()) bool {
+	if !scanRunning.CompareAndSwap(false, true) {
+		return false
+	}
+	defer scanRunning.Store(false)
+	scan()
+	return true
+}
+
+func setBackgroundScan(background bool) {
+	seenMu.Lock()
+	backgroundScan = background
+	seenMu.Unlock()
+}
+
+func isBackgroundScan() bool {
+	seenMu.Lock()
+	defer seenMu.Unlock()
+	return backgroundScan
+}
+
+// backgroundRescan rescans for the scheduler or the tray and notifies about
+// new findings. Skipped while another scan is running, returns whether it ran.
+func (a *App) backgroundRescan(options GUIOptions, window fyne.Window) bool {
+	ran := exclusiveScan(func() {
+		setBackgroundScan(true)
+		defer setBackgroundScan(false)
+		a.startScan(options, window)
+	})
+	if ran {
+		a.notifyNewFindings()
+	}
+	return ran
+}
+
+// notifyNewFindings sends a desktop notification when the last scan found
+// interesting content that wasn't there before, and posts it to the webhook
+// if one is set.
+func (a *App) notifyNewFindings() {
+	newFindings := a.markFindingsSeen()
+	if len(newFindings) == 0 {
+		return
+	}
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Pinecone",
+		fmt.Sprintf("Found %d new unknown or unarchived item(s) in %s", len(newFindings), a.Config.DumpLocation)))
+
+	settings, err := a.loadSettings()
+	if err != nil {
+		settings = &Settings{}
+	}
+	// Only what's new, titles missing from the database were posted with
+	// their first findings
+	report := a.Report
+	report.Findings = newFindings
+	report.UnknownTitles = nil
+	a.postWebhook(settings, &report)
+}
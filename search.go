@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TitleMatch is a database title matching a search query.
+type TitleMatch struct {
+	TitleID string
+	Title   TitleData
+	Score   int
+}
+
+// fuzzyScore rates how well query matches target. Substring matches win over
+// matches of the query's characters in order, which win over no match at all.
+func fuzzyScore(query string, target string) (int, bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	target = strings.ToLower(target)
+	if query == "" {
+		return 0, false
+	}
+
+	if i := strings.Index(target, query); i >= 0 {
+		score := 1000 - i
+		if i == 0 || !unicode.IsLetter(rune(target[i-1])) {
+			score += 500 // starts at a word boundary
+		}
+		if len(query) == len(target) {
+			score += 1000
+		}
+		return score, true
+	}
+
+	// All query characters in order, rewarding consecutive runs.
+	score := 0
+	run := 0
+	t := 0
+	for _, q := range query {
+		if unicode.IsSpace(q) {
+			continue
+		}
+		found := false
+		for t < len(target) {
+			c := rune(target[t])
+			t++
+			if c == q {
+				run++
+				score += run * 2
+				found = true
+				break
+			}
+			run = 0
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return score, true
+}
+
+// searchTitles returns the titles whose name, in any language, or title ID
+// matches query, best matches first.
+func (a *App) searchTitles(query string) []TitleMatch {
+	var matches []TitleMatch
+	for titleID, titleData := range a.Titles.Titles {
+		score, ok := 0, false
+		for _, name := range titleData.Names() {
+			if nameScore, nameOK := fuzzyScore(query, name); nameOK && (!ok || nameScore > score) {
+				score, ok = nameScore, true
+			}
+		}
+		if strings.EqualFold(strings.TrimSpace(query), titleID) {
+			score, ok = 10000, true
+		}
+		if ok {
+			matches = append(matches, TitleMatch{TitleID: titleID, Title: titleData, Score: score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Title.TitleName < matches[j].Title.TitleName
+	})
+	return matches
+}
+
+// archiveStatus summarizes how much of a title's known content is archived.
+func archiveStatus(titleData TitleData) string {
+	archived := 0
+	for _, archivedItem := range titleData.Archived {
+		archived += len(archivedItem)
+	}
+	status := fmt.Sprintf("%d/%d content archived, %d known updates", archived, len(titleData.ContentIDs), len(titleData.TitleUpdatesKnown))
+	if archived < len(titleData.ContentIDs) {
+		status += " - content still wanted"
+	}
+	return status
+}
+
+func (a *App) printSearchResults(query string) {
+	matches := a.searchTitles(query)
+	if len(matches) == 0 {
+		fmt.Printf("No titles found matching %q\n", query)
+		return
+	}
+	for _, match := range matches {
+		fmt.Printf("%-16s  %-40s  %s\n", displayTitleID(match.TitleID), match.Title.DisplayName(), archiveStatus(match.Title))
+	}
+}
+
+func (a *App) showSearchWindow() {
+	var matches []TitleMatch
+
+	results := widget.NewList(
+		func() int {
+			return len(matches)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			match := matches[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s  %s\n%s", match.TitleID, match.Title.DisplayName(), archiveStatus(match.Title)))
+		},
+	)
+	results.OnSelected = func(id widget.ListItemID) {
+		a.showTitleDetails(matches[id].TitleID)
+		results.UnselectAll()
+	}
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Search titles...")
+	searchEntry.OnChanged = func(query string) {
+		matches = a.searchTitles(query)
+		results.Refresh()
+	}
+
+	searchWindow := fyne.CurrentApp().NewWindow("Search Titles")
+	searchWindow.SetContent(container.NewBorder(searchEntry, nil, nil, nil, results))
+	searchWindow.Resize(fyne.NewSize(600, 500))
+	searchWindow.Canvas().Focus(searchEntry)
+	searchWindow.Show()
+}
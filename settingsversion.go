@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// settingsVersion is the version of the pineconeSettings.json schema written
+// by this version of Pinecone. Bump it with a migration whenever a setting is
+// renamed or changes meaning.
+const settingsVersion = 1
+
+// settingsMigrations migrate the raw settings from version i to i+1, the
+// missing "version" of files from before versioning is 0.
+var settingsMigrations = []func(raw map[string]json.RawMessage) error{
+	// 0 -> 1: the first versioned schema, nothing changed.
+	func(raw map[string]json.RawMessage) error { return nil },
+}
+
+// decodeSettings parses a settings file, migrating it from older versions.
+// Keys this version doesn't know, e.g. written by a newer version, are kept
+// in the settings and written back by saveSettings.
+func decodeSettings(data []byte) (*Settings, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	fileVersion := 0
+	if rawVersion, ok := raw["version"]; ok {
+		if err := json.Unmarshal(rawVersion, &fileVersion); err != nil {
+			return nil, fmt.Errorf("invalid version: %v", err)
+		}
+	}
+	for v := fileVersion; v < settingsVersion; v++ {
+		if err := settingsMigrations[v](raw); err != nil {
+			return nil, fmt.Errorf("migrating from version %d: %v", v, err)
+		}
+	}
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	settings := &Settings{}
+	if err := json.Unmarshal(migrated, settings); err != nil {
+		return nil, err
+	}
+	settings.Version = max(fileVersion, settingsVersion)
+	known := settingsKeys()
+	for key, value := range raw {
+		if !known[key] {
+			if settings.unknown == nil {
+				settings.unknown = make(map[string]json.RawMessage)
+			}
+			settings.unknown[key] = value
+		}
+	}
+	return settings, nil
+}
+
+// encodeSettings writes the settings as indented JSON, in the order of the
+// Settings fields followed by the keys this version doesn't know.
+func encodeSettings(settings *Settings) ([]byte, error) {
+	saved := *settings
+	saved.Version = max(saved.Version, settingsVersion)
+	data, err := json.Marshal(&saved)
+	if err != nil {
+		return nil, err
+	}
+	if len(saved.unknown) > 0 {
+		extra, err := json.Marshal(saved.unknown)
+		if err != nil {
+			return nil, err
+		}
+		data = append(bytes.TrimSuffix(data, []byte("}")), ',')
+		data = append(data, extra[1:]...)
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "    "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// settingsKeys are the JSON keys of the Settings fields.
+func settingsKeys() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(Settings{})
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// backupUnreadableSettings copies a settings file that can't be parsed aside
+// before it is overwritten, so a typo in a hand edit doesn't lose it.
+func backupUnreadableSettings(settingsPath string) {
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		return
+	}
+	if _, err := decodeSettings(data); err == nil {
+		return
+	}
+	os.WriteFile(settingsPath+".bak", data, 0o644)
+}
@@ -4,39 +4,40 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/theme"
+
+	"github.com/Xbox-Preservation-Project/Pinecone/titleid"
 )
 
 func checkDataFolder(dataFolder string) error {
 	// Ensure data folder exists
 	if _, err := os.Stat(dataFolder); os.IsNotExist(err) {
 		fmt.Println("Data folder not found. Creating...")
-		if mkDirErr := os.Mkdir(dataFolder, 0755); mkDirErr != nil {
+		if mkDirErr := os.MkdirAll(dataFolder, 0755); mkDirErr != nil {
 			return fmt.Errorf("Error creating data folder: %v", mkDirErr)
 		}
 	}
 	return nil
 }
 
-func checkDatabaseFile(jsonFilePath string, jsonURL string, updateFlag bool, window ...fyne.Window) error {
+func (a *App) checkDatabaseFile(jsonFilePath string, jsonURL string, updateFlag bool, window ...fyne.Window) error {
 	// Check if JSON file exists
 	if _, err := os.Stat(jsonFilePath); os.IsNotExist(err) {
 		// Prompt for download if JSON file doesn't exist
-		if guiEnabled {
+		if a.Config.GUI {
 			if len(window) != 1 {
-				output1 := canvas.NewText("ERROR: Your local developer did not use the a function correctly!", theme.ErrorColor())
-				output2 := canvas.NewText("Please open a GitHub issue and show them this output", theme.ErrorColor())
-				outputContainer.Add(output1)
-				outputContainer.Add(output2)
+				a.addText(theme.ErrorColor(), "ERROR: Your local developer did not use the a function correctly!")
+				a.addText(theme.ErrorColor(), "Please open a GitHub issue and show them this output")
 			}
 
-			guiShowDownloadConfirmation(window[0], jsonFilePath, jsonURL)
+			a.guiShowDownloadConfirmation(window[0], jsonFilePath, jsonURL)
 		} else {
 			if cliPromptForDownload(jsonURL) {
-				err := loadJSONData(jsonFilePath, "Xbox-Preservation-Project", "Pinecone", "data/id_database.json", &titles, true)
+				err := a.loadJSONData(jsonFilePath, "Xbox-Preservation-Project", "Pinecone", databaseRepoPath, &a.Titles, true)
 				if err != nil {
 					return fmt.Errorf("error downloading data: %v ", err)
 				}
@@ -46,26 +47,47 @@ func checkDatabaseFile(jsonFilePath string, jsonURL string, updateFlag bool, win
 		}
 	} else if updateFlag {
 		// Handle manual update
-		err := loadJSONData(jsonFilePath, "Xbox-Preservation-Project", "Pinecone", jsonFilePath, &titles, true)
+		err := a.loadJSONData(jsonFilePath, "Xbox-Preservation-Project", "Pinecone", databaseRepoPath, &a.Titles, true)
 		if err != nil {
 			return fmt.Errorf("error updating data: %v", err)
 		}
 	} else {
 		// Load existing JSON data
-		err := loadJSONData(jsonFilePath, "Xbox-Preservation-Project", "Pinecone", jsonFilePath, &titles, false)
+		err := a.loadJSONData(jsonFilePath, "Xbox-Preservation-Project", "Pinecone", databaseRepoPath, &a.Titles, false)
 		if err != nil {
 			return fmt.Errorf("error loading data: %v", err)
 		}
-		if guiEnabled {
-			guiScanDump()
+		if a.Config.GUI {
+			a.guiScanDump()
 		}
 	}
 	return nil
 }
 
-func checkDumpFolder(dumpLocation string) error {
+// scanLocations returns every dump location to scan, -location can be given
+// several times for dumps spread over multiple partitions.
+func (a *App) scanLocations() []string {
+	if len(a.Config.DumpLocations) > 0 {
+		return a.Config.DumpLocations
+	}
+	return []string{a.Config.DumpLocation}
+}
+
+func (a *App) checkDumpFolder(dumpLocation string) error {
+	if dumpLocation == stdinLocation {
+		if a.Config.TUI {
+			return fmt.Errorf("The terminal UI reads keys from stdin, it can't scan a file list from stdin")
+		}
+		return nil
+	}
+	if err := checkArchiveSupported(dumpLocation); err != nil {
+		return err
+	}
 	if dumpLocation != "dump" {
 		if _, err := os.Stat(dumpLocation); os.IsNotExist(err) {
+			if isArchive(dumpLocation) {
+				return fmt.Errorf("Archive does not exist, exiting...")
+			}
 			return fmt.Errorf("Directory does not exist, exiting...")
 		}
 	} else {
@@ -80,41 +102,59 @@ func checkDumpFolder(dumpLocation string) error {
 	return nil
 }
 
-func checkParsingSettings() error {
-	if titleIDFlag != "" {
-		// if the titleID flag is set, print stats for that title
-		printStats(titleIDFlag, false)
-	} else if summarizeFlag {
+func (a *App) checkParsingSettings() error {
+	a.resetReport()
+	if a.Config.TitleID != "" {
+		// if the titleID flag is set, print stats for that title and only
+		// scan its folders
+		titleID, err := titleid.Normalize(a.Config.TitleID)
+		if err != nil {
+			return err
+		}
+		a.printStats(titleID, false)
+		a.Config.ScanTitleID = titleID
+		printLine("Checking for Content of", displayTitleID(titleID)+"...")
+		printLine(strings.Repeat("=", headerWidth))
+		return a.runDumpScan(a.scanLocations())
+	} else if a.Config.Summarize {
 		// if the summarize flag is set, print stats for all titles
-		printStats("", true)
-	} else if fatxplorer {
+		a.printStats("", true)
+	} else if a.Config.FatXplorer {
 		if runtime.GOOS == "windows" {
 			if _, err := os.Stat(`X:\`); os.IsNotExist(err) {
 				return fmt.Errorf(`FatXplorer's X: drive not found`)
 			} else {
-				fmt.Println("Checking for Content...")
-				fmt.Println("====================================================================================================")
-				err := checkForContent("X:\\TDATA")
-				if err != nil {
-					return err
-				}
+				printLine("Checking for Content...")
+				printLine(strings.Repeat("=", headerWidth))
+				return a.runDumpScan([]string{`X:\`})
 			}
 		} else {
 			return fmt.Errorf("FatXplorer mode is only available on Windows.")
 		}
 	} else {
 		// If no flag is set, proceed normally
-		// Check if TDATA folder exists
-		if _, err := os.Stat(dumpLocation + "/TDATA"); os.IsNotExist(err) {
-			return fmt.Errorf("TDATA folder not found. Please place TDATA folder in the dump folder.")
-		}
-		fmt.Println("Checking for Content...")
-		fmt.Println("====================================================================================================")
-		err := checkForContent(dumpLocation + "/TDATA")
-		if err != nil {
-			return err
-		}
+		printLine("Checking for Content...")
+		printLine(strings.Repeat("=", headerWidth))
+		return a.runDumpScan(a.scanLocations())
+	}
+
+	return nil
+}
+
+// runDumpScan scans the locations, presenting the results for the current
+// mode and a summary, notifies the GUI user of long scans and shares the scan
+// stats if the user opted in.
+func (a *App) runDumpScan(locations []string) error {
+	started := time.Now()
+	err := runScan(&a.Report, func(events chan<- ScanEvent) error {
+		return a.scanDumpLocations(locations, events)
+	}, a.scanPresenters()...)
+	a.notifyScanFinished(time.Since(started), err)
+	if err != nil {
+		return err
 	}
 
+	a.presentScanSummary()
+	a.shareScanStats()
 	return nil
 }
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// Keyboard shortcuts of the GUI, Ctrl is Cmd on macOS. Scanning uses F5 as
+// Ctrl+S usually means save.
+var (
+	shortcutScan       = &desktop.CustomShortcut{KeyName: fyne.KeyF5}
+	shortcutSetFolder  = &desktop.CustomShortcut{KeyName: fyne.KeyO, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutSaveOutput = &desktop.CustomShortcut{KeyName: fyne.KeyE, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutSearch     = &desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierShortcutDefault}
+)
+
+// menuAction is a menu item that can also be triggered by its shortcut.
+func menuAction(w fyne.Window, label string, shortcut *desktop.CustomShortcut, action func()) *fyne.MenuItem {
+	item := fyne.NewMenuItem(label, action)
+	if shortcut == nil {
+		return item
+	}
+	item.Shortcut = shortcut
+	if shortcut.Modifier == 0 {
+		// Shortcuts without a modifier are only delivered as typed keys
+		onTypedKey := w.Canvas().OnTypedKey()
+		w.Canvas().SetOnTypedKey(func(event *fyne.KeyEvent) {
+			if event.Name == shortcut.KeyName {
+				action()
+			} else if onTypedKey != nil {
+				onTypedKey(event)
+			}
+		})
+	} else {
+		w.Canvas().AddShortcut(shortcut, func(fyne.Shortcut) {
+			action()
+		})
+	}
+	return item
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	fatihColor "github.com/fatih/color"
+	"golang.org/x/crypto/blake2b"
+)
+
+// databasePublicKey is the minisign public key database releases are signed
+// with, the base64 line of the .pub file. Release builds embed the project's
+// key with -ldflags "-X main.databasePublicKey=RW...", see on-release.yml.
+// Only builds without one, forks and local builds, use "databasePublicKey"
+// from the settings.
+var databasePublicKey = ""
+
+// signatureSuffix is appended to the database URL and path for its detached
+// minisign signature.
+const signatureSuffix = ".minisig"
+
+func (a *App) signedUpdateEnabled() bool {
+	if a.Config.SignedUpdate {
+		return true
+	}
+	settings, err := a.loadSettings()
+	return err == nil && settings.SignedDatabase
+}
+
+// databaseReleaseURL is where the signed database is downloaded from, the
+// latest release of the repository unless set in the settings. Another URL
+// is warned about, what it serves must still be signed with the release key.
+func (a *App) databaseReleaseURL(owner, repo string) string {
+	if settings, err := a.loadSettings(); err == nil && settings.DatabaseReleaseURL != "" {
+		printInfo(fatihColor.FgYellow, "Warning: downloading the database release from %s, set with databaseReleaseURL in the settings\n", settings.DatabaseReleaseURL)
+		return settings.DatabaseReleaseURL
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/releases/latest/download/id_database.json", owner, repo)
+}
+
+// releasePublicKey is the key the database is verified with. The embedded
+// release key can't be overridden, so editing the settings can't make a
+// release build trust another database. Without one, the settings' key is
+// used with a warning.
+func (a *App) releasePublicKey() string {
+	settings, err := a.loadSettings()
+	if err != nil || settings.DatabasePublicKey == "" {
+		return databasePublicKey
+	}
+	if databasePublicKey != "" {
+		if strings.TrimSpace(lastLine(settings.DatabasePublicKey)) != databasePublicKey {
+			printInfo(fatihColor.FgYellow, "Warning: ignoring databasePublicKey in the settings, this build verifies the database with the project's release key\n")
+		}
+		return databasePublicKey
+	}
+	printInfo(fatihColor.FgYellow, "Warning: verifying the database with databasePublicKey from the settings, not a release key built in\n")
+	return settings.DatabasePublicKey
+}
+
+// downloadSignedDatabase downloads the database release and its signature
+// and verifies them, nothing is returned unless the signature is valid.
+func (a *App) downloadSignedDatabase(url string) ([]byte, []byte, error) {
+	publicKey := a.releasePublicKey()
+	if publicKey == "" {
+		return nil, nil, fmt.Errorf("Error: this build has no public key to verify the database release with, set databasePublicKey in the settings")
+	}
+	jsonData, err := a.downloadReleaseAsset(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := a.downloadReleaseAsset(url + signatureSuffix)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := verifyMinisign(publicKey, jsonData, signature); err != nil {
+		return nil, nil, fmt.Errorf("Error verifying the database release, it was not installed: %v", err)
+	}
+	return jsonData, signature, nil
+}
+
+func (a *App) downloadReleaseAsset(url string) ([]byte, error) {
+	resp, err := a.httpGet(url)
+	if err != nil {
+		return nil, fmt.Errorf("Error downloading %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error downloading %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// verifyLocalDatabase checks the local database against the signature saved
+// with it, so a database replaced on disk isn't used either.
+func (a *App) verifyLocalDatabase(jsonFilePath string, jsonData []byte) error {
+	signature, err := os.ReadFile(jsonFilePath + signatureSuffix)
+	if err != nil {
+		return fmt.Errorf("Error: the database has no signature, update it with -signed-update: %v", err)
+	}
+	if err := verifyMinisign(a.releasePublicKey(), jsonData, signature); err != nil {
+		return fmt.Errorf("Error verifying the local database, update it with -signed-update: %v", err)
+	}
+	return nil
+}
+
+// verifyMinisign verifies data against a minisign signature file made with
+// the given public key. Both the legacy (Ed) and prehashed (ED, BLAKE2b-512)
+// signatures are accepted, and the trusted comment must be signed too.
+func verifyMinisign(publicKey string, data []byte, signature []byte) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lastLine(publicKey)))
+	if err != nil || len(key) != 42 || string(key[:2]) != "Ed" {
+		return fmt.Errorf("invalid minisign public key")
+	}
+	keyID, pub := key[2:10], ed25519.PublicKey(key[10:])
+
+	lines := strings.Split(strings.ReplaceAll(string(signature), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 74 {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	if !bytes.Equal(sig[2:10], keyID) {
+		return fmt.Errorf("signed with another key (%X, expected %X)", reverse(sig[2:10]), reverse(keyID))
+	}
+
+	message := data
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		hash := blake2b.Sum512(data)
+		message = hash[:]
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", sig[:2])
+	}
+	if !ed25519.Verify(pub, message, sig[10:]) {
+		return fmt.Errorf("signature doesn't match")
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(pub, append(append([]byte{}, sig[10:]...), trustedComment...), globalSig) {
+		return fmt.Errorf("trusted comment signature doesn't match")
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line, the key of a minisign .pub file
+// pasted whole.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
+
+// reverse returns b reversed, minisign shows key IDs little endian.
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"io/fs"
+	"path"
+)
+
+const kindSoundtrack = "Soundtrack"
+
+// soundtrackTitleID is the dashboard's title, its TDATA folder holds the
+// soundtracks ripped to the hard drive in music, a numbered folder each.
+const soundtrackTitleID = "fffe0000"
+
+// SoundtrackDetector lists the soundtracks on the hard drive. Most are the
+// owner's CD rips, but some promotional soundtracks only shipped on certain
+// consoles: those in the database's "Soundtracks" are reported as archived,
+// the others as unknown.
+type SoundtrackDetector struct{}
+
+func (SoundtrackDetector) Name() string { return "soundtracks" }
+
+func (SoundtrackDetector) Detect(dump Dump, events chan<- ScanEvent) error {
+	titleDir, found := findSubDir(dump.FS, dump.TDATA, soundtrackTitleID)
+	if !found {
+		return nil
+	}
+	musicDir, found := findSubDir(dump.FS, titleDir, "music")
+	if !found {
+		return nil
+	}
+
+	entries, err := fs.ReadDir(dump.FS, musicDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		dir := path.Join(musicDir, entry.Name())
+		if entry := resolveSymlink(dump.FS, dir, entry); entry == nil || !entry.IsDir() {
+			continue
+		}
+		reportSoundtrack(dump, dir, events)
+	}
+	return nil
+}
+
+// reportSoundtrack reports a soundtrack folder by its content digest, see
+// contentDigest, with the length of its tracks.
+func reportSoundtrack(dump Dump, dir string, events chan<- ScanEvent) {
+	displayedPath := displayPath(dump.Location, dir)
+	digest, reason, err := dump.App.contentDigest(dump.Location, dump.FS, dir)
+	if err != nil {
+		reportHashError(displayedPath, err, events)
+		return
+	}
+	if reason != "" {
+		emitSkipped(events, displayedPath, "soundtrack digest, "+reason)
+		return
+	}
+
+	finding := Finding{TitleID: soundtrackTitleID, TitleName: "Soundtracks", Kind: kindSoundtrack, Status: statusUnknown,
+		Path: displayedPath, Digest: digest, Media: describeMedia(dlcMedia(dump.FS, dir))}
+	if name, ok := dump.App.Titles.Soundtracks[digest]; ok {
+		finding.Status = statusArchived
+		finding.Name = name
+	}
+	dump.App.emitFinding(events, dump.Location, finding)
+}
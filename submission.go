@@ -0,0 +1,93 @@
+package main
+
+import (
+	"net/url"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	fatihColor "github.com/fatih/color"
+)
+
+// communityLinks are where finds are shared with the preservation community.
+var communityLinks = []struct {
+	Name string
+	URL  string
+}{
+	{"ConsoleMods Wiki Discord", "https://discord.gg/x5vEnkR4C8"},
+	{"Xbox-Scene Discord", "https://discord.gg/xbox-scene"},
+}
+
+// submitSteps explain what an unknown find means and how to submit it.
+var submitSteps = []string{
+	"Unknown content isn't in the Pinecone database: it may be DLC or a title update nobody has archived yet.",
+	"1. Keep the files as they are, don't delete, rename or modify them.",
+	"2. Zip the folder of each find (the content ID folder in $c, or the $u folder) as found on the drive.",
+	"3. Export a report (Markdown or HTML) so the maintainers know what you found and where.",
+	"4. Share the report on the community Discord, someone will help get the content archived.",
+}
+
+// submitHelpShown is set once the submit steps were shown, they are only
+// shown for the first unknown find of a session.
+var submitHelpShown = false
+
+// firstUnknownFind tells whether the event is the session's first unknown
+// find, dashboards are left out as they are usually modded.
+func firstUnknownFind(event ScanEvent) bool {
+	if submitHelpShown || event.Kind != EventFinding {
+		return false
+	}
+	f := event.Finding
+	if f.Status != statusUnknown || f.Kind == kindDashboard || f.Kind == kindHomebrew || f.Kind == kindSoundtrack {
+		return false
+	}
+	submitHelpShown = true
+	return true
+}
+
+// printSubmitHelp prints the submit steps after the first unknown find.
+func printSubmitHelp() {
+	printHeader("You found something!")
+	for _, step := range submitSteps {
+		printInfo(fatihColor.FgCyan, "%s\n", step)
+	}
+	for _, link := range communityLinks {
+		printInfo(fatihColor.FgCyan, "%s: %s\n", link.Name, link.URL)
+	}
+	printLine(separator)
+}
+
+// showSubmitHelp explains the first unknown find in a dialog, unless it was
+// turned off in the settings.
+func (a *App) showSubmitHelp(window fyne.Window) {
+	settings, err := a.loadSettings()
+	if err != nil {
+		settings = &Settings{}
+	}
+	if settings.HideSubmitHelp || window == nil {
+		return
+	}
+
+	content := container.NewVBox()
+	for _, step := range submitSteps {
+		label := widget.NewLabel(step)
+		label.Wrapping = fyne.TextWrapWord
+		content.Add(label)
+	}
+	for _, link := range communityLinks {
+		if linkURL, err := url.Parse(link.URL); err == nil {
+			content.Add(widget.NewHyperlink(link.Name, linkURL))
+		}
+	}
+	content.Add(widget.NewCheck("Don't show this again", func(checked bool) {
+		settings.HideSubmitHelp = checked
+		if err := a.saveSettings(settings); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}))
+
+	helpDialog := dialog.NewCustom("You found something!", "Close", content, window)
+	helpDialog.Resize(fyne.NewSize(520, 0))
+	helpDialog.Show()
+}
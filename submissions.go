@@ -0,0 +1,329 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	fatihColor "github.com/fatih/color"
+)
+
+const (
+	submissionSent   = "sent"
+	submissionFailed = "failed"
+	// maxSubmissionAttempts is how often a failed post is retried on its
+	// own, it can still be re-sent by hand after.
+	maxSubmissionAttempts = 10
+	// submissionRetryInterval is how often the GUI retries failed posts.
+	submissionRetryInterval = 15 * time.Minute
+	// keptSubmissions is how many posts the log keeps, the oldest are
+	// dropped unless they are still retried.
+	keptSubmissions = 100
+)
+
+// Submission is a report posted to a webhook, as kept in the submission log.
+// The webhook's URL holds its token, only its host and a hash of it are
+// logged: a failed post is sent again to the webhook currently set if it's
+// the same. Body is the exact payload posted, kept until it went through.
+type Submission struct {
+	Time      time.Time `json:"time"`
+	Webhook   string    `json:"webhook"`
+	WebhookID string    `json:"webhookId"`
+	// URL is the full webhook URL older versions logged, replaced by
+	// Webhook and WebhookID when the log is read.
+	URL      string          `json:"url,omitempty"`
+	ReportID string          `json:"reportId,omitempty"`
+	Hashes   []string        `json:"hashes"`
+	Status   string          `json:"status"`
+	Error    string          `json:"error,omitempty"`
+	Attempts int             `json:"attempts"`
+	Body     json.RawMessage `json:"body,omitempty"`
+}
+
+// webhookID identifies a webhook URL in the submission log without its
+// token.
+func webhookID(url string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(url)))[:16]
+}
+
+// submissionsMu guards the submission log, the GUI can re-send while a scan
+// posts its results.
+var submissionsMu sync.Mutex
+
+func (a *App) submissionsPath() string {
+	return filepath.Join(a.Config.DataPath, "submissions.json")
+}
+
+// loadSubmissions reads the submission log, oldest first. A log of an older
+// version holding webhook URLs is rewritten without them.
+func (a *App) loadSubmissions() ([]Submission, error) {
+	data, err := os.ReadFile(a.submissionsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Error reading the submission log: %v", err)
+	}
+	var submissions []Submission
+	if err := json.Unmarshal(data, &submissions); err != nil {
+		return nil, fmt.Errorf("Error reading the submission log: %v", err)
+	}
+	redacted := false
+	for i := range submissions {
+		if s := &submissions[i]; s.URL != "" {
+			s.Webhook, s.WebhookID, s.URL = hostOf(s.URL), webhookID(s.URL), ""
+			redacted = true
+		}
+	}
+	if redacted {
+		if err := a.saveSubmissions(submissions); err != nil {
+			return nil, err
+		}
+	}
+	return submissions, nil
+}
+
+// pruneSubmissions drops the bodies of the posts that went through and the
+// oldest posts past keptSubmissions, as the scan logs are pruned. Posts
+// still retried are kept.
+func pruneSubmissions(submissions []Submission) []Submission {
+	drop := len(submissions) - keptSubmissions
+	kept := submissions[:0]
+	for _, s := range submissions {
+		if s.Status == submissionSent {
+			s.Body = nil
+		}
+		if drop > 0 && (s.Status != submissionFailed || s.Attempts >= maxSubmissionAttempts) {
+			drop--
+			continue
+		}
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+// saveSubmissions writes the submission log through a temporary file, so a
+// crash can't leave half a log. It's only readable by the user, the bodies
+// list the paths of the dump.
+func (a *App) saveSubmissions(submissions []Submission) error {
+	data, err := json.MarshalIndent(pruneSubmissions(submissions), "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(a.Config.DataPath, 0o755); err != nil {
+		return fmt.Errorf("Error writing the submission log: %v", err)
+	}
+	tmpPath := a.submissionsPath() + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("Error writing the submission log: %v", err)
+	}
+	return os.Rename(tmpPath, a.submissionsPath())
+}
+
+// logSubmission adds a post to the submission log.
+func (a *App) logSubmission(s Submission) error {
+	submissionsMu.Lock()
+	defer submissionsMu.Unlock()
+	submissions, err := a.loadSubmissions()
+	if err != nil {
+		return err
+	}
+	return a.saveSubmissions(append(submissions, s))
+}
+
+// submissionHashes identifies the items a report submits: the hash of
+// updates, the content ID of DLC and the digest of soundtracks.
+func submissionHashes(report *Report) []string {
+	hashes := []string{}
+	for _, f := range report.Findings {
+		if key := f.key(); key != "" {
+			hashes = append(hashes, key)
+		}
+	}
+	return hashes
+}
+
+// submitWebhook posts a payload and records the post in the submission log.
+func (a *App) submitWebhook(settings *Settings, url string, payload WebhookPayload, reportID string, hashes []string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	s := Submission{Time: time.Now(), Webhook: hostOf(url), WebhookID: webhookID(url), ReportID: reportID, Hashes: hashes,
+		Status: submissionSent, Attempts: 1, Body: body}
+	postErr := postWebhookBody(settings, url, body)
+	if postErr != nil {
+		s.Status, s.Error = submissionFailed, postErr.Error()
+	}
+	if err := a.logSubmission(s); err != nil {
+		printInfo(fatihColor.FgYellow, "%v\n", err)
+	}
+	return postErr
+}
+
+// resendSubmission posts the submission logged at index again and records
+// the outcome.
+func (a *App) resendSubmission(settings *Settings, index int) error {
+	submissionsMu.Lock()
+	defer submissionsMu.Unlock()
+	submissions, err := a.loadSubmissions()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(submissions) {
+		return fmt.Errorf("submission not found")
+	}
+	url := a.resolveWebhookURL()
+	if s := submissions[index]; url == "" || webhookID(url) != s.WebhookID {
+		return fmt.Errorf("it was posted to another webhook on %s, set it with --webhook or PINECONE_WEBHOOK to re-send it", s.Webhook)
+	}
+
+	postErr := sendSubmission(settings, url, &submissions[index])
+	if err := a.saveSubmissions(submissions); err != nil {
+		return err
+	}
+	return postErr
+}
+
+// sendSubmission posts a logged submission again to url, its webhook, and
+// records the outcome in it.
+func sendSubmission(settings *Settings, url string, s *Submission) error {
+	err := postWebhookBody(settings, url, s.Body)
+	s.Time = time.Now()
+	s.Attempts++
+	if err != nil {
+		s.Status, s.Error = submissionFailed, err.Error()
+	} else {
+		s.Status, s.Error = submissionSent, ""
+	}
+	return err
+}
+
+// retrySubmissions is the retry queue: it posts the failed submissions to the
+// webhook currently set again, up to maxSubmissionAttempts each, so posts
+// that failed while offline or rate limited aren't lost. Returns how many
+// went through and how many still fail.
+func (a *App) retrySubmissions(settings *Settings) (int, int, error) {
+	submissionsMu.Lock()
+	defer submissionsMu.Unlock()
+	submissions, err := a.loadSubmissions()
+	if err != nil {
+		return 0, 0, err
+	}
+	url := a.resolveWebhookURL()
+	if url == "" {
+		return 0, 0, nil
+	}
+	sent, failed := 0, 0
+	for i := range submissions {
+		s := &submissions[i]
+		if s.Status != submissionFailed || s.Attempts >= maxSubmissionAttempts || s.WebhookID != webhookID(url) {
+			continue
+		}
+		if sendSubmission(settings, url, s) != nil {
+			failed++
+		} else {
+			sent++
+		}
+	}
+	if sent == 0 && failed == 0 {
+		return 0, 0, nil
+	}
+	return sent, failed, a.saveSubmissions(submissions)
+}
+
+// retrySubmissionsLater retries the failed submissions in the background at
+// GUI start and every submissionRetryInterval.
+func (a *App) retrySubmissionsLater() {
+	go func() {
+		for {
+			settings, err := a.loadSettings()
+			if err != nil {
+				settings = &Settings{}
+			}
+			if _, _, err := a.retrySubmissions(settings); err != nil {
+				fmt.Println(err)
+			}
+			time.Sleep(submissionRetryInterval)
+		}
+	}()
+}
+
+// submissionLine describes a logged submission.
+func submissionLine(s Submission) string {
+	line := fmt.Sprintf("%s  %s to %s, %d item(s)", s.Time.Format("2006-01-02 15:04:05"), s.Status, s.Webhook, len(s.Hashes))
+	if s.ReportID != "" {
+		line += ", report " + s.ReportID
+	}
+	if s.Error != "" {
+		line += ": " + s.Error
+	}
+	return line
+}
+
+// submissionsView is the Submissions tab: the submission log, newest first,
+// with failed posts ready to be sent again.
+func (a *App) submissionsView(parent fyne.Window) fyne.CanvasObject {
+	var submissions []Submission
+	status := widget.NewLabel("")
+	reload := func() {
+		submissionsMu.Lock()
+		loaded, err := a.loadSubmissions()
+		submissionsMu.Unlock()
+		submissions = loaded
+		switch {
+		case err != nil:
+			status.SetText(err.Error())
+		case len(submissions) == 0:
+			status.SetText("Nothing was submitted yet. Scan results are submitted to the webhook set with --webhook or PINECONE_WEBHOOK.")
+		default:
+			status.SetText(fmt.Sprintf("%d submission(s), logged in %s", len(submissions), a.submissionsPath()))
+		}
+	}
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int {
+			return len(submissions)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButton("Re-send", nil), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			// Newest first
+			index := len(submissions) - 1 - id
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(submissionLine(submissions[index]))
+			resend := row.Objects[1].(*widget.Button)
+			if submissions[index].Status != submissionFailed {
+				resend.Hide()
+				return
+			}
+			resend.Show()
+			resend.OnTapped = func() {
+				resend.Disable()
+				go func() {
+					defer resend.Enable()
+					settings, err := a.loadSettings()
+					if err != nil {
+						settings = &Settings{}
+					}
+					if err := a.resendSubmission(settings, index); err != nil {
+						dialog.ShowError(fmt.Errorf("Could not re-send the submission: %v", err), parent)
+					}
+					reload()
+					list.Refresh()
+				}()
+			}
+		},
+	)
+	reload()
+	return container.NewBorder(status, nil, nil, nil, list)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSubmitAndResendWebhook(t *testing.T) {
+	a := newTestApp(t, TitleList{})
+	up := false
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	settings := &Settings{}
+	payload := WebhookPayload{Content: "1 unknown"}
+	if err := a.submitWebhook(settings, server.URL, payload, "report-1", []string{"aa"}); err == nil {
+		t.Fatal("post to a failing webhook succeeded")
+	}
+	submissions, err := a.loadSubmissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(submissions) != 1 || submissions[0].Status != submissionFailed || submissions[0].ReportID != "report-1" ||
+		!slices.Equal(submissions[0].Hashes, []string{"aa"}) || submissions[0].Error == "" {
+		t.Fatalf("logged %+v, want the failed post", submissions)
+	}
+
+	up = true
+	if err := a.resendSubmission(settings, 0); err == nil {
+		t.Fatal("re-sent without the webhook set")
+	}
+	a.Config.WebhookURL = server.URL
+	if err := a.resendSubmission(settings, 0); err != nil {
+		t.Fatal(err)
+	}
+	submissions, err = a.loadSubmissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(submissions) != 1 || submissions[0].Status != submissionSent || submissions[0].Error != "" || submissions[0].Body != nil {
+		t.Errorf("logged %+v after re-sending, want it sent without its body", submissions)
+	}
+	if posts < 2 {
+		t.Errorf("got %d posts, want the post and the re-send", posts)
+	}
+}
+
+func TestRetrySubmissions(t *testing.T) {
+	a := newTestApp(t, TitleList{})
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+	}))
+	defer server.Close()
+
+	a.Config.WebhookURL = server.URL
+	id := webhookID(server.URL)
+	err := a.saveSubmissions([]Submission{
+		{WebhookID: id, Status: submissionFailed, Attempts: 1, Body: []byte(`{"content":"a"}`)},
+		{WebhookID: id, Status: submissionFailed, Attempts: maxSubmissionAttempts, Body: []byte(`{"content":"b"}`)},
+		{WebhookID: id, Status: submissionSent, Attempts: 1, Body: []byte(`{"content":"c"}`)},
+		{WebhookID: webhookID("https://example.com/other"), Status: submissionFailed, Attempts: 1, Body: []byte(`{"content":"d"}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent, failed, err := a.retrySubmissions(&Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || failed != 0 || posts != 1 {
+		t.Errorf("retried %d sent, %d failed with %d posts, want only the first one sent", sent, failed, posts)
+	}
+	submissions, err := a.loadSubmissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submissions[0].Status != submissionSent || submissions[0].Attempts != 2 || submissions[1].Status != submissionFailed {
+		t.Errorf("logged %+v after retrying", submissions)
+	}
+}
+
+func TestSubmissionLogRedactsAndPrunes(t *testing.T) {
+	a := newTestApp(t, TitleList{})
+	secret := "https://discord.com/api/webhooks/1/secret-token"
+	submissions := []Submission{{URL: secret, Status: submissionFailed, Attempts: 1, Body: []byte(`{"content":"a"}`)}}
+	for i := 0; i < keptSubmissions+10; i++ {
+		submissions = append(submissions, Submission{Webhook: "discord.com", Status: submissionSent, Attempts: 1, Body: []byte(`{"content":"b"}`)})
+	}
+	if err := a.saveSubmissions(submissions); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := a.loadSubmissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != keptSubmissions {
+		t.Errorf("kept %d submissions, want %d", len(loaded), keptSubmissions)
+	}
+	if s := loaded[0]; s.URL != "" || s.Webhook != "discord.com" || s.WebhookID != webhookID(secret) || s.Body == nil {
+		t.Errorf("failed post logged as %+v, want it kept with the URL replaced", s)
+	}
+	for _, s := range loaded[1:] {
+		if s.Body != nil {
+			t.Fatalf("sent post kept its body: %+v", s)
+		}
+	}
+	data, err := os.ReadFile(a.submissionsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Error("the submission log still holds the webhook token")
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2/theme"
+	fatihColor "github.com/fatih/color"
+)
+
+// scanSummaryLines sums up a scan: what was checked, every item worth
+// submitting and what to do next, so the conclusion isn't left in the
+// scrollback.
+func scanSummaryLines(report *Report) []string {
+	verified := 0
+	for _, f := range report.Findings {
+		if f.Kind == kindUpdate && f.Status == statusArchived {
+			verified++
+		}
+	}
+	interesting := report.Interesting().Findings
+
+	lines := []string{
+		fmt.Sprintf("%d titles scanned", len(report.Scanned)),
+		fmt.Sprintf("%d known updates verified, %d archived items in total", verified, report.Count(statusArchived)),
+		fmt.Sprintf("%d unknown or unarchived items", len(interesting)),
+	}
+	for _, f := range interesting {
+		lines = append(lines, fmt.Sprintf("  %s: %s %s at %s", f.TitleName, f.Status, f.Kind, f.Path))
+	}
+	if len(report.UnknownTitles) > 0 {
+		lines = append(lines, fmt.Sprintf("%d titles missing from the database", len(report.UnknownTitles)))
+	}
+	for _, u := range report.UnknownTitles {
+		lines = append(lines, fmt.Sprintf("  %s: missing from the database at %s", displayTitleID(u.TitleID), u.Path))
+	}
+	if fingerprint := report.Fingerprint(); fingerprint != "" {
+		lines = append(lines, "Dump fingerprint: "+fingerprint)
+	}
+	if report.ID != "" {
+		lines = append(lines, fmt.Sprintf("Report ID: %s, %s", report.ID, report.Producer()))
+	}
+	if len(report.Errors) > 0 {
+		lines = append(lines, fmt.Sprintf("%d files couldn't be checked, see the errors above", len(report.Errors)))
+	}
+
+	if len(interesting) == 0 && len(report.UnknownTitles) == 0 {
+		return append(lines, "Nothing to submit, everything found is already archived. Thanks for checking!")
+	}
+	lines = append(lines, "Next steps:")
+	lines = append(lines, submitSteps[1:]...)
+	for _, link := range communityLinks {
+		lines = append(lines, fmt.Sprintf("%s: %s", link.Name, link.URL))
+	}
+	return lines
+}
+
+// presentScanSummary prints the summary of the last scan, and adds it to the
+// GUI output.
+func (a *App) presentScanSummary() {
+	lines := scanSummaryLines(&a.Report)
+	printHeader("Summary")
+	for _, line := range lines {
+		printInfo(fatihColor.FgCyan, "%s\n", line)
+	}
+	printLine(separator)
+
+	if a.Config.GUI {
+		a.addHeader("Summary")
+		for _, line := range lines {
+			a.addText(theme.ForegroundColor(), "%s", line)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// SystemTitle is a title ID used by the system rather than a game, e.g. the
+// dashboard's, whose TDATA folder holds system data instead of content to
+// preserve. Listed in the database's "System Titles" section.
+type SystemTitle struct {
+	Name        string `json:"Name"`
+	Description string `json:"Description,omitempty"` // what its folder holds
+}
+
+// SystemTitleFolder is the TDATA folder of a system title, listed in reports
+// with what it holds.
+type SystemTitleFolder struct {
+	TitleID     string
+	Name        string
+	Description string
+	Location    string
+	Path        string
+	Content     []string // folders in $c
+	Updates     []string // files in $u
+	Folders     []string // other folders, e.g. the dashboard's music
+}
+
+// defaultSystemTitles are used when the database has no "System Titles".
+var defaultSystemTitles = map[string]SystemTitle{
+	"fffe0000": {Name: "Xbox Dashboard", Description: "soundtracks ripped to the hard drive and dashboard settings"},
+}
+
+// systemTitle returns the system title of a title ID: from the database,
+// the defaults or, for the publisher codes 0xFFFE and 0xFFFF reserved for
+// system software, an unnamed one.
+func (a *App) systemTitle(titleID string) (SystemTitle, bool) {
+	if system, ok := a.Titles.SystemTitles[titleID]; ok {
+		return system, true
+	}
+	if a.Titles.SystemTitles == nil {
+		if system, ok := defaultSystemTitles[titleID]; ok {
+			return system, true
+		}
+	}
+	if strings.HasPrefix(titleID, "fffe") || strings.HasPrefix(titleID, "ffff") {
+		return SystemTitle{}, true
+	}
+	return SystemTitle{}, false
+}
+
+// Contents summarizes what the folder holds, e.g. "2 content, 1 update" or
+// "music folder".
+func (s SystemTitleFolder) Contents() string {
+	contents := folderContents(s.Content, s.Updates)
+	if len(s.Folders) == 0 {
+		return contents
+	}
+	folders := strings.Join(s.Folders, ", ") + " folder"
+	if len(s.Folders) > 1 {
+		folders += "s"
+	}
+	if len(s.Content) == 0 && len(s.Updates) == 0 {
+		return folders
+	}
+	return contents + ", " + folders
+}
+
+// checkSystemTitle lists what the folder of a system title holds. It isn't
+// checked against the database, it's no game's content.
+func checkSystemTitle(fsys fs.FS, titleDir string, titleID string, system SystemTitle, location string, events chan<- ScanEvent) error {
+	folder := &SystemTitleFolder{TitleID: titleID, Name: system.Name, Description: system.Description, Location: location,
+		Path: displayPath(location, titleDir)}
+	var err error
+	if folder.Content, folder.Updates, err = listTitleFolder(fsys, titleDir); err != nil {
+		return err
+	}
+	entries, err := fs.ReadDir(fsys, titleDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry := resolveSymlink(fsys, path.Join(titleDir, name), entry); entry != nil && entry.IsDir() && !strings.EqualFold(name, "$c") && !strings.EqualFold(name, "$u") {
+			folder.Folders = append(folder.Folders, name)
+		}
+	}
+	events <- ScanEvent{Kind: EventSystemTitle, TitleID: titleID, Location: folder.Location, SystemTitle: folder}
+	return nil
+}
+
+// systemTitleMessage is the line presenters show for a system title folder.
+func systemTitleMessage(s *SystemTitleFolder) string {
+	message := "System title " + s.TitleID
+	if s.Name != "" {
+		message += fmt.Sprintf(" (%s)", s.Name)
+	}
+	if s.Description != "" {
+		message += ", " + s.Description
+	}
+	return fmt.Sprintf("%s: %s at %s", message, s.Contents(), s.Path)
+}
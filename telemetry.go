@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	fatihColor "github.com/fatih/color"
+)
+
+// ScanStats is everything sent when sharing scan stats: aggregate counts
+// only, no paths, hashes, title IDs or user info.
+type ScanStats struct {
+	Version       string `json:"version"`
+	TitlesScanned int    `json:"titlesScanned"`
+	Unknown       int    `json:"unknown"`
+	Unarchived    int    `json:"unarchived"`
+	Archived      int    `json:"archived"`
+}
+
+func scanStats(report *Report) ScanStats {
+	titlesScanned := 0
+	for _, title := range report.Titles() {
+		if kind := title.Findings[0].Kind; kind != kindDashboard && kind != kindHomebrew && kind != kindDevkit && kind != kindSoundtrack {
+			titlesScanned++
+		}
+	}
+	return ScanStats{
+		Version:       report.Version,
+		TitlesScanned: titlesScanned,
+		Unknown:       report.Count(statusUnknown),
+		Unarchived:    report.Count(statusUnarchived),
+		Archived:      report.Count(statusArchived),
+	}
+}
+
+// shareScanStats posts the counts of the last scan to the stats URL, only if
+// the user opted in. Failures are printed and never affect the scan.
+func (a *App) shareScanStats() {
+	settings, err := a.loadSettings()
+	if err != nil || !settings.ShareStats || settings.ShareStatsURL == "" {
+		return
+	}
+
+	err = postScanStats(settings, scanStats(&a.Report))
+	if err != nil {
+		printInfo(fatihColor.FgYellow, "Could not share scan stats: %v\n", err)
+	}
+}
+
+func postScanStats(settings *Settings, stats ScanStats) error {
+	body, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	client, err := newHTTPClient(settings)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(settings.ShareStatsURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response from %s: %s", settings.ShareStatsURL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// reportTemplateFuncs are the helpers available to report templates, along
+// with titleMetadata, see loadReportTemplate.
+var reportTemplateFuncs = template.FuncMap{
+	"statusLabel":    statusLabel,
+	"displayTitleID": displayTitleID,
+	"offeringColumn": offeringColumn,
+	"modifiedColumn": modifiedColumn,
+	"markdownEscape": markdownEscape,
+	"join":           strings.Join,
+	"upper":          strings.ToUpper,
+	"lower":          strings.ToLower,
+}
+
+// ReportTemplateData is what a report template is executed with.
+type ReportTemplateData struct {
+	Report *Report
+	// Interesting holds only the unknown and unarchived findings.
+	Interesting *Report
+	// Archived holds only the archived findings.
+	Archived *Report
+	// KnownBad holds only the findings in the known bad list.
+	KnownBad *Report
+	// NotFound lists the titles scanned without any findings.
+	NotFound []ScannedTitle
+	// Prototypes holds the findings with hints of a prototype build.
+	Prototypes []Finding
+	// Credit is the credit line from the user's contact settings. The rest
+	// of the settings, e.g. tokens and keys, is left out: reports are shared
+	// publicly.
+	Credit string
+}
+
+// reportTemplateFile returns the configured report template, the flag taking
+// precedence over the settings. Empty for the built-in report.
+func (a *App) reportTemplateFile(settings *Settings) string {
+	if a.Config.ReportTemplate != "" {
+		return a.Config.ReportTemplate
+	}
+	return settings.ReportTemplate
+}
+
+// loadReportTemplate parses a report template with reportTemplateFuncs and
+// titleMetadata, which looks titles up in the App's database.
+func (a *App) loadReportTemplate(templatePath string) (*template.Template, error) {
+	data, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading report template: %v", err)
+	}
+	tmpl, err := template.New(templatePath).Funcs(reportTemplateFuncs).Funcs(template.FuncMap{"titleMetadata": a.titleMetadata}).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing report template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// writeTemplateReport renders the report with a user template.
+func (a *App) writeTemplateReport(w io.Writer, templatePath string, report *Report, settings *Settings) error {
+	tmpl, err := a.loadReportTemplate(templatePath)
+	if err != nil {
+		return err
+	}
+	err = tmpl.Execute(w, ReportTemplateData{
+		Report:      report,
+		Interesting: report.Interesting(),
+		Archived:    report.Archived(),
+		KnownBad:    report.KnownBad(),
+		NotFound:    report.NotFound(),
+		Prototypes:  report.PossiblePrototypes(),
+		Credit:      creditBlock(report, settings),
+	})
+	if err != nil {
+		return fmt.Errorf("Error executing report template: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package main
+
+import "errors"
+
+func makeRaw(fd int) (func(), error) {
+	return nil, errors.New("the terminal UI is not supported on this platform")
+}
+
+func terminalSize(fd int) (int, int, error) {
+	return 0, 0, errors.New("the terminal UI is not supported on this platform")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+const (
+	minFontScale = 0.8
+	maxFontScale = 2.5
+)
+
+var (
+	highContrast = false
+
+	// Colorblind friendly palette for the high-contrast mode (Okabe-Ito).
+	highContrastGood  = color.RGBA{86, 180, 233, 255}
+	highContrastWarn  = color.RGBA{240, 228, 66, 255}
+	highContrastError = color.RGBA{230, 159, 0, 255}
+)
+
+// pineconeTheme wraps the default theme to apply the accessibility settings.
+type pineconeTheme struct {
+	fyne.Theme
+	fontScale    float32
+	highContrast bool
+}
+
+func newPineconeTheme(settings *Settings) *pineconeTheme {
+	fontScale := settings.FontScale
+	if fontScale < minFontScale || fontScale > maxFontScale {
+		fontScale = 1
+	}
+	return &pineconeTheme{
+		Theme:        theme.DefaultTheme(),
+		fontScale:    fontScale,
+		highContrast: settings.HighContrast,
+	}
+}
+
+func (t *pineconeTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if t.highContrast {
+		switch name {
+		case theme.ColorNameBackground, theme.ColorNameInputBackground, theme.ColorNameMenuBackground, theme.ColorNameOverlayBackground:
+			return color.Black
+		case theme.ColorNameForeground:
+			return color.White
+		case theme.ColorNamePrimary, theme.ColorNameFocus:
+			return highContrastWarn
+		case theme.ColorNameError:
+			return highContrastError
+		case theme.ColorNameSuccess:
+			return highContrastGood
+		case theme.ColorNameWarning:
+			return highContrastWarn
+		}
+	}
+	return t.Theme.Color(name, variant)
+}
+
+func (t *pineconeTheme) Size(name fyne.ThemeSizeName) float32 {
+	switch name {
+	case theme.SizeNameText, theme.SizeNameHeadingText, theme.SizeNameSubHeadingText, theme.SizeNameCaptionText:
+		return t.Theme.Size(name) * t.fontScale
+	}
+	return t.Theme.Size(name)
+}
+
+func applyTheme(a fyne.App, settings *Settings) {
+	highContrast = settings.HighContrast
+	a.Settings().SetTheme(newPineconeTheme(settings))
+}
+
+// Status colors used in the output, swapped for the colorblind friendly
+// palette when high-contrast mode is enabled.
+func guiGoodColor() color.Color {
+	if highContrast {
+		return highContrastGood
+	}
+	return theme.PrimaryColorNamed(theme.ColorGreen)
+}
+
+func guiWarnColor() color.Color {
+	if highContrast {
+		return highContrastWarn
+	}
+	return theme.PrimaryColorNamed(theme.ColorYellow)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// defaultThumbnailURL is the project's image store, %s is the content ID.
+const defaultThumbnailURL = "https://raw.githubusercontent.com/MrMilenko/Pinecone/main/data/thumbnails/%s.png"
+
+var (
+	thumbnailMu sync.Mutex
+	// thumbnailMisses are content IDs without a thumbnail in the store, so
+	// they are only requested once per session.
+	thumbnailMisses = make(map[string]bool)
+)
+
+// thumbnailsEnabled tells whether thumbnails are fetched, with -thumbnails or
+// in the settings.
+func (a *App) thumbnailsEnabled() bool {
+	if a.Config.Thumbnails {
+		return true
+	}
+	settings, err := a.loadSettings()
+	return err == nil && settings.Thumbnails
+}
+
+func (a *App) thumbnailCachePath(contentID string) string {
+	return filepath.Join(a.Config.DataPath, "thumbnails", strings.ToLower(contentID)+".png")
+}
+
+// fetchThumbnail returns the cached thumbnail of a content ID, downloading it
+// from the image store first if needed. It returns "" if the store has none.
+func (a *App) fetchThumbnail(contentID string) (string, error) {
+	contentID = strings.ToLower(contentID)
+	cachePath := a.thumbnailCachePath(contentID)
+	if _, err := os.Stat(cachePath); err == nil {
+		return cachePath, nil
+	}
+
+	thumbnailMu.Lock()
+	defer thumbnailMu.Unlock()
+	if thumbnailMisses[contentID] {
+		return "", nil
+	}
+
+	source := defaultThumbnailURL
+	if settings, err := a.loadSettings(); err == nil && settings.ThumbnailURL != "" {
+		source = settings.ThumbnailURL
+	}
+	resp, err := a.httpGet(fmt.Sprintf(source, contentID))
+	if err != nil {
+		return "", fmt.Errorf("Error downloading thumbnail: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		thumbnailMisses[contentID] = true
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Error downloading thumbnail: %s", resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("Error downloading thumbnail: %v", err)
+	}
+	if err := os.WriteFile(cachePath, data, 0o644); err != nil {
+		return "", err
+	}
+	return cachePath, nil
+}
+
+// thumbnailDataURI embeds a cached thumbnail in HTML reports, "" if it isn't
+// cached. Reports never download thumbnails themselves.
+func (a *App) thumbnailDataURI(contentID string) template.URL {
+	if contentID == "" {
+		return ""
+	}
+	data, err := os.ReadFile(a.thumbnailCachePath(contentID))
+	if err != nil {
+		return ""
+	}
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(data))
+}
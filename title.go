@@ -1,13 +1,102 @@
 package main
 
+import (
+	"fmt"
+	"strings"
+)
+
+// databaseSchemaVersion is the newest database schema this version reads.
+// Version 2 added the optional title metadata, a database without "Schema
+// Version" is version 1.
+const databaseSchemaVersion = 2
+
+// wantedFlags are the values of a title's "Wanted" list, what the project is
+// still looking for.
+var wantedFlags = []string{"content", "updates", "saves", "media"}
+
 type TitleData struct {
 	TitleName         string              `json:"Title Name,"`
 	ContentIDs        []string            `json:"Content IDs"`
 	TitleUpdates      []string            `json:"Title Updates"`
 	TitleUpdatesKnown []map[string]string `json:"Title Updates Known"`
 	Archived          []map[string]string `json:"Archived"`
+	// LocalizedNames are the title's names by language code ("ja", "de",
+	// "fr"), see DisplayName.
+	LocalizedNames map[string]string `json:"Localized Names,omitempty"`
+
+	// Schema version 2 metadata, all optional.
+	Region      string   `json:"Region,omitempty"` // e.g. "NTSC-U", "PAL" or "Region Free"
+	ReleaseYear int      `json:"Release Year,omitempty"`
+	Publisher   string   `json:"Publisher,omitempty"`
+	MediaIDs    []string `json:"Media IDs,omitempty"` // disc media IDs, e.g. "MS-071 (1)"
+	Notes       string   `json:"Notes,omitempty"`
+	Wanted      []string `json:"Wanted,omitempty"` // see wantedFlags
 }
 
 type TitleList struct {
-	Titles map[string]TitleData `json:"Titles"`
+	// SchemaVersion is the database schema version, 1 if missing.
+	SchemaVersion int                  `json:"Schema Version,omitempty"`
+	Titles        map[string]TitleData `json:"Titles"`
+	Dashboards    map[string]string    `json:"Dashboards,omitempty"` // SHA1 -> dashboard version
+	// KnownBad are corrupt or fake files circulating in the community, SHA1
+	// -> what is wrong with them, so they aren't taken for unknown updates.
+	KnownBad map[string]string `json:"Known Bad,omitempty"`
+	// WantedSaves are promo/unlock saves looked for, per title ID.
+	WantedSaves map[string][]WantedSave `json:"Wanted Saves,omitempty"`
+	// Soundtracks are promotional soundtracks, content digest -> name, see
+	// SoundtrackDetector.
+	Soundtracks map[string]string `json:"Soundtracks,omitempty"`
+	// SystemTitles are title IDs used by the system, see SystemTitle.
+	SystemTitles map[string]SystemTitle `json:"System Titles,omitempty"`
+	// QuickHashes are the quick hashes of large known files, quick hash ->
+	// SHA1, see quickHash.
+	QuickHashes map[string]string `json:"Quick Hashes,omitempty"`
+
+	// updateIndex and contentIndex are the reverse indexes, see buildIndexes.
+	updateIndex  map[string][]IndexEntry
+	contentIndex map[string][]IndexEntry
+}
+
+// Metadata summarizes the schema version 2 metadata of a title, e.g.
+// "NTSC-U, 2004, Microsoft Game Studios, media MS-071 (1); wanted: saves".
+// Empty if the database has none.
+func (t TitleData) Metadata() string {
+	var parts []string
+	if t.Region != "" {
+		parts = append(parts, t.Region)
+	}
+	if t.ReleaseYear != 0 {
+		parts = append(parts, fmt.Sprint(t.ReleaseYear))
+	}
+	if t.Publisher != "" {
+		parts = append(parts, t.Publisher)
+	}
+	if len(t.MediaIDs) > 0 {
+		parts = append(parts, "media "+strings.Join(t.MediaIDs, ", "))
+	}
+	metadata := strings.Join(parts, ", ")
+	if len(t.Wanted) > 0 {
+		if metadata != "" {
+			metadata += "; "
+		}
+		metadata += "wanted: " + strings.Join(t.Wanted, ", ")
+	}
+	return metadata
+}
+
+// titleMetadata returns the metadata and notes of a title in the loaded
+// database, for reports.
+func (a *App) titleMetadata(titleID string) string {
+	titleData, ok := a.Titles.Titles[titleID]
+	if !ok {
+		return ""
+	}
+	metadata := titleData.Metadata()
+	if titleData.Notes != "" {
+		if metadata != "" {
+			metadata += ". "
+		}
+		metadata += titleData.Notes
+	}
+	return metadata
 }
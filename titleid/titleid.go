@@ -0,0 +1,173 @@
+// Package titleid validates, normalizes and decodes Xbox title IDs.
+//
+// A title ID is 32 bits written as 8 hex digits. The upper 16 bits are two
+// ASCII characters identifying the publisher (0x4d53 = "MS" = Microsoft) and
+// the lower 16 bits are the game number, so 4d530064 decodes to MS-100.
+package titleid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// publishers maps publisher codes to names.
+var publishers = map[string]string{
+	"AC": "Acclaim",
+	"AH": "ARUSH Entertainment",
+	"AQ": "Aqua System",
+	"AS": "ASK",
+	"AT": "Atlus",
+	"AV": "Activision",
+	"AY": "Aspyr",
+	"BA": "Bandai",
+	"BL": "Black Box",
+	"BM": "BAM! Entertainment",
+	"BR": "Broccoli",
+	"BS": "Bethesda Softworks",
+	"BU": "Bunkasha Games",
+	"BV": "Buena Vista Games",
+	"BW": "BBC Multimedia",
+	"BZ": "Blizzard",
+	"CC": "Capcom",
+	"CK": "Kemco",
+	"CM": "Codemasters",
+	"CV": "Crave Entertainment",
+	"DC": "DreamCatcher Interactive",
+	"DX": "Davilex",
+	"EA": "Electronic Arts",
+	"EC": "Encore",
+	"EL": "Enlight Software",
+	"EM": "Empire Interactive",
+	"ES": "Eidos Interactive",
+	"FI": "Fox Interactive",
+	"FS": "From Software",
+	"GE": "Genki",
+	"GV": "Groove Games",
+	"HE": "Tru Blu Entertainment",
+	"HP": "Hip Games",
+	"HU": "Hudson Soft",
+	"HW": "Highwaystar",
+	"IA": "Mad Catz Interactive",
+	"IF": "Idea Factory",
+	"IG": "Infogrames",
+	"IL": "Interlex",
+	"IM": "Imagine Media",
+	"IO": "Ignition Entertainment",
+	"IP": "Interplay Entertainment",
+	"IX": "InXile Entertainment",
+	"JA": "Jaleco",
+	"JW": "JoWooD",
+	"KB": "Kemco",
+	"KI": "Kids Station",
+	"KN": "Konami",
+	"KO": "Koei",
+	"KU": "Kobi",
+	"KY": "Kemco",
+	"LA": "LucasArts",
+	"LS": "Black Bean Games",
+	"MD": "Metro3D",
+	"ME": "Medix",
+	"MI": "Microids",
+	"MJ": "Majesco Entertainment",
+	"MM": "Myelin Media",
+	"MP": "MediaQuest",
+	"MS": "Microsoft",
+	"MW": "Midway",
+	"MX": "Empire Interactive",
+	"NK": "NewKidCo",
+	"NL": "NovaLogic",
+	"NM": "Namco",
+	"OX": "Oxygen Interactive",
+	"PC": "Playlogic Entertainment",
+	"PL": "Phantagram",
+	"RA": "Rage",
+	"SA": "Sammy",
+	"SC": "SCi Games",
+	"SE": "Sega",
+	"SN": "SNK",
+	"SS": "Simon & Schuster",
+	"SU": "Success",
+	"SW": "Swing! Deutschland",
+	"TA": "Takara",
+	"TC": "Tecmo",
+	"TD": "The 3DO Company",
+	"TK": "Takuyo",
+	"TM": "TDK Mediactive",
+	"TQ": "THQ",
+	"TS": "Titus Interactive",
+	"TT": "Take-Two Interactive",
+	"US": "Ubisoft",
+	"VC": "Victor Interactive Software",
+	"VN": "Vivendi Universal",
+	"VU": "Vivendi Universal Games",
+	"VV": "Vir2L Studios",
+	"WE": "Working Designs",
+	"WI": "Wizard",
+	"XI": "XPEC Entertainment",
+}
+
+// TitleID is a decoded title ID.
+type TitleID struct {
+	ID        string // normalized, 8 lowercase hex digits
+	Code      string // publisher code, e.g. "MS"
+	Publisher string // publisher name, empty if the code is unknown
+	Number    uint16 // game number within the publisher
+}
+
+// String returns the decoded form, e.g. "MS-100".
+func (t TitleID) String() string {
+	return fmt.Sprintf("%s-%03d", t.Code, t.Number)
+}
+
+// Valid reports whether id is 8 hex digits, in either case.
+func Valid(id string) bool {
+	if len(id) != 8 {
+		return false
+	}
+	_, err := strconv.ParseUint(id, 16, 32)
+	return err == nil
+}
+
+// Normalize trims id, strips a 0x prefix and lowercases it, the form used
+// by the database and TDATA folder names.
+func Normalize(id string) (string, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	id = strings.TrimPrefix(id, "0x")
+	if !Valid(id) {
+		return "", fmt.Errorf("invalid title ID %q, expected 8 hex digits", id)
+	}
+	return id, nil
+}
+
+// Decode normalizes id and splits it into publisher and game number.
+func Decode(id string) (TitleID, error) {
+	id, err := Normalize(id)
+	if err != nil {
+		return TitleID{}, err
+	}
+
+	value, _ := strconv.ParseUint(id, 16, 32)
+	code := string([]byte{byte(value >> 24), byte(value >> 16)})
+	return TitleID{
+		ID:        id,
+		Code:      code,
+		Publisher: publishers[code],
+		Number:    uint16(value),
+	}, nil
+}
+
+// Decoded returns the "MS-100" form of id, or an empty string if id is
+// invalid or its publisher code isn't printable.
+func Decoded(id string) string {
+	t, err := Decode(id)
+	if err != nil {
+		return ""
+	}
+	for _, c := range t.Code {
+		if c < 'A' || c > 'Z' {
+			return ""
+		}
+	}
+	return t.String()
+}
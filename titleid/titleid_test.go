@@ -0,0 +1,79 @@
+package titleid
+
+import "testing"
+
+func TestValid(t *testing.T) {
+	for id, want := range map[string]bool{
+		"4d530064":   true,
+		"4D530064":   true,
+		"0x4d530064": false,
+		"4d53006":    false,
+		"4d5300640":  false,
+		"":           false,
+		"4d53006g":   false,
+	} {
+		if got := Valid(id); got != want {
+			t.Errorf("Valid(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for id, want := range map[string]string{
+		"4d530064":     "4d530064",
+		"4D530064":     "4d530064",
+		"0x4d530064":   "4d530064",
+		"0X4D530064":   "4d530064",
+		" 4d530064\n":  "4d530064",
+		"4d53006":      "",
+		"0x4d5300640":  "",
+		"0x":           "",
+		"title 123456": "",
+	} {
+		got, err := Normalize(id)
+		if want == "" {
+			if err == nil {
+				t.Errorf("Normalize(%q) = %q, want an error", id, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v, want %q", id, got, err, want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	got, err := Decode("0x4D530064")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TitleID{ID: "4d530064", Code: "MS", Publisher: "Microsoft", Number: 100}
+	if got != want {
+		t.Errorf("Decode(0x4D530064) = %+v, want %+v", got, want)
+	}
+
+	// Unknown publisher codes still decode, without a name
+	if got, err := Decode("5a5a0001"); err != nil || got.Code != "ZZ" || got.Publisher != "" || got.Number != 1 {
+		t.Errorf("Decode(5a5a0001) = %+v, %v, want code ZZ and number 1 without a publisher", got, err)
+	}
+
+	if _, err := Decode("4d5300"); err == nil {
+		t.Error("Decode(4d5300) accepted a short ID")
+	}
+}
+
+func TestDecoded(t *testing.T) {
+	for id, want := range map[string]string{
+		"4d530064": "MS-100",
+		"4D530064": "MS-100",
+		"4d530fa0": "MS-4000",
+		"fffe0000": "",
+		"00000000": "",
+		"4d53":     "",
+	} {
+		if got := Decoded(id); got != want {
+			t.Errorf("Decoded(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Xbox-Preservation-Project/Pinecone/titleid"
+)
+
+var (
+	lookupMu sync.Mutex
+	// lookupCache holds the online lookups of this session, "" for misses.
+	lookupCache = make(map[string]string)
+)
+
+func (a *App) defaultCommunityTitlesPath() string {
+	return filepath.Join(a.Config.DataPath, "community_titles.json")
+}
+
+// loadCommunityTitles loads the community title dataset: a JSON object of
+// title IDs to names. It's optional, a missing default file isn't an error.
+func (a *App) loadCommunityTitles() error {
+	a.CommunityTitles = nil
+	path := a.Config.CommunityTitles
+	if path == "" {
+		path = a.defaultCommunityTitlesPath()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Error reading community titles: %v", err)
+	}
+	var names map[string]string
+	if err := json.Unmarshal([]byte(removeCommentsFromJSON(string(data))), &names); err != nil {
+		return fmt.Errorf("Error parsing community titles %s: %v", path, err)
+	}
+	a.CommunityTitles = make(map[string]string, len(names))
+	for titleID, name := range names {
+		if normalized, err := titleid.Normalize(titleID); err == nil {
+			a.CommunityTitles[normalized] = name
+		}
+	}
+	return nil
+}
+
+// lookupTitle names a title ID missing from the database from the community
+// dataset, or online when enabled. It returns the name and where it came
+// from, "" if nobody knows the title.
+func (a *App) lookupTitle(titleID string) (string, string, error) {
+	if name, ok := a.CommunityTitles[titleID]; ok {
+		return name, "community titles", nil
+	}
+	settings, err := a.loadSettings()
+	if err != nil || !(a.Config.LookupTitles || settings.TitleLookup) || settings.TitleLookupURL == "" {
+		return "", "", nil
+	}
+	name, err := a.lookupTitleOnline(settings.TitleLookupURL, titleID)
+	if err != nil || name == "" {
+		return "", "", err
+	}
+	return name, hostOf(fmt.Sprintf(settings.TitleLookupURL, titleID)), nil
+}
+
+// lookupTitleOnline queries a community title database, lookupURL with %s
+// for the title ID. The answer is a JSON object, or an array of them, with
+// the name in a "name", "title" or "titleName" field.
+func (a *App) lookupTitleOnline(lookupURL string, titleID string) (string, error) {
+	lookupMu.Lock()
+	defer lookupMu.Unlock()
+	if name, ok := lookupCache[titleID]; ok {
+		return name, nil
+	}
+
+	resp, err := a.httpGet(fmt.Sprintf(lookupURL, titleID))
+	if err != nil {
+		return "", fmt.Errorf("Error looking up title %s: %v", titleID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		lookupCache[titleID] = ""
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Error looking up title %s: %s", titleID, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("Error looking up title %s: %v", titleID, err)
+	}
+
+	name := parseLookupName(body)
+	lookupCache[titleID] = name
+	return name, nil
+}
+
+func parseLookupName(body []byte) string {
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(body, &entries); err != nil {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(body, &entry); err != nil {
+			return ""
+		}
+		entries = append(entries, entry)
+	}
+	for _, entry := range entries {
+		for _, field := range []string{"name", "title", "titlename"} {
+			for key, value := range entry {
+				if name, ok := value.(string); ok && strings.EqualFold(key, field) && strings.TrimSpace(name) != "" {
+					return strings.TrimSpace(name)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func hostOf(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return rawURL
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+const (
+	trayPollInterval = 30 * time.Second
+	// Deep enough to see new content ID folders and update files.
+	trayWatchDepth = 3
+)
+
+// setupTray runs Pinecone from the system tray: the window hides instead of
+// closing and the dump folder is watched for changes. Returns false if the
+// platform has no system tray.
+func (a *App) setupTray(fyneApp fyne.App, w fyne.Window, icon fyne.Resource, options GUIOptions) bool {
+	desk, ok := fyneApp.(desktop.App)
+	if !ok {
+		return false
+	}
+
+	desk.SetSystemTrayIcon(icon)
+	desk.SetSystemTrayMenu(fyne.NewMenu("Pinecone",
+		fyne.NewMenuItem("Show", func() {
+			w.Show()
+		}),
+		// Goes through the same guard as the Scan button and the watcher.
+		fyne.NewMenuItem("Scan Now", func() {
+			a.backgroundRescan(options, w)
+		}),
+	))
+	w.SetCloseIntercept(func() {
+		w.Hide()
+	})
+
+	go a.watchDumpFolder(options, w)
+	return true
+}
+
+// watchDumpFolder rescans whenever a dump location changes and notifies
+// about new unknown content. Polling keeps this working on network mounts
+// and removable drives where change events are unreliable. A change seen
+// while another scan runs is rescanned on the next poll.
+func (a *App) watchDumpFolder(options GUIOptions, w fyne.Window) {
+	a.markFindingsSeen()
+	last := dumpFingerprint(a.scanLocations())
+	for range time.Tick(trayPollInterval) {
+		current := dumpFingerprint(a.scanLocations())
+		if current == last {
+			continue
+		}
+		if a.backgroundRescan(options, w) {
+			last = current
+		}
+	}
+}
+
+// dumpFingerprint hashes the names, sizes and modification times of the top
+// levels of every location's TDATA folder, it changes whenever content is
+// added or removed. Archives are fingerprinted by their own size and time.
+func dumpFingerprint(locations []string) string {
+	hash := sha1.New()
+	for _, location := range locations {
+		fmt.Fprintf(hash, "%s\n", location)
+		if isArchive(location) {
+			if info, err := os.Stat(location); err == nil {
+				fmt.Fprintf(hash, "%d|%d\n", info.Size(), info.ModTime().UnixNano())
+			}
+			continue
+		}
+		fingerprintTDATA(hash, location)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+func fingerprintTDATA(hash io.Writer, location string) {
+	root := filepath.Join(location, "TDATA")
+	if tdata, found := findTDATA(dumpDirFS(location)); found {
+		root = filepath.Join(location, tdata)
+	}
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() && strings.Count(rel, string(filepath.Separator)) >= trayWatchDepth {
+			return filepath.SkipDir
+		}
+		if info, err := d.Info(); err == nil {
+			fmt.Fprintf(hash, "%s|%d|%d\n", rel, info.Size(), info.ModTime().UnixNano())
+		}
+		return nil
+	})
+}
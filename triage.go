@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	fatihColor "github.com/fatih/color"
+)
+
+// Triage decisions, see applyTriage. Items without one are left as they are.
+const (
+	triageSubmit   = "submit"
+	triageIgnore   = "ignore"
+	triageHomebrew = "homebrew"
+)
+
+// triageItems are the unknown findings of the last scan, most promising
+// first, the ones without a confidence score after them.
+func (a *App) triageItems() []Finding {
+	items := a.Report.UnknownByConfidence()
+	for _, f := range a.Report.Findings {
+		if f.Status == statusUnknown && (f.Kind == kindDashboard || f.Kind == kindHomebrew || f.Kind == kindDevkit || f.Kind == kindSoundtrack) {
+			items = append(items, f)
+		}
+	}
+	return items
+}
+
+// triageLines describes an item to decide on.
+func triageLines(f Finding) []string {
+	lines := []string{fmt.Sprintf("%s %s for %s (%s)", statusLabel(f.Status), f.Kind, f.TitleName, displayTitleID(f.TitleID)), "Path: " + f.Path}
+	if f.Name != "" {
+		lines = append(lines, "Name: "+f.Name)
+	}
+	if f.Media != "" {
+		lines = append(lines, "Media: "+f.Media)
+	}
+	if f.Signature != "" {
+		lines = append(lines, "XBE signature: "+f.Signature)
+	}
+	if f.Confidence.Score > 0 || len(f.Confidence.Signals) > 0 {
+		lines = append(lines, "Confidence: "+f.Confidence.String())
+	}
+	if len(f.Prototype) > 0 {
+		lines = append(lines, "Possible prototype: "+strings.Join(f.Prototype, ", "))
+	}
+	return lines
+}
+
+// applyTriage carries out the decisions in bulk: the items to submit are
+// packaged into one zip, ignored items won't be reported by later scans and
+// neither will homebrew nor anything else under its title ID. Returns what
+// was done.
+func (a *App) applyTriage(items []Finding, decisions []string, settings *Settings) ([]string, error) {
+	var submit []Finding
+	var ignoredKeys, homebrewIDs []string
+	ignored := 0
+	for i, f := range items {
+		switch decisions[i] {
+		case triageSubmit:
+			submit = append(submit, f)
+		case triageIgnore:
+			ignoredKeys = append(ignoredKeys, f.key())
+			ignored++
+		case triageHomebrew:
+			// Not everything is stored under its title ID, e.g. dashboards.
+			ignoredKeys = append(ignoredKeys, f.key())
+			if !contains(homebrewIDs, f.TitleID) {
+				homebrewIDs = append(homebrewIDs, f.TitleID)
+			}
+		}
+	}
+
+	var done []string
+	if len(submit) > 0 {
+		zipPath, err := a.packageSubmission(submit, settings)
+		if err != nil {
+			return done, err
+		}
+		done = append(done, fmt.Sprintf("%d item(s) packaged for submission: %s", len(submit), zipPath))
+	}
+	if len(ignoredKeys) > 0 || len(homebrewIDs) > 0 {
+		if err := a.ignoreInSettings(ignoredKeys, homebrewIDs); err != nil {
+			return done, err
+		}
+	}
+	if ignored > 0 {
+		done = append(done, fmt.Sprintf("%d item(s) ignored in future scans", ignored))
+	}
+	if len(homebrewIDs) > 0 {
+		done = append(done, fmt.Sprintf("Homebrew title IDs ignored in future scans: %s", strings.Join(homebrewIDs, ", ")))
+	}
+	if len(done) == 0 {
+		done = append(done, "Nothing to do.")
+	}
+	return done, nil
+}
+
+// runTriage asks what to do with every unknown item of the last scan, then
+// applies the decisions.
+func (a *App) runTriage(in io.Reader, settings *Settings) error {
+	items := a.triageItems()
+	if len(items) == 0 {
+		printLine("Nothing to triage, no unknown items were found.")
+		return nil
+	}
+
+	printHeader("Triage")
+	reader := bufio.NewReader(in)
+	decisions := make([]string, len(items))
+items:
+	for i, f := range items {
+		printInfo(fatihColor.FgCyan, "[%d/%d]\n", i+1, len(items))
+		for _, line := range triageLines(f) {
+			printInfo(fatihColor.FgWhite, "%s\n", line)
+		}
+		for {
+			fmt.Print("    (s)ubmit, (i)gnore, (h)omebrew, (n)ext or (q)uit and apply: ")
+			answer, err := reader.ReadString('\n')
+			if err != nil && answer == "" {
+				fmt.Println()
+				break items
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "s", "submit":
+				decisions[i] = triageSubmit
+			case "i", "ignore":
+				decisions[i] = triageIgnore
+			case "h", "homebrew":
+				decisions[i] = triageHomebrew
+			case "n", "next", "":
+			case "q", "quit":
+				break items
+			default:
+				continue
+			}
+			break
+		}
+	}
+
+	done, err := a.applyTriage(items, decisions, settings)
+	for _, line := range done {
+		printInfo(fatihColor.FgGreen, "%s\n", line)
+	}
+	return err
+}
+
+// showTriageWizard steps through the unknown items of the last scan in a
+// window, applying the decisions at the end.
+func (a *App) showTriageWizard(parent fyne.Window) {
+	items := a.triageItems()
+	if len(items) == 0 {
+		dialog.ShowInformation("Triage", "Nothing to triage, no unknown items were found.", parent)
+		return
+	}
+
+	triageWindow := fyne.CurrentApp().NewWindow("Triage")
+	decisions := make([]string, len(items))
+	current := 0
+	progress := widget.NewLabel("")
+	details := widget.NewLabel("")
+	details.Wrapping = fyne.TextWrapWord
+
+	var buttons *fyne.Container
+	var show func()
+	decide := func(decision string) func() {
+		return func() {
+			decisions[current] = decision
+			current++
+			show()
+		}
+	}
+	back := widget.NewButton("Back", func() {
+		if current > 0 {
+			current--
+			show()
+		}
+	})
+	apply := widget.NewButton("Apply", func() {
+		settings, err := a.loadSettings()
+		if err != nil {
+			settings = &Settings{}
+		}
+		done, err := a.applyTriage(items, decisions, settings)
+		if err != nil {
+			dialog.ShowError(err, triageWindow)
+			return
+		}
+		dialog.ShowInformation("Triage", strings.Join(done, "\n"), parent)
+		triageWindow.Close()
+	})
+	choices := container.NewHBox(
+		widget.NewButton("Submit", decide(triageSubmit)),
+		widget.NewButton("Ignore", decide(triageIgnore)),
+		widget.NewButton("Homebrew", decide(triageHomebrew)),
+		widget.NewButton("Skip", decide("")),
+	)
+	show = func() {
+		if current >= len(items) {
+			counts := make(map[string]int)
+			for _, decision := range decisions {
+				counts[decision]++
+			}
+			progress.SetText("Done")
+			details.SetText(fmt.Sprintf("%d to submit, %d to ignore, %d homebrew, %d skipped. Apply to package the submission and update the ignore lists.",
+				counts[triageSubmit], counts[triageIgnore], counts[triageHomebrew], counts[""]))
+			buttons.Objects = []fyne.CanvasObject{back, apply}
+		} else {
+			progress.SetText(fmt.Sprintf("Item %d of %d", current+1, len(items)))
+			details.SetText(strings.Join(triageLines(items[current]), "\n"))
+			buttons.Objects = []fyne.CanvasObject{back, choices, apply}
+		}
+		buttons.Refresh()
+	}
+	buttons = container.NewHBox()
+	show()
+
+	triageWindow.SetContent(container.NewBorder(progress, buttons, nil, nil, container.NewVScroll(details)))
+	triageWindow.Resize(fyne.NewSize(600, 300))
+	triageWindow.Show()
+}
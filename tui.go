@@ -0,0 +1,399 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	fatihColor "github.com/fatih/color"
+)
+
+// ANSI sequences used by the terminal UI, skipped when color is disabled.
+const (
+	tuiReset  = "\x1b[0m"
+	tuiRed    = "\x1b[31m"
+	tuiGreen  = "\x1b[32m"
+	tuiYellow = "\x1b[33m"
+	tuiCyan   = "\x1b[36m"
+	tuiInvert = "\x1b[7m"
+)
+
+type tuiLine struct {
+	text  string
+	color string
+	// interesting lines are kept when filtering to unknown content.
+	interesting bool
+}
+
+// tuiTitle is the output of one title folder, the first group holds
+// anything reported before the first title.
+type tuiTitle struct {
+	header string
+	lines  []tuiLine
+}
+
+// tuiState is shared between the scan, which appends results, and the
+// terminal loop drawing them.
+type tuiState struct {
+	mu          sync.Mutex
+	groups      []*tuiTitle
+	titles      int
+	findings    int
+	unknown     int
+	errors      int
+	done        bool
+	err         error
+	started     time.Time
+	elapsed     time.Duration
+	scroll      int
+	onlyUnknown bool
+}
+
+func (s *tuiState) add(line tuiLine) {
+	if len(s.groups) == 0 {
+		s.groups = append(s.groups, &tuiTitle{})
+	}
+	group := s.groups[len(s.groups)-1]
+	group.lines = append(group.lines, line)
+}
+
+// savesGroup starts the group holding the saves found in UDATA, if it isn't
+// the current one already.
+func (s *tuiState) savesGroup() {
+	if len(s.groups) == 0 || s.groups[len(s.groups)-1].header != "Saves" {
+		s.groups = append(s.groups, &tuiTitle{header: "Saves"})
+	}
+}
+
+// tuiPresenter collects scan events for the terminal UI.
+type tuiPresenter struct {
+	state *tuiState
+}
+
+func (p tuiPresenter) Present(event ScanEvent) {
+	s := p.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := event.Finding
+	switch event.Kind {
+	case EventTitleFound:
+		s.titles++
+		s.groups = append(s.groups, &tuiTitle{header: fmt.Sprintf("%s (%s)", event.TitleName, displayTitleID(event.TitleID))})
+	case EventDuplicate:
+		s.add(tuiLine{text: fmt.Sprintf("Also found in %s: %s", event.Location, f.Path)})
+	case EventCopy:
+		s.add(tuiLine{text: fmt.Sprintf("Identical %s also at: %s", f.Kind, f.Path)})
+	case EventConsole:
+		s.groups = append(s.groups, &tuiTitle{header: "Console"})
+		for _, line := range consoleLines(event.Console) {
+			s.add(tuiLine{text: line})
+		}
+	case EventSave:
+		s.savesGroup()
+		s.add(tuiLine{text: fmt.Sprintf("Save \"%s\" for %s at: %s", event.Message, event.TitleName, f.Path)})
+	case EventWarning, EventSkipped:
+		s.add(tuiLine{text: event.Message, color: tuiYellow, interesting: true})
+	case EventUnknownTitle:
+		s.add(tuiLine{text: unknownTitleMessage(event.UnknownTitle), color: tuiYellow, interesting: true})
+	case EventSystemTitle:
+		s.add(tuiLine{text: systemTitleMessage(event.SystemTitle), color: tuiCyan})
+	case EventError:
+		s.errors++
+		s.add(tuiLine{text: event.Message, color: tuiRed, interesting: true})
+	case EventFinding:
+		s.findings++
+		if f.Kind == kindDashboard || f.Kind == kindHomebrew || f.Kind == kindDevkit || f.Kind == kindSoundtrack {
+			s.groups = append(s.groups, &tuiTitle{header: f.TitleName})
+		} else if f.Kind == kindSave {
+			s.savesGroup()
+		}
+		switch f.Status {
+		case statusArchived:
+			s.add(tuiLine{text: fmt.Sprintf("Known %s: %s (%s)", f.Kind, f.Name, filepath.Base(f.Path)), color: tuiGreen})
+		case statusKnownBad:
+			s.add(tuiLine{text: fmt.Sprintf("Known bad %s: %s (%s)", f.Kind, f.Name, f.Path), color: tuiYellow})
+		case statusUnknown:
+			s.unknown++
+			s.add(tuiLine{text: fmt.Sprintf("Unknown %s: %s", f.Kind, f.Path), color: tuiRed, interesting: true})
+			if f.Offering != "" {
+				s.add(tuiLine{text: "  Offering: " + f.Offering, color: tuiRed, interesting: true})
+			}
+			if f.Listing != "" {
+				s.add(tuiLine{text: "  This content " + f.Listing, color: tuiRed, interesting: true})
+			}
+			if f.Media != "" {
+				s.add(tuiLine{text: "  Media: " + f.Media, color: tuiRed, interesting: true})
+			}
+			if f.Digest != "" {
+				s.add(tuiLine{text: "  Content digest: " + f.Digest, color: tuiRed, interesting: true})
+			}
+			if date := modifiedColumn(f); date != "" {
+				s.add(tuiLine{text: "  Modified: " + date, color: tuiRed, interesting: true})
+			}
+			if f.SHA1 != "" {
+				s.add(tuiLine{text: "  SHA1: " + f.SHA1, color: tuiRed, interesting: true})
+			}
+			if f.Signature != "" {
+				s.add(tuiLine{text: "  XBE signature: " + f.Signature, color: tuiRed, interesting: true})
+			}
+			if f.SuggestedName != "" {
+				s.add(tuiLine{text: "  Suggested name: " + f.SuggestedName, color: tuiRed, interesting: true})
+			}
+			if len(f.Prototype) > 0 {
+				s.add(tuiLine{text: "  Possible prototype: " + strings.Join(f.Prototype, ", "), color: tuiRed, interesting: true})
+			}
+			if f.Kind != kindDashboard && f.Kind != kindHomebrew && f.Kind != kindDevkit && f.Kind != kindSoundtrack {
+				s.add(tuiLine{text: "  Confidence: " + f.Confidence.String(), color: tuiRed, interesting: true})
+			}
+		default:
+			s.unknown++
+			s.add(tuiLine{text: fmt.Sprintf("Unarchived %s: %s", f.Kind, f.Path), color: tuiYellow, interesting: true})
+			if f.Listing != "" {
+				s.add(tuiLine{text: "  This content " + f.Listing, color: tuiYellow, interesting: true})
+			}
+			if f.Media != "" {
+				s.add(tuiLine{text: "  Media: " + f.Media, color: tuiYellow, interesting: true})
+			}
+		}
+	}
+}
+
+// visibleLines flattens the results into screen lines, keeping only titles
+// with unknown/unarchived content when the filter is on.
+func (s *tuiState) visibleLines() []tuiLine {
+	var lines []tuiLine
+	for _, group := range s.groups {
+		var body []tuiLine
+		for _, line := range group.lines {
+			if !s.onlyUnknown || line.interesting {
+				body = append(body, line)
+			}
+		}
+		if s.onlyUnknown && len(body) == 0 {
+			continue
+		}
+		if group.header != "" {
+			lines = append(lines, tuiLine{text: "== " + group.header + " ==", color: tuiCyan})
+		}
+		for _, line := range body {
+			line.text = "    " + line.text
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// startTUI scans the dump locations showing live progress and the results
+// in a scrollable full screen view.
+func (a *App) startTUI(options CLIOptions) {
+	a.Config.GUI = false
+
+	err := checkDataFolder(options.DataFolder)
+	if err != nil {
+		exitWithError(err)
+	}
+	err = a.checkDatabaseFile(options.JSONFilePath, options.JSONUrl, a.Config.Update)
+	if err != nil {
+		exitWithError(err)
+	}
+	for _, location := range a.scanLocations() {
+		err = a.checkDumpFolder(location)
+		if err != nil {
+			exitWithError(err)
+		}
+	}
+
+	stdin := int(os.Stdin.Fd())
+	restore, err := makeRaw(stdin)
+	if err != nil {
+		exitWithError(fmt.Errorf("Error starting the terminal UI: %v", err))
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	// Alternate screen, hidden cursor.
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	out.Flush()
+
+	state := &tuiState{started: time.Now()}
+	a.resetReport()
+	go func() {
+		err := runScan(&a.Report, func(events chan<- ScanEvent) error {
+			return a.scanDumpLocations(a.scanLocations(), events)
+		}, tuiPresenter{state})
+		if err == nil {
+			a.shareScanStats()
+		}
+		state.mu.Lock()
+		state.done, state.err = true, err
+		state.elapsed = time.Since(state.started)
+		state.mu.Unlock()
+	}()
+
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for quit := false; !quit; {
+		drawTUI(out, state)
+		select {
+		case key := <-keys:
+			quit = handleTUIKey(state, key)
+		case <-ticker.C:
+		}
+	}
+
+	fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+	out.Flush()
+	restore()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.err != nil {
+		exitWithError(state.err)
+	}
+	if !state.done {
+		os.Exit(exitError)
+	}
+	fmt.Printf("Scanned %d title(s), %d finding(s), %d unknown or unarchived.\n", state.titles, state.findings, state.unknown)
+	if len(a.Report.Errors) > 0 {
+		os.Exit(exitError)
+	}
+	if len(a.Report.Interesting().Findings) > 0 {
+		os.Exit(exitFoundContent)
+	}
+	os.Exit(exitNothingFound)
+}
+
+// readKeys turns terminal input into key names, arrow and page keys arrive
+// as escape sequences.
+func readKeys(in *os.File, keys chan<- string) {
+	buf := make([]byte, 16)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		input := string(buf[:n])
+		switch input {
+		case "\x1b[A", "\x1bOA":
+			keys <- "up"
+		case "\x1b[B", "\x1bOB":
+			keys <- "down"
+		case "\x1b[5~":
+			keys <- "pgup"
+		case "\x1b[6~":
+			keys <- "pgdown"
+		case "\x1b[H", "\x1b[1~":
+			keys <- "home"
+		case "\x1b[F", "\x1b[4~":
+			keys <- "end"
+		case "\x1b", "\x03":
+			keys <- "quit"
+		default:
+			for _, r := range input {
+				keys <- string(r)
+			}
+		}
+	}
+}
+
+// handleTUIKey applies a key press, returning true to quit.
+func handleTUIKey(s *tuiState, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, height, _ := terminalSize(int(os.Stdout.Fd()))
+	page := height - 4
+	if page < 1 {
+		page = 1
+	}
+	switch key {
+	case "", "quit", "q":
+		return true
+	case "up", "k":
+		s.scroll--
+	case "down", "j":
+		s.scroll++
+	case "pgup", "b":
+		s.scroll -= page
+	case "pgdown", " ":
+		s.scroll += page
+	case "home", "g":
+		s.scroll = 0
+	case "end", "G":
+		s.scroll = len(s.visibleLines())
+	case "u":
+		s.onlyUnknown = !s.onlyUnknown
+		s.scroll = 0
+	}
+	return false
+}
+
+// drawTUI redraws the whole screen: a progress line, the results and a key
+// help line. Output processing is off in raw mode, so lines end in \r\n.
+func drawTUI(out *bufio.Writer, s *tuiState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	width, height, err := terminalSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = headerWidth, 24
+	}
+	paint := func(code string, text string) string {
+		if runes := []rune(text); len(runes) > width {
+			text = string(runes[:width])
+		}
+		if fatihColor.NoColor || code == "" {
+			return text
+		}
+		return code + text + tuiReset
+	}
+
+	lines := s.visibleLines()
+	rows := height - 2
+	if rows < 1 {
+		rows = 1
+	}
+	if s.scroll > len(lines)-rows {
+		s.scroll = len(lines) - rows
+	}
+	if s.scroll < 0 {
+		s.scroll = 0
+	}
+
+	status, elapsed := "Scanning", time.Since(s.started)
+	if s.done {
+		status, elapsed = "Done", s.elapsed
+		if s.err != nil {
+			status = "Failed"
+		}
+	}
+	progress := fmt.Sprintf(" Pinecone v%s | %s %s | %d titles, %d findings, %d unknown/unarchived, %d errors",
+		version, status, elapsed.Round(time.Second), s.titles, s.findings, s.unknown, s.errors)
+
+	fmt.Fprint(out, "\x1b[H\x1b[2J")
+	fmt.Fprint(out, paint(tuiInvert, progress+strings.Repeat(" ", max(0, width-len(progress)))), "\r\n")
+	for i := s.scroll; i < s.scroll+rows; i++ {
+		if i < len(lines) {
+			fmt.Fprint(out, paint(lines[i].color, lines[i].text))
+		}
+		fmt.Fprint(out, "\r\n")
+	}
+	if s.err != nil {
+		fmt.Fprint(out, paint(tuiRed, " "+s.err.Error()))
+	} else {
+		filter := "all"
+		if s.onlyUnknown {
+			filter = "unknown only"
+		}
+		fmt.Fprint(out, paint("", fmt.Sprintf(" ↑/↓ j/k scroll  PgUp/PgDn page  g/G top/bottom  u filter (%s)  q quit", filter)))
+	}
+	out.Flush()
+}
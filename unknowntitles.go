@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/Xbox-Preservation-Project/Pinecone/titleid"
+)
+
+// UnknownTitle is a title ID folder of a title missing from the database,
+// listed in reports so the database team learns about it.
+type UnknownTitle struct {
+	TitleID string
+	// Name and NameSource come from community title databases, see
+	// lookupTitle, "" if nobody knows the title.
+	Name       string
+	NameSource string
+	Location   string
+	Path       string
+	Content    []string // folders in $c
+	Updates    []string // files in $u
+}
+
+// Contents summarizes what the folder holds, e.g. "2 content, 1 update".
+func (u UnknownTitle) Contents() string {
+	return folderContents(u.Content, u.Updates)
+}
+
+func folderContents(content []string, updates []string) string {
+	var parts []string
+	if len(content) > 0 {
+		parts = append(parts, fmt.Sprintf("%d content", len(content)))
+	}
+	if len(updates) == 1 {
+		parts = append(parts, "1 update")
+	} else if len(updates) > 1 {
+		parts = append(parts, fmt.Sprintf("%d updates", len(updates)))
+	}
+	if len(parts) == 0 {
+		return "no content or updates"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkUnknownTitle lists the content and updates of a title ID folder
+// missing from the database. Folders that aren't title IDs are ignored.
+func (a *App) checkUnknownTitle(fsys fs.FS, titleDir string, titleID string, location string, events chan<- ScanEvent) error {
+	if !titleid.Valid(titleID) {
+		return nil
+	}
+	unknown := &UnknownTitle{TitleID: titleID, Location: location, Path: displayPath(location, titleDir)}
+
+	name, source, err := a.lookupTitle(titleID)
+	if err != nil {
+		emitWarning(events, err.Error())
+	}
+	unknown.Name, unknown.NameSource = name, source
+	if unknown.Content, unknown.Updates, err = listTitleFolder(fsys, titleDir); err != nil {
+		return err
+	}
+
+	events <- ScanEvent{Kind: EventUnknownTitle, TitleID: titleID, Location: unknown.Location, UnknownTitle: unknown}
+	return nil
+}
+
+// listTitleFolder lists the content folders in $c and files in $u of a
+// title ID folder, without checking them.
+func listTitleFolder(fsys fs.FS, titleDir string) ([]string, []string, error) {
+	var content, updates []string
+	if subDirDLC, found := findSubDir(fsys, titleDir, "$c"); found {
+		entries, err := fs.ReadDir(fsys, subDirDLC)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, entry := range entries {
+			if entry := resolveSymlink(fsys, path.Join(subDirDLC, entry.Name()), entry); entry != nil && entry.IsDir() {
+				content = append(content, entry.Name())
+			}
+		}
+	}
+	if subDirUpdates, found := findSubDir(fsys, titleDir, "$u"); found {
+		entries, err := fs.ReadDir(fsys, subDirUpdates)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, entry := range entries {
+			if entry := resolveSymlink(fsys, path.Join(subDirUpdates, entry.Name()), entry); entry != nil && !entry.IsDir() {
+				updates = append(updates, entry.Name())
+			}
+		}
+	}
+	return content, updates, nil
+}
+
+// unknownTitleContents lists what the folder holds for reports, e.g. "1
+// content, 1 update: 4d530001000a, default.xbe".
+func unknownTitleContents(u UnknownTitle) string {
+	summary := u.Contents()
+	names := append(append([]string{}, u.Content...), u.Updates...)
+	if len(names) > 0 {
+		summary += ": " + strings.Join(names, ", ")
+	}
+	return summary
+}
+
+// unknownTitleMessage is the line presenters show for an unknown title.
+func unknownTitleMessage(u *UnknownTitle) string {
+	message := fmt.Sprintf("Title %s isn't in the database (%s): %s", displayTitleID(u.TitleID), u.Contents(), u.Path)
+	if u.Name != "" {
+		message += fmt.Sprintf(", %s knows it as %q, please propose adding it", u.NameSource, u.Name)
+	}
+	return message
+}
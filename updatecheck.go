@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultUpdateCheckHours = 6
+
+// UpdateCheck records the last database update check, so repeated -update
+// runs don't hit the GitHub API every time.
+type UpdateCheck struct {
+	LastCheck time.Time `json:"lastCheck"`
+	RemoteSHA string    `json:"remoteSHA"`
+}
+
+func (a *App) updateCheckPath() string {
+	return filepath.Join(a.Config.DataPath, "update_check.json")
+}
+
+func (a *App) loadUpdateCheck() UpdateCheck {
+	var check UpdateCheck
+	if data, err := os.ReadFile(a.updateCheckPath()); err == nil {
+		json.Unmarshal(data, &check)
+	}
+	return check
+}
+
+func (a *App) saveUpdateCheck(remoteSHA string) error {
+	data, err := json.MarshalIndent(UpdateCheck{LastCheck: time.Now(), RemoteSHA: remoteSHA}, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.updateCheckPath(), data, 0o644)
+}
+
+// updateCheckWindow is how long a check is trusted, from the settings.
+func (a *App) updateCheckWindow() time.Duration {
+	hours := defaultUpdateCheckHours
+	if settings, err := a.loadSettings(); err == nil && settings.UpdateCheckHours != 0 {
+		hours = settings.UpdateCheckHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// recentlyChecked reports whether the local database was found up to date by
+// a check within the update check window, in which case the network call is
+// skipped unless -force-update is given.
+func (a *App) recentlyChecked(jsonFilePath string) bool {
+	if a.Config.ForceUpdate {
+		return false
+	}
+	check := a.loadUpdateCheck()
+	if check.RemoteSHA == "" || time.Since(check.LastCheck) > a.updateCheckWindow() {
+		return false
+	}
+	localData, err := os.ReadFile(jsonFilePath)
+	return err == nil && gitBlobSHA(localData) == check.RemoteSHA
+}
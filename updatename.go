@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// Known update names follow "<offering ID>:<label> <version>", e.g.
+// "0000000200000302:PAL 0302". The offering ID holds the SKU, one per
+// regional release of the title, and the update number within it:
+// 0000000S 0000 UUSS. The version repeats its last four digits.
+
+// updateSKU is a regional release of a title with its known updates.
+type updateSKU struct {
+	Number     uint32
+	Label      string // e.g. "PAL Germany"
+	LastUpdate uint32
+}
+
+// xbeRegionLabel is the label of the XBE region flags as used in update
+// names, "" for unknown flags.
+func xbeRegionLabel(region uint32) string {
+	switch region &^ 0x80000000 { // the manufacturing flag isn't a region
+	case 0x1:
+		return "NTSC"
+	case 0x2:
+		return "NTSC-J"
+	case 0x4:
+		return "PAL"
+	case 0x3:
+		return "NTSC+NTSC-J"
+	case 0x5:
+		return "NTSC+PAL"
+	case 0x7:
+		return "RF"
+	}
+	return ""
+}
+
+// parseUpdateName splits a known update name into its SKU, update number and
+// label.
+func parseUpdateName(name string) (uint32, uint32, string, bool) {
+	offering, rest, found := strings.Cut(name, ":")
+	if !found || len(offering) != 16 {
+		return 0, 0, "", false
+	}
+	sku, err := strconv.ParseUint(offering[:8], 16, 32)
+	if err != nil {
+		return 0, 0, "", false
+	}
+	update, err := strconv.ParseUint(offering[12:14], 16, 8)
+	if err != nil {
+		return 0, 0, "", false
+	}
+	label := rest
+	if i := strings.LastIndex(rest, " "); i > 0 {
+		label = rest[:i]
+	}
+	return uint32(sku), uint32(update), label, true
+}
+
+// updateSKUs lists the regional releases of a title from its known update
+// names, in SKU order.
+func updateSKUs(titleData TitleData) []updateSKU {
+	var skus []updateSKU
+	for _, knownUpdate := range titleData.TitleUpdatesKnown {
+		for _, name := range knownUpdate {
+			number, update, label, ok := parseUpdateName(name)
+			if !ok {
+				continue
+			}
+			i := 0
+			for i < len(skus) && skus[i].Number < number {
+				i++
+			}
+			if i == len(skus) || skus[i].Number != number {
+				skus = append(skus[:i], append([]updateSKU{{Number: number, Label: label}}, skus[i:]...)...)
+			}
+			skus[i].LastUpdate = max(skus[i].LastUpdate, update)
+		}
+	}
+	return skus
+}
+
+// suggestUpdateName proposes the database name of an unknown title update:
+// the next update of the title's release for the XBE's region, or the first
+// update of a new release. It returns "" when the region can't be told.
+func suggestUpdateName(titleData TitleData, region uint32) string {
+	skus := updateSKUs(titleData)
+	label := xbeRegionLabel(region)
+
+	var next updateSKU
+	for _, sku := range skus {
+		if label != "" && strings.HasPrefix(sku.Label+" ", label+" ") {
+			next = sku
+			break
+		}
+	}
+	switch {
+	case next.Number != 0:
+	case label == "" && len(skus) == 1:
+		next = skus[0]
+	case label == "":
+		return ""
+	default:
+		next = updateSKU{Number: 1, Label: label}
+		if len(skus) > 0 {
+			next.Number = skus[len(skus)-1].Number + 1
+		}
+	}
+
+	update := next.LastUpdate + 1
+	version := fmt.Sprintf("%02x%02x", update&0xff, next.Number&0xff)
+	return fmt.Sprintf("%08x0000%s:%s %s", next.Number, version, next.Label, version)
+}
+
+// suggestUpdateNameFS reads the region of an unknown update and proposes its
+// name, see suggestUpdateName.
+func suggestUpdateNameFS(fsys fs.FS, filePath string, titleData TitleData) string {
+	var region uint32
+	if xbe, err := readXBEInfoFS(fsys, filePath); err == nil {
+		region = xbe.Region
+	}
+	return suggestUpdateName(titleData, region)
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	titleIDPattern   = regexp.MustCompile(`^[0-9a-f]{8}$`)
+	contentIDPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+	sha1Pattern      = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	quickHashPattern = regexp.MustCompile(`^[0-9]+:[0-9a-f]{40}$`)
+	languagePattern  = regexp.MustCompile(`^[a-z]{2,3}$`)
+)
+
+// validateDatabase checks a (comment free) database against the TitleList
+// schema and reports every problem with the line and title it belongs to,
+// to help contributors fixing hand-edited files.
+func validateDatabase(jsonStr string) error {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(jsonStr), &root); err != nil {
+		return describeJSONError(jsonStr, err)
+	}
+
+	rawTitles, ok := root["Titles"]
+	if !ok {
+		return fmt.Errorf("database is missing the top level 'Titles' object")
+	}
+
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(rawTitles, &entries); err != nil {
+		return fmt.Errorf("'Titles' must be an object of title IDs: %v", err)
+	}
+
+	var problems []string
+	if rawVersion, ok := root["Schema Version"]; ok {
+		var schemaVersion int
+		if err := json.Unmarshal(rawVersion, &schemaVersion); err != nil || schemaVersion < 1 {
+			problems = append(problems, fmt.Sprintf("line %d: 'Schema Version' must be a positive number", lineOfKey(jsonStr, "Schema Version")))
+		} else if schemaVersion > databaseSchemaVersion {
+			problems = append(problems, fmt.Sprintf("line %d: 'Schema Version' %d is newer than this version of Pinecone reads (%d), please update Pinecone",
+				lineOfKey(jsonStr, "Schema Version"), schemaVersion, databaseSchemaVersion))
+		}
+	}
+
+	if rawDashboards, ok := root["Dashboards"]; ok {
+		var dashboards map[string]string
+		if err := json.Unmarshal(rawDashboards, &dashboards); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: 'Dashboards' must be an object of {\"sha1\": \"version\"}", lineOfKey(jsonStr, "Dashboards")))
+		}
+		for hash := range dashboards {
+			if !sha1Pattern.MatchString(hash) {
+				problems = append(problems, fmt.Sprintf("line %d: Dashboards has an invalid SHA1 %q", lineOfKey(jsonStr, hash), hash))
+			}
+		}
+	}
+
+	if rawKnownBad, ok := root["Known Bad"]; ok {
+		var knownBad map[string]string
+		if err := json.Unmarshal(rawKnownBad, &knownBad); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: 'Known Bad' must be an object of {\"sha1\": \"reason\"}", lineOfKey(jsonStr, "Known Bad")))
+		}
+		for hash, reason := range knownBad {
+			if !sha1Pattern.MatchString(hash) {
+				problems = append(problems, fmt.Sprintf("line %d: Known Bad has an invalid SHA1 %q", lineOfKey(jsonStr, hash), hash))
+			}
+			if strings.TrimSpace(reason) == "" {
+				problems = append(problems, fmt.Sprintf("line %d: Known Bad %s has no reason", lineOfKey(jsonStr, hash), hash))
+			}
+		}
+	}
+
+	if rawSoundtracks, ok := root["Soundtracks"]; ok {
+		var soundtracks map[string]string
+		if err := json.Unmarshal(rawSoundtracks, &soundtracks); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: 'Soundtracks' must be an object of {\"digest\": \"name\"}", lineOfKey(jsonStr, "Soundtracks")))
+		}
+		for digest := range soundtracks {
+			if !sha1Pattern.MatchString(digest) {
+				problems = append(problems, fmt.Sprintf("line %d: Soundtracks has an invalid digest %q", lineOfKey(jsonStr, digest), digest))
+			}
+		}
+	}
+
+	if rawQuick, ok := root["Quick Hashes"]; ok {
+		var quickHashes map[string]string
+		if err := json.Unmarshal(rawQuick, &quickHashes); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: 'Quick Hashes' must be an object of {\"size:quick hash\": \"SHA1\"}", lineOfKey(jsonStr, "Quick Hashes")))
+		}
+		for quick, hash := range quickHashes {
+			if !quickHashPattern.MatchString(quick) {
+				problems = append(problems, fmt.Sprintf("line %d: Quick Hashes has an invalid quick hash %q", lineOfKey(jsonStr, quick), quick))
+			}
+			if !sha1Pattern.MatchString(hash) {
+				problems = append(problems, fmt.Sprintf("line %d: Quick Hashes has an invalid SHA1 %q", lineOfKey(jsonStr, hash), hash))
+			}
+		}
+	}
+
+	if rawSystem, ok := root["System Titles"]; ok {
+		var systemTitles map[string]SystemTitle
+		if err := json.Unmarshal(rawSystem, &systemTitles); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: 'System Titles' must be an object of title IDs to {\"Name\": \"...\", \"Description\": \"...\"}", lineOfKey(jsonStr, "System Titles")))
+		}
+		for titleID, system := range systemTitles {
+			if !titleIDPattern.MatchString(titleID) {
+				problems = append(problems, fmt.Sprintf("line %d: System Titles has an invalid title ID %q", lineOfKey(jsonStr, titleID), titleID))
+			}
+			if strings.TrimSpace(system.Name) == "" {
+				problems = append(problems, fmt.Sprintf("line %d: System Titles %s has no 'Name'", lineOfKey(jsonStr, titleID), titleID))
+			}
+		}
+	}
+
+	if rawSaves, ok := root["Wanted Saves"]; ok {
+		var wantedSaves map[string][]WantedSave
+		if err := json.Unmarshal(rawSaves, &wantedSaves); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: 'Wanted Saves' must be an object of title IDs to [{\"Name\": \"...\", \"SHA1\": \"...\"}] lists", lineOfKey(jsonStr, "Wanted Saves")))
+		}
+		for titleID, saves := range wantedSaves {
+			if !titleIDPattern.MatchString(titleID) {
+				problems = append(problems, fmt.Sprintf("line %d: Wanted Saves has an invalid title ID %q", lineOfKey(jsonStr, titleID), titleID))
+			}
+			for _, save := range saves {
+				if strings.TrimSpace(save.Name) == "" {
+					problems = append(problems, fmt.Sprintf("line %d: Wanted Saves of %s has a save without a 'Name'", lineOfKey(jsonStr, titleID), titleID))
+				}
+				if save.SHA1 != "" && !sha1Pattern.MatchString(save.SHA1) {
+					problems = append(problems, fmt.Sprintf("line %d: Wanted Saves of %s has an invalid SHA1 %q", lineOfKey(jsonStr, save.SHA1), titleID, save.SHA1))
+				}
+			}
+		}
+	}
+
+	titleIDs := make([]string, 0, len(entries))
+	for titleID := range entries {
+		titleIDs = append(titleIDs, titleID)
+	}
+	sort.Strings(titleIDs)
+
+	for _, titleID := range titleIDs {
+		for _, problem := range validateTitle(titleID, entries[titleID]) {
+			problems = append(problems, fmt.Sprintf("line %d: Title %s %s", lineOfKey(jsonStr, titleID), strings.ToUpper(titleID), problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("database has %d problem(s):\n  %s", len(problems), strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func validateTitle(titleID string, raw json.RawMessage) []string {
+	var problems []string
+
+	if !titleIDPattern.MatchString(titleID) {
+		problems = append(problems, "has an invalid ID, expected 8 lowercase hex characters")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return append(problems, "is not an object")
+	}
+
+	var name string
+	if rawName, ok := fields["Title Name"]; !ok {
+		problems = append(problems, "missing 'Title Name'")
+	} else if err := json.Unmarshal(rawName, &name); err != nil {
+		problems = append(problems, "'Title Name' must be a string")
+	} else if strings.TrimSpace(name) == "" {
+		problems = append(problems, "has an empty 'Title Name'")
+	}
+
+	var contentIDs []string
+	problems = append(problems, checkField(fields, "Content IDs", &contentIDs, "a list of content IDs")...)
+	for _, contentID := range contentIDs {
+		if !contentIDPattern.MatchString(contentID) {
+			problems = append(problems, fmt.Sprintf("has an invalid content ID %q", contentID))
+		}
+	}
+
+	var updates []string
+	problems = append(problems, checkField(fields, "Title Updates", &updates, "a list of strings")...)
+
+	var knownUpdates []map[string]string
+	problems = append(problems, checkField(fields, "Title Updates Known", &knownUpdates, `a list of {"sha1": "name"} objects`)...)
+	for _, knownUpdate := range knownUpdates {
+		for hash := range knownUpdate {
+			if !sha1Pattern.MatchString(hash) {
+				problems = append(problems, fmt.Sprintf("has an invalid known update SHA1 %q", hash))
+			}
+		}
+	}
+
+	var archived []map[string]string
+	problems = append(problems, checkField(fields, "Archived", &archived, `a list of {"content ID": "name"} objects`)...)
+	for _, archivedItem := range archived {
+		for contentID := range archivedItem {
+			if !contentIDPattern.MatchString(contentID) {
+				problems = append(problems, fmt.Sprintf("has an invalid archived content ID %q", contentID))
+			}
+		}
+	}
+
+	var localizedNames map[string]string
+	problems = append(problems, checkField(fields, "Localized Names", &localizedNames, `an object of {"language code": "name"}`)...)
+	for language, localizedName := range localizedNames {
+		if !languagePattern.MatchString(language) {
+			problems = append(problems, fmt.Sprintf("has an invalid language code %q, expected e.g. \"ja\"", language))
+		}
+		if strings.TrimSpace(localizedName) == "" {
+			problems = append(problems, fmt.Sprintf("has an empty %q localized name", language))
+		}
+	}
+
+	var textFields struct {
+		Region, Publisher, Notes string
+	}
+	problems = append(problems, checkField(fields, "Region", &textFields.Region, "a string")...)
+	problems = append(problems, checkField(fields, "Publisher", &textFields.Publisher, "a string")...)
+	problems = append(problems, checkField(fields, "Notes", &textFields.Notes, "a string")...)
+
+	var releaseYear int
+	problems = append(problems, checkField(fields, "Release Year", &releaseYear, "a year")...)
+	if releaseYear != 0 && (releaseYear < 2000 || releaseYear > 2030) {
+		problems = append(problems, fmt.Sprintf("has an implausible 'Release Year' %d", releaseYear))
+	}
+
+	var mediaIDs []string
+	problems = append(problems, checkField(fields, "Media IDs", &mediaIDs, "a list of strings")...)
+
+	var wanted []string
+	problems = append(problems, checkField(fields, "Wanted", &wanted, "a list of strings")...)
+	for _, flag := range wanted {
+		if !contains(wantedFlags, flag) {
+			problems = append(problems, fmt.Sprintf("has an unknown 'Wanted' flag %q, expected one of %s", flag, strings.Join(wantedFlags, ", ")))
+		}
+	}
+
+	for field := range fields {
+		if !knownTitleFields[field] {
+			problems = append(problems, fmt.Sprintf("has an unknown field %q", field))
+		}
+	}
+
+	return problems
+}
+
+var knownTitleFields = map[string]bool{
+	"Title Name":          true,
+	"Content IDs":         true,
+	"Title Updates":       true,
+	"Title Updates Known": true,
+	"Archived":            true,
+	"Localized Names":     true,
+	"Region":              true,
+	"Release Year":        true,
+	"Publisher":           true,
+	"Media IDs":           true,
+	"Notes":               true,
+	"Wanted":              true,
+}
+
+// checkField decodes an optional field, describing the expected type if it
+// doesn't match.
+func checkField(fields map[string]json.RawMessage, name string, v interface{}, expected string) []string {
+	raw, ok := fields[name]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return []string{fmt.Sprintf("'%s' must be %s", name, expected)}
+	}
+	return nil
+}
+
+// describeJSONError adds the line and column to JSON syntax errors.
+func describeJSONError(jsonStr string, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+
+	line, column := lineAndColumn(jsonStr, int(offset))
+	return fmt.Errorf("database is not valid JSON at line %d, column %d: %v", line, column, err)
+}
+
+func lineAndColumn(s string, offset int) (int, int) {
+	if offset > len(s) {
+		offset = len(s)
+	}
+	before := s[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := offset - strings.LastIndex(before, "\n")
+	return line, column
+}
+
+// lineOfKey finds the line an object key is declared on, 0 if not found.
+func lineOfKey(jsonStr string, key string) int {
+	re := regexp.MustCompile(`"` + regexp.QuoteMeta(key) + `"\s*:`)
+	loc := re.FindStringIndex(jsonStr)
+	if loc == nil {
+		return 0
+	}
+	line, _ := lineAndColumn(jsonStr, loc[0])
+	return line
+}
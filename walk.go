@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	symlinksFollow = "follow"
+	symlinksSkip   = "skip"
+)
+
+// symlinkMode decides what happens to symlinks and junctions found in a
+// dump, set with -symlinks.
+var symlinkMode = symlinksFollow
+
+func checkSymlinkMode(mode string) error {
+	switch mode {
+	case symlinksFollow, symlinksSkip:
+		return nil
+	}
+	return fmt.Errorf("invalid -symlinks value %q, expected %q or %q", mode, symlinksFollow, symlinksSkip)
+}
+
+// resolveSymlink returns the entry to use for a directory entry: the entry
+// itself, the link target for a followed link, or nil for a skipped or
+// broken link. Walks below a followed link go through walkDump, which
+// doesn't enter a folder it is already in.
+func resolveSymlink(fsys fs.FS, name string, entry fs.DirEntry) fs.DirEntry {
+	if entry.Type()&fs.ModeSymlink == 0 {
+		return entry
+	}
+	if symlinkMode == symlinksSkip {
+		return nil
+	}
+
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil // broken link
+	}
+	return fs.FileInfoToDirEntry(info)
+}
+
+// walkDump walks the tree at root like fs.WalkDir, with the symlinks and
+// junctions found resolved per symlinkMode, which fs.WalkDir never follows.
+// A followed link to a folder the walk is already in, e.g. a link to a
+// parent, isn't entered, so links can't make it loop.
+func walkDump(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	info, err := fs.Stat(fsys, root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDumpDir(fsys, root, fs.FileInfoToDirEntry(info), nil, fn)
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walkDumpDir walks name, its parents are the folders the walk is in.
+func walkDumpDir(fsys fs.FS, name string, d fs.DirEntry, parents []fs.FileInfo, fn fs.WalkDirFunc) error {
+	if err := fn(name, d, nil); err != nil || !d.IsDir() {
+		if err == fs.SkipDir && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	// Compared by device and inode, or file ID on Windows, a link's target
+	// has the identity of the folder it points to
+	info, err := fs.Stat(fsys, name)
+	if err == nil {
+		for _, parent := range parents {
+			if os.SameFile(parent, info) {
+				return nil
+			}
+		}
+		parents = append(parents, info)
+	}
+
+	entries, err := fs.ReadDir(fsys, name)
+	if err != nil {
+		if err = fn(name, d, err); err != nil {
+			if err == fs.SkipDir {
+				err = nil
+			}
+			return err
+		}
+	}
+	for _, entry := range entries {
+		entryPath := path.Join(name, entry.Name())
+		entry = resolveSymlink(fsys, entryPath, entry)
+		if entry == nil {
+			continue
+		}
+		if err := walkDumpDir(fsys, entryPath, entry, parents, fn); err != nil {
+			if err == fs.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// findEntry looks up name in dir ignoring case, FATX dumps extracted on
+// other systems end up with $C, Tdata and the like. Returns the path with the
+// name as found on disk and the entry, symlinks resolved per symlinkMode.
+func findEntry(fsys fs.FS, dir string, name string) (string, fs.DirEntry, bool) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return "", nil, false
+	}
+	for _, entry := range entries {
+		if !strings.EqualFold(entry.Name(), name) {
+			continue
+		}
+		entryPath := path.Join(dir, entry.Name())
+		if entry := resolveSymlink(fsys, entryPath, entry); entry != nil {
+			return entryPath, entry, true
+		}
+	}
+	return "", nil, false
+}
+
+// findSubDir returns the path of the sub directory name of dir, ignoring case.
+func findSubDir(fsys fs.FS, dir string, name string) (string, bool) {
+	subDir, entry, ok := findEntry(fsys, dir, name)
+	if !ok || !entry.IsDir() {
+		return "", false
+	}
+	return subDir, true
+}
+
+// findFile returns the path of a slash separated file below dir, ignoring
+// the case of every element.
+func findFile(fsys fs.FS, dir string, name string) (string, bool) {
+	elems := strings.Split(name, "/")
+	for _, elem := range elems[:len(elems)-1] {
+		var ok bool
+		if dir, ok = findSubDir(fsys, dir, elem); !ok {
+			return "", false
+		}
+	}
+	filePath, entry, ok := findEntry(fsys, dir, elems[len(elems)-1])
+	if !ok || entry.IsDir() {
+		return "", false
+	}
+	return filePath, true
+}
+
+// tdataSearchDepth is how many folders below the dump a TDATA folder is
+// searched for when it isn't at the root, set with -tdata-depth.
+var tdataSearchDepth = 3
+
+// findTDATA returns the TDATA folder of a dump, ignoring case. Dumps nested
+// in backup folders (dump/Backup/Drive E/TDATA) are searched level by level
+// up to tdataSearchDepth folders down, so the shallowest TDATA wins.
+func findTDATA(fsys fs.FS) (string, bool) {
+	level := []string{"."}
+	for depth := 0; depth <= tdataSearchDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, dir := range level {
+			if tdata, found := findSubDir(fsys, dir, "TDATA"); found {
+				return tdata, true
+			}
+			entries, err := fs.ReadDir(fsys, dir)
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				entryPath := path.Join(dir, entry.Name())
+				if entry := resolveSymlink(fsys, entryPath, entry); entry != nil && entry.IsDir() {
+					next = append(next, entryPath)
+				}
+			}
+		}
+		level = next
+	}
+	return "", false
+}
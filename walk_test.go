@@ -0,0 +1,196 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFindTDATA(t *testing.T) {
+	tests := []struct {
+		name  string
+		fsys  fstest.MapFS
+		want  string
+		found bool
+	}{
+		{"upper case", fstest.MapFS{"TDATA/4d530064/file": {}}, "TDATA", true},
+		{"lower case", fstest.MapFS{"tdata/4d530064/file": {}}, "tdata", true},
+		{"mixed case", fstest.MapFS{"Tdata/4d530064/file": {}}, "Tdata", true},
+		{"nested", fstest.MapFS{"Backup/Drive E/TdAtA/4d530064/file": {}}, "Backup/Drive E/TdAtA", true},
+		{"shallowest wins", fstest.MapFS{"a/b/TDATA/file": {}, "c/tdata/file": {}}, "c/tdata", true},
+		{"too deep", fstest.MapFS{"a/b/c/d/TDATA/file": {}}, "", false},
+		{"file, not folder", fstest.MapFS{"TDATA": {}}, "", false},
+		{"missing", fstest.MapFS{"UDATA/file": {}}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := findTDATA(tt.fsys)
+			if got != tt.want || found != tt.found {
+				t.Errorf("findTDATA() = %q, %v, want %q, %v", got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestFindSubDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"TDATA/4d530064/$C/4d53006400000001/ContentMeta.xbx": {},
+		"TDATA/4d530064/$u/default.xbe":                      {},
+		"TDATA/4d530065/$U/default.xbe":                      {},
+		"TDATA/4d530065/$c":                                  {},
+	}
+	tests := []struct {
+		dir, name string
+		want      string
+		found     bool
+	}{
+		{"TDATA/4d530064", "$c", "TDATA/4d530064/$C", true},
+		{"TDATA/4d530064", "$u", "TDATA/4d530064/$u", true},
+		{"TDATA/4d530065", "$u", "TDATA/4d530065/$U", true},
+		{"TDATA/4d530065", "$c", "", false}, // a file
+		{"TDATA/4d530066", "$c", "", false},
+		{"TDATA", "4D530064", "TDATA/4d530064", true},
+	}
+	for _, tt := range tests {
+		got, found := findSubDir(fsys, tt.dir, tt.name)
+		if got != tt.want || found != tt.found {
+			t.Errorf("findSubDir(%q, %q) = %q, %v, want %q, %v", tt.dir, tt.name, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestFindFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"C/XBOXDASH.XBE":      {},
+		"c/xboxdashdata/FILE": {},
+	}
+	tests := []struct {
+		name  string
+		want  string
+		found bool
+	}{
+		{"C/xboxdash.xbe", "C/XBOXDASH.XBE", true},
+		{"c/XBOXDASH.XBE", "C/XBOXDASH.XBE", true},
+		{"xboxdash.xbe", "", false},
+		{"C/xboxdashdata", "", false}, // a folder
+	}
+	for _, tt := range tests {
+		got, found := findFile(fsys, ".", tt.name)
+		if got != tt.want || found != tt.found {
+			t.Errorf("findFile(%q) = %q, %v, want %q, %v", tt.name, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+// TestMixedCaseTitleFolders checks that title ID folders and their $c and $u
+// folders are found whatever their case, with the title IDs reported in
+// lower case.
+func TestMixedCaseTitleFolders(t *testing.T) {
+	a := newTestApp(t, TitleList{Titles: map[string]TitleData{
+		"4d530064": {TitleName: "Halo 2", ContentIDs: []string{"4d53006400000001"}},
+	}})
+	tests := []struct {
+		name       string
+		fsys       fstest.MapFS
+		wantTitles []string
+		wantKinds  []string
+	}{
+		{"lower case", fstest.MapFS{
+			"TDATA/4d530064/$c/4d53006400000001/contentmeta.xbx": {},
+		}, []string{"4d530064"}, []string{kindDLC}},
+		{"upper case", fstest.MapFS{
+			"TDATA/4D530064/$C/4D53006400000001/CONTENTMETA.XBX": {},
+			"TDATA/4D530064/$U/DEFAULT.XBE":                      {Data: []byte("XBEH")},
+		}, []string{"4d530064"}, []string{kindDLC, kindUpdate}},
+		{"mixed case", fstest.MapFS{
+			"Tdata/4D530064/$u/Default.Xbe": {Data: []byte("XBEH")},
+		}, []string{"4d530064"}, []string{kindUpdate}},
+		{"not a title ID", fstest.MapFS{
+			"TDATA/4d53006/$c/4d53006400000001/ContentMeta.xbx": {},
+		}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tdata, found := findTDATA(tt.fsys)
+			if !found {
+				t.Fatal("TDATA not found")
+			}
+			dump := Dump{FS: tt.fsys, TDATA: tdata, Root: ".", Location: "dump", App: a}
+			events, err := collectEvents(func(events chan<- ScanEvent) error {
+				return checkForContent(dump, []TitleDetector{DLCDetector{}, UpdateDetector{}}, events)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotTitles, gotKinds []string
+			for _, event := range events {
+				switch event.Kind {
+				case EventTitleFound:
+					gotTitles = append(gotTitles, event.TitleID)
+				case EventFinding:
+					gotKinds = append(gotKinds, event.Finding.Kind)
+					if event.TitleID != "4d530064" {
+						t.Errorf("finding title ID = %q, want 4d530064", event.TitleID)
+					}
+				}
+			}
+			if !slices.Equal(gotTitles, tt.wantTitles) {
+				t.Errorf("titles = %v, want %v", gotTitles, tt.wantTitles)
+			}
+			if !slices.Equal(gotKinds, tt.wantKinds) {
+				t.Errorf("findings = %v, want %v", gotKinds, tt.wantKinds)
+			}
+		})
+	}
+}
+
+func TestWalkDumpSymlinks(t *testing.T) {
+	root := t.TempDir()
+	other := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{filepath.Join(root, "a", "b", "file"), filepath.Join(other, "linked")} {
+		if err := os.WriteFile(name, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A link back to a parent and one to a file elsewhere
+	if err := os.Symlink(filepath.Join(root, "a"), filepath.Join(root, "a", "b", "loop")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := os.Symlink(filepath.Join(other, "linked"), filepath.Join(root, "a", "linked")); err != nil {
+		t.Fatal(err)
+	}
+
+	oldMode := symlinkMode
+	t.Cleanup(func() { symlinkMode = oldMode })
+	for _, tt := range []struct {
+		mode string
+		want []string
+	}{
+		{symlinksFollow, []string{"a/b/file", "a/linked"}},
+		{symlinksSkip, []string{"a/b/file"}},
+	} {
+		symlinkMode = tt.mode
+		var files []string
+		err := walkDump(os.DirFS(root), ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(files, tt.want) {
+			t.Errorf("-symlinks=%s walked %v, want %v", tt.mode, files, tt.want)
+		}
+	}
+}
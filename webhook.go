@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	fatihColor "github.com/fatih/color"
+)
+
+// discordMessageLimit is the longest message a Discord webhook accepts.
+const discordMessageLimit = 2000
+
+// WebhookPayload is posted to the webhook after a scan. Content is a summary
+// Discord shows as the message, Report holds the findings picked by the
+// webhookFilter and is left out for Discord, which rejects long messages.
+type WebhookPayload struct {
+	Content string  `json:"content"`
+	Report  *Report `json:"report,omitempty"`
+}
+
+func (a *App) resolveWebhookURL() string {
+	if a.Config.WebhookURL != "" {
+		return a.Config.WebhookURL
+	}
+	return os.Getenv("PINECONE_WEBHOOK")
+}
+
+func isDiscordWebhook(url string) bool {
+	return strings.Contains(url, "discord.com/api/webhooks/") || strings.Contains(url, "discordapp.com/api/webhooks/")
+}
+
+// postScanWebhook posts the results of the last scan to the webhook, if one
+// is set.
+func (a *App) postScanWebhook(settings *Settings) {
+	a.postWebhook(settings, &a.Report)
+}
+
+// postWebhook posts report to the webhook, if one is set, filtered by the
+// webhookFilter. Every post is recorded in the submission log, posts that
+// failed before are retried first.
+func (a *App) postWebhook(settings *Settings, report *Report) {
+	if sent, failed, err := a.retrySubmissions(settings); err != nil {
+		printInfo(fatihColor.FgYellow, "%v\n", err)
+	} else if sent > 0 || failed > 0 {
+		printInfo(fatihColor.FgYellow, "Retried earlier webhook posts: %d sent, %d still failing.\n", sent, failed)
+	}
+
+	url := a.resolveWebhookURL()
+	if url == "" {
+		return
+	}
+	if a.anonymizeEnabled(settings) {
+		report = a.anonymizeReport(report)
+	}
+	filter, err := a.resolveWebhookFilter(settings)
+	if err != nil {
+		printInfo(fatihColor.FgYellow, "%v\n", err)
+		return
+	}
+	report = filter.apply(report)
+
+	// Discord gets the summary split in messages it accepts, other webhooks
+	// the whole summary and the findings in one post
+	var messages []webhookMessage
+	var attached *Report
+	if isDiscordWebhook(url) {
+		messages = webhookMessages(report, discordMessageLimit)
+	} else {
+		messages = []webhookMessage{{Text: strings.Join(webhookSummary(report), "\n"), Keys: submissionHashes(report)}}
+		attached = report
+	}
+	// Every part is posted and logged even if one fails, so the failed ones
+	// are retried rather than the rest never sent
+	failed := 0
+	var postErr error
+	for _, message := range messages {
+		payload := WebhookPayload{Content: message.Text, Report: attached}
+		if err := a.submitWebhook(settings, url, payload, report.ID, message.Keys); err != nil {
+			failed++
+			postErr = err
+		}
+	}
+	if failed > 0 {
+		printInfo(fatihColor.FgYellow, "Could not post %d of %d part(s) of the results to the webhook: %v\n", failed, len(messages), postErr)
+		printInfo(fatihColor.FgYellow, "The failed posts are kept in %s and retried on the next run, or re-send them from the GUI's Submissions tab.\n", a.submissionsPath())
+		return
+	}
+	printLine("Results posted to the webhook.")
+}
+
+// webhookFilter picks the findings posted to the webhook by status and kind,
+// an empty list keeps every status or kind.
+type webhookFilter struct {
+	Statuses []string
+	Kinds    []string
+}
+
+// webhookStatuses are the statuses -webhook-only accepts.
+var webhookStatuses = []string{statusUnknown, statusUnarchived, statusArchived, statusKnownBad}
+
+// parseWebhookStatuses checks a list of statuses to post, like
+// "unknown,unarchived".
+func parseWebhookStatuses(list []string) ([]string, error) {
+	var statuses []string
+	for _, status := range list {
+		status = strings.ToLower(strings.TrimSpace(status))
+		if !contains(webhookStatuses, status) {
+			return nil, fmt.Errorf("Error: unknown webhook status %q, expected %s", status, strings.Join(webhookStatuses, ", "))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// resolveWebhookFilter returns the findings to post: the statuses given with
+// -webhook-only or "webhookStatuses", unknown and unarchived by default, of
+// the kinds in "webhookKinds", every kind by default.
+func (a *App) resolveWebhookFilter(settings *Settings) (webhookFilter, error) {
+	statuses := settings.WebhookStatuses
+	if a.Config.WebhookOnly != "" {
+		statuses = strings.Split(a.Config.WebhookOnly, ",")
+	}
+	filter := webhookFilter{Statuses: []string{statusUnknown, statusUnarchived}, Kinds: settings.WebhookKinds}
+	if len(statuses) > 0 {
+		parsed, err := parseWebhookStatuses(statuses)
+		if err != nil {
+			return filter, err
+		}
+		filter.Statuses = parsed
+	}
+	return filter, nil
+}
+
+func (w webhookFilter) keep(f Finding) bool {
+	return (len(w.Statuses) == 0 || contains(w.Statuses, f.Status)) && (len(w.Kinds) == 0 || contains(w.Kinds, f.Kind))
+}
+
+// apply returns a copy of the report with only the findings to post. Titles
+// missing from the database are kept along with unknown findings.
+func (w webhookFilter) apply(report *Report) *Report {
+	filtered := *report
+	filtered.Findings = nil
+	for _, f := range report.Findings {
+		if w.keep(f) {
+			filtered.Findings = append(filtered.Findings, f)
+		}
+	}
+	if len(w.Statuses) > 0 && !contains(w.Statuses, statusUnknown) {
+		filtered.UnknownTitles = nil
+	}
+	return &filtered
+}
+
+// webhookHeader names the build and scan a post comes from, so the team can
+// trace which Pinecone produced a report.
+func webhookHeader(report *Report) []string {
+	lines := []string{fmt.Sprintf("Pinecone v%s scanned %s on %s: %d unknown, %d unarchived, %d archived",
+		report.Version, report.DumpLocation, report.Created.Format("2006-01-02 15:04:05"),
+		report.Count(statusUnknown), report.Count(statusUnarchived), report.Count(statusArchived))}
+	if fingerprint := report.Fingerprint(); fingerprint != "" {
+		lines = append(lines, "Dump fingerprint: "+fingerprint)
+	}
+	if report.ID != "" {
+		lines = append(lines, fmt.Sprintf("Report ID: %s, %s", report.ID, report.Producer()))
+	}
+	return lines
+}
+
+// webhookGroup is a block of lines of a post, the title and a line per
+// finding. Keys holds the key of each line's finding, see Finding.key, ""
+// for lines that aren't one.
+type webhookGroup struct {
+	Lines []string
+	Keys  []string
+}
+
+func (g *webhookGroup) add(line string, key string) {
+	g.Lines = append(g.Lines, line)
+	g.Keys = append(g.Keys, key)
+}
+
+// webhookGroups are the findings of a report as blocks of lines, one per
+// title. Titles missing from the database come last.
+func webhookGroups(report *Report) []webhookGroup {
+	var groups []webhookGroup
+	for _, title := range report.Titles() {
+		var group webhookGroup
+		group.add(fmt.Sprintf("%s (%s):", title.TitleName, displayTitleID(title.TitleID)), "")
+		for _, f := range title.Findings {
+			group.add(fmt.Sprintf("- %s %s: %s", statusLabel(f.Status), f.Kind, f.Path), f.key())
+		}
+		groups = append(groups, group)
+	}
+	if len(report.UnknownTitles) > 0 {
+		var missing webhookGroup
+		missing.add("Titles missing from the database:", "")
+		for _, u := range report.UnknownTitles {
+			missing.add(fmt.Sprintf("- %s, %s", u.TitleID, u.Path), "")
+		}
+		groups = append(groups, missing)
+	}
+	return groups
+}
+
+// webhookSummary is the header followed by the findings grouped per title.
+func webhookSummary(report *Report) []string {
+	lines := webhookHeader(report)
+	for _, group := range webhookGroups(report) {
+		lines = append(lines, group.Lines...)
+	}
+	return lines
+}
+
+// webhookMessage is a post of the summary, with the keys of the findings it
+// lists for the submission log.
+type webhookMessage struct {
+	Text string
+	Keys []string
+}
+
+// webhookMessages splits the summary in messages of at most limit bytes,
+// keeping each title's findings together where they fit. Every message
+// starts with the header line, so each can be traced back to its scan and
+// build on its own.
+func webhookMessages(report *Report, limit int) []webhookMessage {
+	header := webhookHeader(report)
+	// Room for the " (part 10 of 12)" added to the header line
+	limit -= 20
+	var messages [][]string
+	var keys [][]string
+	current := append([]string{}, header...)
+	currentKeys := []string{}
+	size := len(strings.Join(current, "\n"))
+	next := func() {
+		messages = append(messages, current)
+		keys = append(keys, currentKeys)
+		current = []string{header[0]}
+		currentKeys = []string{}
+		size = len(header[0])
+	}
+	for _, group := range webhookGroups(report) {
+		// Start a new message rather than split a title that would fit in one
+		groupSize := len(strings.Join(group.Lines, "\n"))
+		if size+1+groupSize > limit && len(header[0])+1+groupSize <= limit {
+			next()
+		}
+		for i, line := range group.Lines {
+			if len(line) > limit/2 {
+				line = strings.ToValidUTF8(line[:limit/2], "") + "..."
+			}
+			if size+1+len(line) > limit {
+				next()
+				if i > 0 {
+					current = append(current, group.Lines[0]+" (continued)")
+					size += 1 + len(group.Lines[0]) + len(" (continued)")
+				}
+			}
+			current = append(current, line)
+			size += 1 + len(line)
+			if group.Keys[i] != "" {
+				currentKeys = append(currentKeys, group.Keys[i])
+			}
+		}
+	}
+	messages = append(messages, current)
+	keys = append(keys, currentKeys)
+
+	posts := make([]webhookMessage, len(messages))
+	for i, lines := range messages {
+		if len(messages) > 1 {
+			lines[0] += fmt.Sprintf(" (part %d of %d)", i+1, len(messages))
+		}
+		posts[i] = webhookMessage{Text: strings.Join(lines, "\n"), Keys: keys[i]}
+	}
+	return posts
+}
+
+// postWebhookBody posts a JSON payload to the webhook.
+func postWebhookBody(settings *Settings, url string, body []byte) error {
+	client, err := newHTTPClient(settings)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response from the webhook: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func webhookTestReport() *Report {
+	report := &Report{Version: "1.5.0", DumpLocation: "dump", Created: time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)}
+	for i := 0; i < 40; i++ {
+		titleID := fmt.Sprintf("4d5300%02x", i)
+		for j, status := range []string{statusUnknown, statusUnarchived, statusArchived} {
+			report.Findings = append(report.Findings, Finding{
+				TitleID: titleID, TitleName: fmt.Sprintf("Title %d", i), Kind: kindDLC, Status: status,
+				ContentID: fmt.Sprintf("%s%08x", titleID, j), Path: fmt.Sprintf("%s/$c/%s%08x", titleID, titleID, j),
+			})
+		}
+	}
+	report.UnknownTitles = []UnknownTitle{{TitleID: "ffff0001", Path: "ffff0001"}}
+	return report
+}
+
+func TestWebhookFilter(t *testing.T) {
+	a := &App{}
+	report := webhookTestReport()
+	filter, err := a.resolveWebhookFilter(&Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered := filter.apply(report)
+	if len(filtered.Findings) != 80 || filtered.Count(statusArchived) != 0 || len(filtered.UnknownTitles) != 1 {
+		t.Errorf("default filter kept %d findings and %d unknown titles, want 80 and 1", len(filtered.Findings), len(filtered.UnknownTitles))
+	}
+
+	a.Config.WebhookOnly = "Unarchived"
+	filter, err = a.resolveWebhookFilter(&Settings{WebhookStatuses: []string{statusUnknown}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered = filter.apply(report)
+	if len(filtered.Findings) != 40 || filtered.Count(statusUnarchived) != 40 || len(filtered.UnknownTitles) != 0 {
+		t.Errorf("-webhook-only=Unarchived kept %d findings and %d unknown titles, want only the 40 unarchived", len(filtered.Findings), len(filtered.UnknownTitles))
+	}
+
+	a.Config.WebhookOnly = ""
+	filter, err = a.resolveWebhookFilter(&Settings{WebhookKinds: []string{kindUpdate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filtered = filter.apply(report); len(filtered.Findings) != 0 {
+		t.Errorf("kind filter kept %d DLC, want none", len(filtered.Findings))
+	}
+
+	a.Config.WebhookOnly = "unknown,new"
+	if _, err := a.resolveWebhookFilter(&Settings{}); err == nil {
+		t.Error("unknown status accepted")
+	}
+}
+
+func TestWebhookMessages(t *testing.T) {
+	report := webhookTestReport()
+	messages := webhookMessages(report, discordMessageLimit)
+	if len(messages) < 2 {
+		t.Fatalf("got %d message(s), want the findings split", len(messages))
+	}
+	titles := 0
+	keys := make(map[string]int)
+	for i, post := range messages {
+		message := post.Text
+		if len(message) > discordMessageLimit {
+			t.Errorf("message %d is %d bytes, over the limit", i, len(message))
+		}
+		for _, key := range post.Keys {
+			if !strings.Contains(message, key) {
+				t.Errorf("message %d logs %s without listing it", i, key)
+			}
+			keys[key]++
+		}
+		header := strings.SplitN(message, "\n", 2)[0]
+		if !strings.Contains(header, "Pinecone v1.5.0") || !strings.Contains(header, "2026-10-16 12:30:00") ||
+			!strings.HasSuffix(header, fmt.Sprintf("(part %d of %d)", i+1, len(messages))) {
+			t.Errorf("message %d starts with %q, want the version, time and part", i, header)
+		}
+		titles += strings.Count(message, "Title ")
+		if strings.Contains(message, "(continued)") {
+			t.Errorf("message %d splits a title that fits in one message", i)
+		}
+	}
+	if titles != 40 {
+		t.Errorf("listed %d titles, want each of the 40 once", titles)
+	}
+	if len(keys) != len(report.Findings) {
+		t.Errorf("messages log %d findings, want all %d", len(keys), len(report.Findings))
+	}
+	for key, n := range keys {
+		if n != 1 {
+			t.Errorf("%s logged with %d messages, want 1", key, n)
+		}
+	}
+}
+
+func TestPostScanWebhookLogsEveryPart(t *testing.T) {
+	a := newTestApp(t, TitleList{})
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if posts++; posts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	// Posted as to Discord, in parts
+	a.Config.WebhookURL = server.URL + "/discord.com/api/webhooks/1/token"
+	a.Report = *webhookTestReport()
+
+	a.postScanWebhook(&Settings{})
+	submissions, err := a.loadSubmissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(submissions) < 2 || len(submissions) != posts {
+		t.Fatalf("logged %d submissions of %d posts, want every part", len(submissions), posts)
+	}
+	keys := 0
+	for i, s := range submissions {
+		if want := i == 0; (s.Status == submissionFailed) != want {
+			t.Errorf("submission %d is %s, want only the first failed", i, s.Status)
+		}
+		keys += len(s.Hashes)
+	}
+	if keys != 80 {
+		t.Errorf("submissions log %d findings, want the 80 unknown and unarchived", keys)
+	}
+}
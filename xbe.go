@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	xbeMagic          = "XBEH"
+	xbeMaxHeaderSize  = 0x10000
+	xbeCertTitleName  = 0x0C
+	xbeCertTitleLen   = 40
+	xbeCertMedia      = 0x9C
+	xbeCertRegion     = 0xA0
+	xbeCertVersion    = 0xAC
+	xbeCertMinimumLen = 0xB0
+)
+
+// The entry point at 0x128 is XORed with a key telling retail and debug
+// builds apart, see isDebugXBE.
+const (
+	xbeEntryRetailKey = 0xA8FC57AB
+	xbeEntryDebugKey  = 0x94859D4B
+)
+
+// XBEInfo holds the fields of an XBE header and certificate Pinecone reports on.
+type XBEInfo struct {
+	TitleID   string
+	TitleName string
+	Version   uint32
+	Region    uint32
+	Timestamp time.Time
+	Debug     bool   // built for debug kits, see isDebugXBE
+	Media     uint32 // allowed media flags of the certificate
+	DebugPath string // path the executable was built at
+}
+
+// hasXBEMagic tells whether a file starts with the XBEH magic, whatever its
+// extension.
+func hasXBEMagic(fsys fs.FS, name string) bool {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	magic := make([]byte, len(xbeMagic))
+	if _, err := io.ReadFull(file, magic); err != nil {
+		return false
+	}
+	return string(magic) == xbeMagic
+}
+
+// readXBEInfo parses the header and certificate of an XBE file.
+func readXBEInfo(filePath string) (*XBEInfo, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseXBEInfo(file)
+}
+
+// readXBEInfoFS is readXBEInfo for a file inside a dump.
+func readXBEInfoFS(fsys fs.FS, name string) (*XBEInfo, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseXBEInfo(file)
+}
+
+func parseXBEInfo(r io.Reader) (*XBEInfo, error) {
+	header := make([]byte, 0x178)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("not an XBE: %v", err)
+	}
+	if string(header[:4]) != xbeMagic {
+		return nil, fmt.Errorf("not an XBE: missing XBEH magic")
+	}
+
+	baseAddress := binary.LittleEndian.Uint32(header[0x104:])
+	headerSize := binary.LittleEndian.Uint32(header[0x108:])
+	certAddress := binary.LittleEndian.Uint32(header[0x118:])
+	if headerSize > xbeMaxHeaderSize || headerSize < uint32(len(header)) || certAddress < baseAddress {
+		return nil, fmt.Errorf("not an XBE: invalid header size")
+	}
+
+	// The certificate lives in the headers, read the rest of them.
+	rest := make([]byte, headerSize-uint32(len(header)))
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, fmt.Errorf("truncated XBE header: %v", err)
+	}
+	header = append(header, rest...)
+
+	certOffset := certAddress - baseAddress
+	if uint64(certOffset)+xbeCertMinimumLen > uint64(len(header)) {
+		return nil, fmt.Errorf("XBE certificate is outside the header")
+	}
+	cert := header[certOffset:]
+
+	name := make([]uint16, xbeCertTitleLen)
+	for i := range name {
+		name[i] = binary.LittleEndian.Uint16(cert[xbeCertTitleName+i*2:])
+	}
+
+	return &XBEInfo{
+		TitleID:   fmt.Sprintf("%08x", binary.LittleEndian.Uint32(cert[0x08:])),
+		TitleName: strings.TrimRight(string(utf16.Decode(name)), "\x00"),
+		Version:   binary.LittleEndian.Uint32(cert[xbeCertVersion:]),
+		Region:    binary.LittleEndian.Uint32(cert[xbeCertRegion:]),
+		Timestamp: time.Unix(int64(binary.LittleEndian.Uint32(cert[0x04:])), 0).UTC(),
+		Debug:     isDebugXBE(header),
+		Media:     binary.LittleEndian.Uint32(cert[xbeCertMedia:]),
+		DebugPath: headerString(header, baseAddress, binary.LittleEndian.Uint32(header[0x14C:])),
+	}, nil
+}
+
+// headerString reads the zero terminated string at address from the headers,
+// "" if it's outside them.
+func headerString(header []byte, baseAddress uint32, address uint32) string {
+	if address < baseAddress || uint64(address-baseAddress) >= uint64(len(header)) {
+		return ""
+	}
+	s := header[address-baseAddress:]
+	if end := bytes.IndexByte(s, 0); end >= 0 {
+		s = s[:end]
+	}
+	return string(s)
+}
+
+// isDebugXBE tells whether an XBE was built for debug kits: its entry point
+// only lands inside the image when decoded with the debug key. Debug builds
+// are signed with the devkit key and don't run on retail consoles.
+func isDebugXBE(header []byte) bool {
+	if len(header) < 0x12C {
+		return false
+	}
+	baseAddress := binary.LittleEndian.Uint32(header[0x104:])
+	imageSize := binary.LittleEndian.Uint32(header[0x10C:])
+	entry := binary.LittleEndian.Uint32(header[0x128:])
+	inImage := func(address uint32) bool {
+		return address >= baseAddress && uint64(address) < uint64(baseAddress)+uint64(imageSize)
+	}
+	return inImage(entry^xbeEntryDebugKey) && !inImage(entry^xbeEntryRetailKey)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
+// Signature status of an XBE, see checkXBESignature.
+const (
+	signatureRetail    = "valid retail signature"
+	signatureResigned  = "not signed by Microsoft (resigned)"
+	signaturePatched   = "section digests don't match (patched)"
+	signatureDebug     = "debug build, devkit signed"
+	signatureUnsigned  = "unsigned"
+	signatureUnchecked = "signature not checked, no XBE public key"
+	signatureInvalid   = "invalid XBE headers"
+)
+
+const (
+	xbeSignatureOffset  = 0x04
+	xbeSignatureLen     = 256
+	xbeSectionHeaderLen = 0x38
+	xboxPublicKeyLen    = 284
+)
+
+func (a *App) defaultXBEPublicKeyPath() string {
+	return filepath.Join(a.Config.DataPath, "xbe_public_key.bin")
+}
+
+// loadXBEPublicKey loads the retail XBE public key. Pinecone doesn't ship
+// it, a missing default file only means signatures aren't checked.
+func (a *App) loadXBEPublicKey() error {
+	a.XBEPublicKey = nil
+	path := a.Config.XBEKey
+	if path == "" {
+		path = a.defaultXBEPublicKeyPath()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Error reading XBE public key: %v", err)
+	}
+	a.XBEPublicKey, err = parseXboxPublicKey(data)
+	if err != nil {
+		return fmt.Errorf("Error parsing XBE public key %s: %v", path, err)
+	}
+	return nil
+}
+
+// parseXboxPublicKey parses the kernel's RSA1 public key blob: the magic, the
+// modulus buffer size, the key size in bits, the maximum data length, the
+// public exponent and the little endian modulus.
+func parseXboxPublicKey(data []byte) (*rsa.PublicKey, error) {
+	if len(data) != xboxPublicKeyLen || string(data[:4]) != "RSA1" {
+		return nil, fmt.Errorf("expected a %d byte RSA1 key", xboxPublicKeyLen)
+	}
+	bits := binary.LittleEndian.Uint32(data[8:])
+	exponent := binary.LittleEndian.Uint32(data[16:])
+	modulus := data[20:]
+	if bits == 0 || int(bits/8) > len(modulus) || exponent < 3 {
+		return nil, fmt.Errorf("invalid RSA1 key header")
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(reverseBytes(modulus[:bits/8])),
+		E: int(exponent),
+	}, nil
+}
+
+func reverseBytes(b []byte) []byte {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return reversed
+}
+
+// xboxDigest is the SHA1 the Xbox uses for XBEs: the length of the data as a
+// little endian uint32 followed by the data.
+func xboxDigest(data []byte) []byte {
+	hash := sha1.New()
+	binary.Write(hash, binary.LittleEndian, uint32(len(data)))
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+// checkXBESignature tells whether an XBE is signed with Microsoft's retail
+// key. The headers are signed and hold a digest of every section, so patched
+// executables fail the section digests and resigned ones the signature.
+// Debug builds are signed with the devkit key, which Pinecone doesn't check.
+func (a *App) checkXBESignature(fsys fs.FS, name string) string {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil || len(data) < 0x178 || string(data[:4]) != xbeMagic {
+		return signatureInvalid
+	}
+	baseAddress := binary.LittleEndian.Uint32(data[0x104:])
+	headerSize := binary.LittleEndian.Uint32(data[0x108:])
+	sectionCount := binary.LittleEndian.Uint32(data[0x11C:])
+	sectionAddress := binary.LittleEndian.Uint32(data[0x120:])
+	if headerSize > xbeMaxHeaderSize || int(headerSize) > len(data) || sectionAddress < baseAddress {
+		return signatureInvalid
+	}
+	signature := data[xbeSignatureOffset : xbeSignatureOffset+xbeSignatureLen]
+	if bytes.Count(signature, []byte{0}) == len(signature) {
+		return signatureUnsigned
+	}
+
+	sections := uint64(sectionAddress - baseAddress)
+	if sections+uint64(sectionCount)*xbeSectionHeaderLen > uint64(headerSize) {
+		return signatureInvalid
+	}
+	for i := uint64(0); i < uint64(sectionCount); i++ {
+		section := data[sections+i*xbeSectionHeaderLen:]
+		rawAddress := uint64(binary.LittleEndian.Uint32(section[0x0C:]))
+		rawSize := uint64(binary.LittleEndian.Uint32(section[0x10:]))
+		if rawAddress+rawSize > uint64(len(data)) {
+			return signaturePatched
+		}
+		if !bytes.Equal(xboxDigest(data[rawAddress:rawAddress+rawSize]), section[0x24:0x38]) {
+			return signaturePatched
+		}
+	}
+
+	if isDebugXBE(data) {
+		return signatureDebug
+	}
+	if a.XBEPublicKey == nil {
+		return signatureUnchecked
+	}
+	if verifyXboxSignature(a.XBEPublicKey, signature, xboxDigest(data[0x104:headerSize])) {
+		return signatureRetail
+	}
+	return signatureResigned
+}
+
+// verifyXboxSignature checks a little endian RSA signature whose decrypted
+// block is, in memory order, the digest, a zero byte, 0xff padding and 0x01
+// 0x00.
+func verifyXboxSignature(key *rsa.PublicKey, signature []byte, digest []byte) bool {
+	size := key.Size()
+	if len(signature) < size {
+		return false
+	}
+	s := new(big.Int).SetBytes(reverseBytes(signature[:size]))
+	if s.Cmp(key.N) >= 0 {
+		return false
+	}
+	m := new(big.Int).Exp(s, big.NewInt(int64(key.E)), key.N)
+	block := reverseBytes(m.FillBytes(make([]byte, size)))
+
+	if !bytes.Equal(block[:len(digest)], digest) || block[len(digest)] != 0 {
+		return false
+	}
+	for _, b := range block[len(digest)+1 : size-2] {
+		if b != 0xff {
+			return false
+		}
+	}
+	return block[size-2] == 0x01 && block[size-1] == 0x00
+}